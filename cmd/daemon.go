@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jackchuka/gv/internal/cache"
+	"github.com/jackchuka/gv/internal/daemon"
+	"github.com/jackchuka/gv/internal/metrics"
+	"github.com/jackchuka/gv/internal/notify"
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Keep repo statuses fresh in the background and serve them over a local socket",
+	Long: `Run the scanner, status reader and watcher continuously and expose the
+shared cache on a Unix domain socket. While the daemon runs, gv, gv status
+and gv prompt read from it instead of starting their own git processes.`,
+	RunE: runDaemon,
+}
+
+func init() {
+	daemonCmd.Flags().Duration("rescan", 5*time.Minute, "interval between full rescans of scan paths (0 disables)")
+	daemonCmd.Flags().String("metrics", "", "also serve OpenMetrics at http://<addr>/metrics (e.g. 127.0.0.1:9723)")
+	rootCmd.AddCommand(daemonCmd)
+}
+
+func runDaemon(cmd *cobra.Command, args []string) error {
+	if !cfg.HasSources() {
+		return errors.New("no scan paths configured")
+	}
+
+	rescan, _ := cmd.Flags().GetDuration("rescan")
+	metricsAddr, _ := cmd.Flags().GetString("metrics")
+
+	notifier, err := notify.New(cfg.Notifications)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	socket := cfg.SocketPath()
+	svc := daemon.NewService(cfg, notifier)
+	svc.PersistTo(cache.DefaultPath())
+	srv := daemon.NewServer(svc)
+	srv.Handle("GET /metrics", metrics.Handler(svc))
+
+	errc := make(chan error, 3)
+	go func() { errc <- srv.ListenAndServe(ctx, socket) }()
+	go func() { errc <- svc.Run(ctx, rescan) }()
+	if metricsAddr != "" {
+		go func() { errc <- metrics.ListenAndServe(ctx, metricsAddr, svc) }()
+		log.Printf("gv metrics on http://%s/metrics", metricsAddr)
+	}
+
+	log.Printf("gv daemon listening on %s", socket)
+
+	// Either side failing brings the daemon down
+	err = <-errc
+	stop()
+	if err != nil {
+		return fmt.Errorf("daemon: %w", err)
+	}
+	return <-errc
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/jackchuka/gv/internal/config"
+	"github.com/jackchuka/gv/internal/theme"
+	"github.com/jackchuka/gv/tui"
 )
 
 var initCmd = &cobra.Command{
@@ -34,13 +36,21 @@ const (
 	stepDone
 )
 
+// Styles, set from the theme by setInitTheme
 var (
-	styleInitTitle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("73"))
-	styleInitSuccess = lipgloss.NewStyle().Foreground(lipgloss.Color("71"))
-	styleInitWarn    = lipgloss.NewStyle().Foreground(lipgloss.Color("179"))
-	styleInitDim     = lipgloss.NewStyle().Foreground(lipgloss.Color("242"))
+	styleInitTitle   lipgloss.Style
+	styleInitSuccess lipgloss.Style
+	styleInitWarn    lipgloss.Style
+	styleInitDim     lipgloss.Style
 )
 
+func setInitTheme(t theme.Theme) {
+	styleInitTitle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(t[theme.Accent]))
+	styleInitSuccess = lipgloss.NewStyle().Foreground(lipgloss.Color(t[theme.Clean]))
+	styleInitWarn = lipgloss.NewStyle().Foreground(lipgloss.Color(t[theme.Dirty]))
+	styleInitDim = lipgloss.NewStyle().Foreground(lipgloss.Color(t[theme.Dim]))
+}
+
 type initModel struct {
 	step         initStep
 	input        textinput.Model
@@ -62,6 +72,12 @@ func runInit(cmd *cobra.Command, args []string) error {
 	_, err := os.Stat(configPath)
 	configExists := err == nil
 
+	t, err := tui.ResolveTheme(cfg)
+	if err != nil {
+		return err
+	}
+	setInitTheme(t)
+
 	ti := textinput.New()
 	ti.Placeholder = "~/ghq/github.com"
 	ti.CharLimit = 256
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jackchuka/gv/internal/daemon"
+	"github.com/jackchuka/gv/internal/metrics"
+)
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Serve repo health as OpenMetrics for Prometheus",
+	Long: `Scan and watch the configured repos and serve per-repo gauges (dirty and
+staged files, ahead/behind, stashes, conflict state, last commit age) plus
+totals and scan/poll durations at http://<listen>/metrics.
+
+When gv daemon is already running, prefer gv daemon --metrics so the repos
+are only polled once.`,
+	RunE: runMetrics,
+}
+
+func init() {
+	metricsCmd.Flags().String("listen", "127.0.0.1:9723", "address to serve /metrics on")
+	metricsCmd.Flags().Duration("rescan", 5*time.Minute, "interval between full rescans of scan paths (0 disables)")
+	rootCmd.AddCommand(metricsCmd)
+}
+
+func runMetrics(cmd *cobra.Command, args []string) error {
+	if !cfg.HasSources() {
+		return errors.New("no scan paths configured")
+	}
+
+	addr, _ := cmd.Flags().GetString("listen")
+	rescan, _ := cmd.Flags().GetDuration("rescan")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Notifications stay with the daemon and the TUI
+	svc := daemon.NewService(cfg, nil)
+
+	errc := make(chan error, 2)
+	go func() { errc <- metrics.ListenAndServe(ctx, addr, svc) }()
+	go func() { errc <- svc.Run(ctx, rescan) }()
+
+	log.Printf("gv metrics on http://%s/metrics", addr)
+
+	err := <-errc
+	stop()
+	if err != nil {
+		return fmt.Errorf("metrics: %w", err)
+	}
+	return <-errc
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jackchuka/gv/internal/cache"
+	"github.com/jackchuka/gv/internal/prompt"
+	"github.com/jackchuka/gv/internal/scanner"
+	"github.com/jackchuka/gv/internal/status"
+)
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt [path]",
+	Short: "Print a compact status segment for shell prompts",
+	Long: `Print a one-line summary of the repo enclosing path (default: the current
+directory), formatted with a Go template. Statuses are served from a running
+daemon or the persisted cache when fresh, falling back to git within the
+latency budget. Prints nothing outside a repo.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPrompt,
+}
+
+func init() {
+	promptCmd.Flags().String("format", "", "Go template over the prompt fields (default from config)")
+	promptCmd.Flags().Duration("timeout", 0, "latency budget (default from config, or 200ms)")
+	rootCmd.AddCommand(promptCmd)
+}
+
+func runPrompt(cmd *cobra.Command, args []string) error {
+	path := "."
+	if len(args) == 1 {
+		path = args[0]
+	}
+
+	format := cfg.Prompt.Format
+	if f, _ := cmd.Flags().GetString("format"); f != "" {
+		format = f
+	}
+	timeout := cfg.Prompt.Timeout
+	if t, _ := cmd.Flags().GetDuration("timeout"); t > 0 {
+		timeout = t
+	}
+	if timeout <= 0 {
+		timeout = 200 * time.Millisecond
+	}
+	maxAge := cfg.Prompt.MaxAge
+	if maxAge <= 0 {
+		maxAge = cfg.PollInterval
+	}
+
+	repo, err := scanner.FindRepo(path)
+	if err != nil || repo == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cachePath := cache.DefaultPath()
+	found, source := prompt.Resolve(ctx, repo.Path,
+		prompt.DaemonSource{SocketPath: cfg.SocketPath()},
+		prompt.CacheSource{Path: cachePath, MaxAge: maxAge},
+		prompt.GitSource{Reader: status.NewGitReader()},
+	)
+	if found == nil {
+		// Out of budget: a stale answer beats an empty prompt
+		found, source = prompt.Resolve(context.Background(), repo.Path, prompt.CacheSource{Path: cachePath})
+	}
+	if found == nil {
+		return nil
+	}
+
+	out, err := prompt.Render(format, prompt.NewData(found, source))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(os.Stdout, out)
+	return err
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jackchuka/gv/internal/report"
+	"github.com/jackchuka/gv/internal/status"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Print an activity report for standups or a journal",
+	Long: `Summarize recent work across all repos: commits and lines changed per repo,
+the most touched files, repos left dirty, unpushed branches and open stashes.`,
+	Example: `  gv report --since 1d
+  gv report --since 7d --author "$(git config user.email)" --format html > week.html`,
+	RunE: runReport,
+}
+
+func init() {
+	reportCmd.Flags().String("since", "7d", "period to cover: 1d, 2w, 36h or a date (2006-01-02)")
+	reportCmd.Flags().String("format", "md", "output format: md, html or json")
+	reportCmd.Flags().String("author", "", "only count commits whose author matches this pattern")
+	rootCmd.AddCommand(reportCmd)
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	format, _ := cmd.Flags().GetString("format")
+	author, _ := cmd.Flags().GetString("author")
+
+	if !slices.Contains(report.Formats, format) {
+		return fmt.Errorf("unknown format %q (want %s)", format, strings.Join(report.Formats, ", "))
+	}
+
+	now := time.Now()
+	since, err := report.ParseSince(sinceFlag, now)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Minute)
+	defer cancel()
+
+	repos, _, err := loadRepoStatuses(ctx)
+	if err != nil {
+		return err
+	}
+	paths := make([]string, len(repos))
+	for i, r := range repos {
+		paths[i] = r.Path
+	}
+	activity := status.NewGitReader().GetActivityBatch(ctx, paths, status.ActivityOptions{Since: since, Author: author})
+
+	return report.Write(os.Stdout, report.Build(repos, activity, since, now), format)
+}
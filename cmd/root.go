@@ -28,7 +28,7 @@ var rootCmd = &cobra.Command{
   and worktrees. Auto-discovers git repos under configured
   paths and shows their status in real-time.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(cfg.ScanPaths) == 0 {
+		if !cfg.HasSources() {
 			fmt.Fprintf(os.Stderr, "No scan paths configured.\n")
 			fmt.Fprintf(os.Stderr, "Run 'gv init' to set up, or add paths to %s\n", cfgFile)
 			return nil
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jackchuka/gv/internal/daemon"
+	"github.com/jackchuka/gv/internal/model"
+	"github.com/jackchuka/gv/internal/scanner"
+	"github.com/jackchuka/gv/internal/status"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Print the status of all repos",
+	RunE:  runStatus,
+}
+
+func init() {
+	statusCmd.Flags().Bool("json", false, "output JSON")
+	statusCmd.Flags().BoolP("verbose", "v", false, "report what the scan skipped or could not read")
+	rootCmd.AddCommand(statusCmd)
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	asJSON, _ := cmd.Flags().GetBool("json")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), time.Minute)
+	defer cancel()
+
+	repos, scan, err := loadRepoStatuses(ctx)
+	if err != nil {
+		return err
+	}
+	sort.Slice(repos, func(i, j int) bool { return repos[i].Path < repos[j].Path })
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if verbose {
+			report := *scan
+			report.Repos = nil
+			return enc.Encode(struct {
+				Repos []model.Repository  `json:"repos"`
+				Scan  *scanner.ScanResult `json:"scan"`
+			}{repos, &report})
+		}
+		return enc.Encode(repos)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "REPO\tBRANCH\tSYNC\tCHANGES\tPATH")
+	for _, r := range repos {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			r.DisplayName(), statusBranch(r.Status), statusSync(r.Status), statusChanges(r.Status), r.Path)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if verbose {
+		printScanReport(os.Stdout, scan)
+	}
+	return nil
+}
+
+// printScanReport summarizes a scan and lists everything it skipped or
+// could not read.
+func printScanReport(w io.Writer, scan *scanner.ScanResult) {
+	_, _ = fmt.Fprintf(w, "\nScanned %d directories in %s\n", scan.Dirs, scan.Duration.Round(time.Millisecond))
+
+	if len(scan.Skipped) > 0 {
+		_, _ = fmt.Fprintf(w, "\nSkipped (%d):\n", len(scan.Skipped))
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, s := range scan.Skipped {
+			_, _ = fmt.Fprintf(tw, "  %s\t%s\n", s.Reason, s.Path)
+		}
+		_ = tw.Flush()
+	}
+
+	if len(scan.Errors) > 0 {
+		_, _ = fmt.Fprintf(w, "\nErrors (%d):\n", len(scan.Errors))
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, e := range scan.Errors {
+			_, _ = fmt.Fprintf(tw, "  %s\t%s\t%s\n", e.Kind, e.Path, e.Message)
+		}
+		_ = tw.Flush()
+	}
+}
+
+// loadRepoStatuses reads repos from a running daemon, or scans and reads
+// statuses directly when none is running. The scan report comes from the
+// daemon's last scan in the first case.
+func loadRepoStatuses(ctx context.Context) ([]model.Repository, *scanner.ScanResult, error) {
+	connectCtx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	client, err := daemon.Connect(connectCtx, cfg.SocketPath())
+	cancel()
+	if err == nil {
+		defer client.CloseIdleConnections()
+		repos, err := client.Repos(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		scan, err := client.ScanReport(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return repos, scan, nil
+	}
+
+	if !cfg.HasSources() {
+		return nil, nil, fmt.Errorf("no scan paths configured; run 'gv init' or add paths to %s", cfgFile)
+	}
+
+	scan, err := scanner.NewWalker(cfg).ScanStream(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	repos := scan.Repos
+	paths := make([]string, len(repos))
+	for i, r := range repos {
+		paths[i] = r.Path
+	}
+	statuses, _ := status.NewGitReader().GetStatusBatch(ctx, paths)
+	for i := range repos {
+		repos[i].Status = statuses[repos[i].Path]
+	}
+	return repos, scan, nil
+}
+
+func statusBranch(s *model.RepoStatus) string {
+	switch {
+	case s == nil:
+		return "?"
+	case s.Branch != "":
+		return s.Branch
+	case s.DetachedHead:
+		return "(" + s.CommitHash + ")"
+	default:
+		return "?"
+	}
+}
+
+func statusSync(s *model.RepoStatus) string {
+	if s == nil {
+		return ""
+	}
+	if s.HasSpecialState() {
+		return s.SpecialState()
+	}
+	out := ""
+	if s.Ahead > 0 {
+		out += fmt.Sprintf("↑%d", s.Ahead)
+	}
+	if s.Behind > 0 {
+		if out != "" {
+			out += " "
+		}
+		out += fmt.Sprintf("↓%d", s.Behind)
+	}
+	if out == "" {
+		out = "-"
+	}
+	return out
+}
+
+func statusChanges(s *model.RepoStatus) string {
+	if s == nil || !s.IsDirty() {
+		return "-"
+	}
+	return fmt.Sprintf("+%d ~%d ?%d", s.Staged, s.Modified, s.Untracked)
+}
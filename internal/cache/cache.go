@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jackchuka/gv/internal/model"
+)
+
+// Snapshot is the persisted state of every known repo.
+type Snapshot struct {
+	SavedAt time.Time          `json:"saved_at"`
+	Repos   []model.Repository `json:"repos"`
+}
+
+// Find returns the cached repo at path, or nil.
+func (s *Snapshot) Find(path string) *model.Repository {
+	for i := range s.Repos {
+		if s.Repos[i].Path == path {
+			return &s.Repos[i]
+		}
+	}
+	return nil
+}
+
+func DefaultPath() string {
+	if xdg := os.Getenv("XDG_CACHE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "gv", "repos.json")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".cache", "gv", "repos.json")
+}
+
+// Load reads a snapshot from path.
+func Load(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Save writes repos to path atomically, so concurrent readers such as
+// shell prompts never see a partial file.
+func Save(path string, repos []model.Repository) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(Snapshot{SavedAt: time.Now(), Repos: repos})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".repos-*.json")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
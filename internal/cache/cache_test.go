@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackchuka/gv/internal/model"
+)
+
+func TestSaveAndLoad_Roundtrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "repos.json")
+
+	repos := []model.Repository{
+		{Path: "/code/a", Status: &model.RepoStatus{Branch: "main", Ahead: 2}},
+		{Path: "/code/b"},
+	}
+	if err := Save(path, repos); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	snap, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if snap.SavedAt.IsZero() {
+		t.Error("SavedAt should be set")
+	}
+	if len(snap.Repos) != 2 {
+		t.Fatalf("got %d repos, want 2", len(snap.Repos))
+	}
+
+	a := snap.Find("/code/a")
+	if a == nil || a.Status == nil || a.Status.Branch != "main" || a.Status.Ahead != 2 {
+		t.Errorf("Find(/code/a) = %+v", a)
+	}
+	if snap.Find("/code/missing") != nil {
+		t.Error("Find() should return nil for unknown paths")
+	}
+
+	// No temp files are left behind
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("cache dir has %d entries, want 1", len(entries))
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "/custom/cache")
+	if got, want := DefaultPath(), "/custom/cache/gv/repos.json"; got != want {
+		t.Errorf("DefaultPath() = %q, want %q", got, want)
+	}
+}
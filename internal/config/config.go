@@ -2,8 +2,14 @@
 package config
 
 import (
-	"path/filepath"
 	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jackchuka/gv/internal/forge"
+	"github.com/jackchuka/gv/internal/ignore"
+	"github.com/jackchuka/gv/internal/keymap"
+	"github.com/jackchuka/gv/internal/theme"
 )
 
 type Config struct {
@@ -11,10 +17,133 @@ type Config struct {
 	ScanPaths      []string `yaml:"scan_paths"`
 	IgnorePatterns []string `yaml:"ignore_patterns"`
 	MaxDepth       int      `yaml:"max_depth"`
+	Submodules     bool     `yaml:"submodules"`                // list initialized submodules under their superproject
+	FollowSymlinks bool     `yaml:"follow_symlinks,omitempty"` // descend into symlinked directories
+	OneFilesystem  bool     `yaml:"one_filesystem,omitempty"`  // don't cross into other mounts
+
+	// Repos listed explicitly, or settings for discovered ones
+	Repos []RepoConfig `yaml:"repos,omitempty"`
+
+	// Saved filter queries for the TUI
+	Views []ViewConfig `yaml:"views,omitempty"`
+
+	// Shell commands for the command palette
+	Commands []CommandConfig `yaml:"commands,omitempty"`
+
+	// Web UIs and APIs of self-hosted git servers, by remote host
+	Forges map[string]forge.Def `yaml:"forges,omitempty"`
+
+	// PullRequests: look up each branch's pull request and CI status on
+	// its forge's API. Off keeps gv from making network requests for them.
+	PullRequests bool `yaml:"pull_requests"`
+
+	// Mouse: click to select and sort, wheel to scroll. Off leaves the
+	// terminal's own text selection working.
+	Mouse bool `yaml:"mouse"`
+
+	// Keybindings: action -> keys, replacing the action's default keys
+	Keys map[string]keymap.Keys `yaml:"keys,omitempty"`
+
+	// Colors
+	Theme  string               `yaml:"theme,omitempty"`  // auto (default), dark, light, high-contrast, colorblind, or a name from themes
+	Themes map[string]theme.Def `yaml:"themes,omitempty"` // custom themes
+	Colors map[string]string    `yaml:"colors,omitempty"` // role -> color, overriding the theme's
+
+	// Table column presets for the TUI, cycled with a key; the first is used at start
+	Columns []ColumnPreset    `yaml:"columns,omitempty"`
+	Aliases map[string]string `yaml:"aliases,omitempty"` // name -> git arguments, shown by alias:<name> columns
 
 	// Watcher
 	PollInterval time.Duration `yaml:"poll_interval"`
 	AutoRefresh  bool          `yaml:"auto_refresh"`
+
+	// Notifications
+	Notifications []NotifyRule `yaml:"notifications,omitempty"`
+
+	// Daemon
+	DaemonSocket string `yaml:"daemon_socket,omitempty"` // default: DefaultSocketPath()
+
+	// Shell prompt segment
+	Prompt PromptConfig `yaml:"prompt,omitempty"`
+}
+
+// PromptConfig controls `gv prompt`. Zero values fall back to defaults.
+type PromptConfig struct {
+	Format  string        `yaml:"format,omitempty"`  // text/template over prompt.Data
+	Timeout time.Duration `yaml:"timeout,omitempty"` // total latency budget
+	MaxAge  time.Duration `yaml:"max_age,omitempty"` // oldest cache entry served before asking git
+}
+
+// RepoConfig adds a repo outside the scan paths, or overrides settings of
+// a discovered one. Entries match repos by canonical path.
+type RepoConfig struct {
+	Path          string        `yaml:"path"`
+	Name          string        `yaml:"name,omitempty"`           // display name
+	Group         string        `yaml:"group,omitempty"`          // e.g. work, oss
+	Tags          []string      `yaml:"tags,omitempty"`           // free-form labels
+	Pinned        bool          `yaml:"pinned,omitempty"`         // listed first
+	PollInterval  time.Duration `yaml:"poll_interval,omitempty"`  // overrides the global poll_interval
+	FetchDisabled bool          `yaml:"fetch_disabled,omitempty"` // never fetch, e.g. slow or metered remotes
+	Remote        string        `yaml:"remote,omitempty"`         // remote to fetch and derive the owner from; default origin
+}
+
+// ViewConfig names a filter query so it can be picked like the built-in
+// views, optionally with its own key.
+type ViewConfig struct {
+	Name  string `yaml:"name"`
+	Query string `yaml:"query"`         // see internal/query
+	Key   string `yaml:"key,omitempty"` // single key that selects the view
+}
+
+// CommandConfig is a shell command run from the command palette in the
+// selected repo's directory, or in each repo listed.
+type CommandConfig struct {
+	Name        string `yaml:"name"`
+	Run         string `yaml:"run"`                   // run with sh -c; GV_REPO and GV_REPO_NAME are set
+	All         bool   `yaml:"all,omitempty"`         // run in every repo listed instead of the selected one
+	Interactive bool   `yaml:"interactive,omitempty"` // hand the terminal over, e.g. for tig
+}
+
+// ColumnPreset is a named list of table columns, in display order.
+type ColumnPreset struct {
+	Name    string         `yaml:"name"`
+	Columns []ColumnConfig `yaml:"columns"`
+}
+
+// ColumnConfig is one table column. In YAML it is either a bare column
+// name or a mapping that also bounds its width.
+type ColumnConfig struct {
+	Name string `yaml:"name"`          // one of ColumnNames, or alias:<name>
+	Min  int    `yaml:"min,omitempty"` // width in cells; 0 for the column's default
+	Max  int    `yaml:"max,omitempty"` // 0 for no limit
+}
+
+// ColumnNames lists the built-in table columns.
+var ColumnNames = []string{
+	"repo", "branch", "sync", "changes", "diff",
+	"age", "owner", "stashes", "remote", "upstream", "fork", "pr", "head", "tag", "week",
+}
+
+// AliasColumnPrefix marks a column showing the output of an alias.
+const AliasColumnPrefix = "alias:"
+
+func (c *ColumnConfig) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*c = ColumnConfig{Name: value.Value}
+		return nil
+	}
+	type plain ColumnConfig // without this method
+	return value.Decode((*plain)(c))
+}
+
+// NotifyRule routes repo state transitions to a notification sink.
+type NotifyRule struct {
+	Events   []string          `yaml:"events,omitempty"`   // transition kinds; empty matches all
+	Sink     string            `yaml:"sink"`               // desktop, webhook or command
+	URL      string            `yaml:"url,omitempty"`      // webhook endpoint
+	Headers  map[string]string `yaml:"headers,omitempty"`  // extra webhook request headers
+	Command  string            `yaml:"command,omitempty"`  // shell command for the command sink
+	Throttle time.Duration     `yaml:"throttle,omitempty"` // minimum gap between repeats per repo and event
 }
 
 func NewConfig() *Config {
@@ -35,44 +164,29 @@ func NewConfig() *Config {
 			"**/dist/**",
 		},
 		MaxDepth:     10,
+		Submodules:   true,
 		PollInterval: 5 * time.Second,
 		AutoRefresh:  true,
+		Mouse:        true,
+		PullRequests: true,
 	}
 }
 
-func (c *Config) ShouldIgnore(path string) bool {
-	for _, pattern := range c.IgnorePatterns {
-		matched, err := filepath.Match(pattern, path)
-		if err == nil && matched {
-			return true
-		}
-		// Try matching against each path segment for ** patterns
-		if containsDoublestar(pattern) {
-			if matchDoublestar(pattern, path) {
-				return true
-			}
-		}
-	}
-	return false
+// HasSources reports whether there is anything to look at: scan paths or
+// explicitly listed repos.
+func (c *Config) HasSources() bool {
+	return len(c.ScanPaths) > 0 || len(c.Repos) > 0
 }
 
-func containsDoublestar(pattern string) bool {
-	for i := 0; i < len(pattern)-1; i++ {
-		if pattern[i] == '*' && pattern[i+1] == '*' {
-			return true
-		}
-	}
-	return false
+// ShouldIgnore reports whether path matches IgnorePatterns. Patterns
+// anchored to a scan root are evaluated against path as given; use
+// IgnoreMatcher to match relative to a root.
+func (c *Config) ShouldIgnore(path string) bool {
+	return c.IgnoreMatcher().Match("", path)
 }
 
-func matchDoublestar(pattern, path string) bool {
-	// Simple implementation: check if path contains the middle part
-	// e.g., "**/node_modules/**" matches if path contains "/node_modules/"
-	if len(pattern) < 5 {
-		return false
-	}
-	// Extract middle part between **/ and /**
-	middle := pattern[3 : len(pattern)-3]
-	return filepath.Base(filepath.Dir(path)) == middle ||
-		filepath.Base(path) == middle
+// IgnoreMatcher compiles IgnorePatterns, skipping invalid ones.
+func (c *Config) IgnoreMatcher() *ignore.Matcher {
+	m, _ := ignore.New(c.IgnorePatterns)
+	return m
 }
@@ -4,8 +4,12 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/jackchuka/gv/internal/forge"
 )
 
 func TestNewConfig_Defaults(t *testing.T) {
@@ -199,6 +203,304 @@ func TestShouldIgnore_AdditionalCases(t *testing.T) {
 	}
 }
 
+func TestShouldIgnore_DefaultPatterns(t *testing.T) {
+	cfg := NewConfig()
+
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"/home/user/code/app/node_modules", true},
+		{"/home/user/code/app/node_modules/pkg", true},
+		{"/home/user/code/app/vendor", true},
+		{"/home/user/code/app/.venv/lib", true},
+		{"/home/user/code/app/target/debug", true},
+		{"/home/user/code/app/src", false},
+		{"/home/user/code/app/vendored", false},
+		{"/home/user/code/builder", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := cfg.ShouldIgnore(tt.path); got != tt.expected {
+				t.Errorf("ShouldIgnore(%q) = %v, want %v", tt.path, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoad_RejectsInvalidIgnorePattern(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := []byte("ignore_patterns:\n  - \"[unclosed\"\n")
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(configPath); err == nil {
+		t.Error("Load() should reject an invalid ignore pattern")
+	}
+}
+
+func TestLoad_ParsesRepos(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := []byte(`
+repos:
+  - path: ~/work/api
+    name: api
+    group: work
+    tags: [go, backend]
+    pinned: true
+    poll_interval: 30s
+    fetch_disabled: true
+    remote: upstream
+`)
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.HasSources() {
+		t.Error("HasSources() = false with repos listed")
+	}
+	if len(cfg.Repos) != 1 {
+		t.Fatalf("Repos length = %d, want 1", len(cfg.Repos))
+	}
+
+	home, _ := os.UserHomeDir()
+	want := RepoConfig{
+		Path:          filepath.Join(home, "work/api"),
+		Name:          "api",
+		Group:         "work",
+		Tags:          []string{"go", "backend"},
+		Pinned:        true,
+		PollInterval:  30 * time.Second,
+		FetchDisabled: true,
+		Remote:        "upstream",
+	}
+	if got := cfg.Repos[0]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Repos[0] = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoad_RejectsRepoWithoutPath(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := []byte("repos:\n  - name: nowhere\n")
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(configPath); err == nil {
+		t.Error("Load() should reject a repo without a path")
+	}
+}
+
+func TestLoad_ValidatesViews(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := []byte(`
+views:
+  - name: stale
+    query: age>30d -pinned
+    key: "7"
+`)
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.Views) != 1 || cfg.Views[0].Key != "7" {
+		t.Errorf("Views = %+v", cfg.Views)
+	}
+
+	content = []byte("views:\n  - name: broken\n    query: age>soon\n")
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(configPath); err == nil {
+		t.Error("Load() should reject a view with an invalid query")
+	}
+}
+
+func TestLoad_ValidatesCommands(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := []byte(`
+commands:
+  - name: pull
+    run: git pull --ff-only
+    all: true
+  - name: tig
+    run: tig
+    interactive: true
+`)
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.Commands) != 2 || !cfg.Commands[0].All || !cfg.Commands[1].Interactive {
+		t.Errorf("Commands = %+v", cfg.Commands)
+	}
+
+	for _, bad := range []string{
+		"commands:\n  - name: empty\n",
+		"commands:\n  - run: ls\n",
+		"commands:\n  - name: tig\n    run: tig\n    all: true\n    interactive: true\n",
+	} {
+		if err := os.WriteFile(configPath, []byte(bad), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(configPath); err == nil {
+			t.Errorf("Load(%q) should fail", bad)
+		}
+	}
+}
+
+func TestLoad_ParsesForges(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := []byte(`
+forges:
+  git.corp.example:
+    type: gitlab
+    url: https://code.corp.example
+    commit: "{base}/{owner}/{repo}/-/commit/{commit}?view=parallel"
+`)
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	f := cfg.Forges["git.corp.example"]
+	if f.Type != forge.GitLab || f.URL != "https://code.corp.example" || !strings.HasSuffix(f.Commit, "?view=parallel") {
+		t.Errorf("Forges = %+v", cfg.Forges)
+	}
+
+	bad := "forges:\n  git.corp.example:\n    type: sourcehut\n"
+	if err := os.WriteFile(configPath, []byte(bad), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(configPath); err == nil {
+		t.Errorf("Load(%q) should fail", bad)
+	}
+}
+
+func TestLoad_ParsesColumns(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := []byte(`
+aliases:
+  author: log -1 --format=%an
+columns:
+  - name: narrow
+    columns: [repo, branch, sync]
+  - name: wide
+    columns:
+      - repo
+      - {name: branch, min: 12, max: 30}
+      - alias:author
+`)
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.Columns) != 2 || len(cfg.Columns[0].Columns) != 3 {
+		t.Fatalf("Columns = %+v", cfg.Columns)
+	}
+	if got, want := cfg.Columns[1].Columns[1], (ColumnConfig{Name: "branch", Min: 12, Max: 30}); got != want {
+		t.Errorf("wide[1] = %+v, want %+v", got, want)
+	}
+	if got := cfg.Columns[1].Columns[2].Name; got != "alias:author" {
+		t.Errorf("wide[2] = %q, want alias:author", got)
+	}
+
+	for _, bad := range []string{
+		"columns:\n  - name: x\n    columns: [repo, colour]\n",
+		"columns:\n  - name: x\n    columns: [alias:missing]\n",
+		"columns:\n  - name: x\n    columns: [{name: repo, min: 20, max: 10}]\n",
+		"columns:\n  - columns: [repo]\n",
+	} {
+		if err := os.WriteFile(configPath, []byte(bad), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(configPath); err == nil {
+			t.Errorf("Load(%q) should fail", bad)
+		}
+	}
+}
+
+func TestLoad_ValidatesTheme(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := []byte(`
+theme: mine
+themes:
+  mine:
+    base: light
+    colors:
+      accent: "#268bd2"
+colors:
+  ahead: "201"
+`)
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Themes["mine"].Base != "light" || cfg.Colors["ahead"] != "201" {
+		t.Errorf("Themes = %+v, Colors = %+v", cfg.Themes, cfg.Colors)
+	}
+
+	for _, bad := range []string{
+		"theme: sepia\n",
+		"colors:\n  ahead: orange\n",
+		"themes:\n  x:\n    base: sepia\n",
+	} {
+		if err := os.WriteFile(configPath, []byte(bad), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(configPath); err == nil {
+			t.Errorf("Load(%q) should fail", bad)
+		}
+	}
+}
+
+func TestLoad_ValidatesKeys(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("keys:\n  fetch: x\n  fetch_all: [gf, ctrl+f]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.Keys["fetch"]) != 1 || len(cfg.Keys["fetch_all"]) != 2 {
+		t.Errorf("Keys = %v", cfg.Keys)
+	}
+
+	for _, bad := range []string{
+		"keys:\n  fetchh: x\n",
+		"keys:\n  fetch: p\n",
+		"keys:\n  fetch: g\n",
+	} {
+		if err := os.WriteFile(configPath, []byte(bad), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(configPath); err == nil {
+			t.Errorf("Load(%q) should fail", bad)
+		}
+	}
+}
+
 func TestNewConfig_DefaultIgnorePatterns(t *testing.T) {
 	cfg := NewConfig()
 
@@ -206,6 +508,18 @@ func TestNewConfig_DefaultIgnorePatterns(t *testing.T) {
 		t.Error("AutoRefresh should default to true")
 	}
 
+	if !cfg.Submodules {
+		t.Error("Submodules should default to true")
+	}
+
+	if !cfg.Mouse {
+		t.Error("Mouse should default to true")
+	}
+
+	if !cfg.PullRequests {
+		t.Error("PullRequests should default to true")
+	}
+
 	if len(cfg.IgnorePatterns) == 0 {
 		t.Error("IgnorePatterns should not be empty by default")
 	}
@@ -214,3 +528,22 @@ func TestNewConfig_DefaultIgnorePatterns(t *testing.T) {
 		t.Errorf("ScanPaths should be empty by default, got %d", len(cfg.ScanPaths))
 	}
 }
+
+func TestConfig_SocketPath(t *testing.T) {
+	t.Run("uses XDG_RUNTIME_DIR when set", func(t *testing.T) {
+		t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+		cfg := NewConfig()
+		if got, want := cfg.SocketPath(), "/run/user/1000/gv/gv.sock"; got != want {
+			t.Errorf("SocketPath() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("configured socket wins", func(t *testing.T) {
+		t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+		cfg := NewConfig()
+		cfg.DaemonSocket = "/tmp/custom.sock"
+		if got := cfg.SocketPath(); got != "/tmp/custom.sock" {
+			t.Errorf("SocketPath() = %q, want %q", got, "/tmp/custom.sock")
+		}
+	})
+}
@@ -2,10 +2,19 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/jackchuka/gv/internal/forge"
+	"github.com/jackchuka/gv/internal/ignore"
+	"github.com/jackchuka/gv/internal/keymap"
+	"github.com/jackchuka/gv/internal/query"
+	"github.com/jackchuka/gv/internal/theme"
 )
 
 func DefaultConfigPath() string {
@@ -16,6 +25,23 @@ func DefaultConfigPath() string {
 	return filepath.Join(home, ".config", "gv", "config.yaml")
 }
 
+// DefaultSocketPath returns the daemon socket location, preferring
+// $XDG_RUNTIME_DIR and falling back to a per-user temp directory.
+func DefaultSocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "gv", "gv.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("gv-%d", os.Getuid()), "gv.sock")
+}
+
+// SocketPath returns the configured daemon socket or the default one.
+func (c *Config) SocketPath() string {
+	if c.DaemonSocket != "" {
+		return ExpandHome(c.DaemonSocket)
+	}
+	return DefaultSocketPath()
+}
+
 // Load reads config from path, returning defaults if file doesn't exist
 func Load(path string) (*Config, error) {
 	cfg := NewConfig()
@@ -33,10 +59,81 @@ func Load(path string) (*Config, error) {
 	}
 
 	cfg.ScanPaths = expandPaths(cfg.ScanPaths)
+	for i := range cfg.Repos {
+		if cfg.Repos[i].Path == "" {
+			return nil, fmt.Errorf("repos[%d]: path is required", i)
+		}
+		cfg.Repos[i].Path = ExpandHome(cfg.Repos[i].Path)
+	}
+
+	if _, err := ignore.New(cfg.IgnorePatterns); err != nil {
+		return nil, fmt.Errorf("ignore_patterns: %w", err)
+	}
+
+	for i, v := range cfg.Views {
+		if v.Name == "" {
+			return nil, fmt.Errorf("views[%d]: name is required", i)
+		}
+		if _, err := query.Parse(v.Query); err != nil {
+			return nil, fmt.Errorf("views[%d] (%s): %w", i, v.Name, err)
+		}
+	}
+
+	for i, c := range cfg.Commands {
+		switch {
+		case c.Name == "":
+			return nil, fmt.Errorf("commands[%d]: name is required", i)
+		case c.Run == "":
+			return nil, fmt.Errorf("commands[%d] (%s): run is required", i, c.Name)
+		case c.All && c.Interactive:
+			return nil, fmt.Errorf("commands[%d] (%s): an interactive command can't run in all repos", i, c.Name)
+		}
+	}
+
+	if err := forge.Check(cfg.Forges); err != nil {
+		return nil, err
+	}
+
+	if _, err := keymap.Build(cfg.Keys); err != nil {
+		return nil, fmt.Errorf("keys: %w", err)
+	}
+
+	if err := theme.Check(cfg.Theme, cfg.Themes, cfg.Colors); err != nil {
+		return nil, err
+	}
+
+	for i, p := range cfg.Columns {
+		if p.Name == "" {
+			return nil, fmt.Errorf("columns[%d]: name is required", i)
+		}
+		if len(p.Columns) == 0 {
+			return nil, fmt.Errorf("columns[%d] (%s): no columns", i, p.Name)
+		}
+		for _, c := range p.Columns {
+			if err := cfg.validateColumn(c); err != nil {
+				return nil, fmt.Errorf("columns[%d] (%s): %w", i, p.Name, err)
+			}
+		}
+	}
 
 	return cfg, nil
 }
 
+func (c *Config) validateColumn(col ColumnConfig) error {
+	if alias, ok := strings.CutPrefix(col.Name, AliasColumnPrefix); ok {
+		if _, defined := c.Aliases[alias]; !defined {
+			return fmt.Errorf("column %s: alias %q is not defined in aliases", col.Name, alias)
+		}
+	} else if !slices.Contains(ColumnNames, col.Name) {
+		return fmt.Errorf("unknown column %q (want one of %s, or %s<name>)",
+			col.Name, strings.Join(ColumnNames, ", "), AliasColumnPrefix)
+	}
+	if col.Min < 0 || col.Max < 0 || (col.Max > 0 && col.Max < col.Min) {
+		return fmt.Errorf("column %s: invalid width bounds min=%d max=%d", col.Name, col.Min, col.Max)
+	}
+	return nil
+}
+
 func Save(cfg *Config, path string) error {
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -0,0 +1,386 @@
+package daemon
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/jackchuka/gv/internal/model"
+	"github.com/jackchuka/gv/internal/scanner"
+	"github.com/jackchuka/gv/internal/status"
+	"github.com/jackchuka/gv/internal/watcher"
+)
+
+// ErrNotFound is returned when the daemon does not know a repo.
+var ErrNotFound = errors.New("repo not known to daemon")
+
+// Client talks to a running daemon. It implements scanner.Scanner and
+// status.Reader so the TUI and other commands can use the daemon's cache
+// in place of running git themselves.
+type Client struct {
+	http  *http.Client
+	local *status.GitReader // for operations the daemon does not cache
+
+	mu      sync.Mutex
+	scanned bool
+}
+
+// Connect dials the daemon at socketPath and verifies it responds.
+func Connect(ctx context.Context, socketPath string) (*Client, error) {
+	c := &Client{
+		http: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", socketPath)
+				},
+			},
+		},
+		local: status.NewGitReader(),
+	}
+	if _, err := c.Health(ctx); err != nil {
+		c.CloseIdleConnections()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Client) CloseIdleConnections() {
+	c.http.CloseIdleConnections()
+}
+
+// The host is ignored by the Unix socket dialer.
+func endpoint(path string, query url.Values) string {
+	u := url.URL{Scheme: "http", Host: "gv", Path: path}
+	if query != nil {
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint(path, query), reqBody)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("daemon: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *Client) Health(ctx context.Context) (*Health, error) {
+	var h Health
+	if err := c.do(ctx, http.MethodGet, "/v1/health", nil, nil, &h); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// Repos returns the daemon's cached repos.
+func (c *Client) Repos(ctx context.Context) ([]model.Repository, error) {
+	var repos []model.Repository
+	if err := c.do(ctx, http.MethodGet, "/v1/repos", nil, nil, &repos); err != nil {
+		return nil, err
+	}
+	return repos, nil
+}
+
+// Repo returns the daemon's cached copy of one repo, or ErrNotFound.
+func (c *Client) Repo(ctx context.Context, path string) (*model.Repository, error) {
+	var repo model.Repository
+	if err := c.do(ctx, http.MethodGet, "/v1/repo", url.Values{"path": {path}}, nil, &repo); err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
+
+// Rescan asks the daemon to rediscover repos and reload their statuses.
+func (c *Client) Rescan(ctx context.Context) ([]model.Repository, error) {
+	var repos []model.Repository
+	if err := c.do(ctx, http.MethodPost, "/v1/rescan", nil, nil, &repos); err != nil {
+		return nil, err
+	}
+	return repos, nil
+}
+
+// --- scanner.Scanner ---
+
+// Scan returns the daemon's cached repos on first use, so starting a client
+// is cheap, and triggers a real rescan on subsequent calls.
+func (c *Client) Scan(ctx context.Context) ([]model.Repository, error) {
+	c.mu.Lock()
+	first := !c.scanned
+	c.scanned = true
+	c.mu.Unlock()
+
+	if first {
+		return c.Repos(ctx)
+	}
+	return c.Rescan(ctx)
+}
+
+// ScanStream relays the repos of Scan to found and pairs them with the
+// daemon's report on its last scan.
+func (c *Client) ScanStream(ctx context.Context, found chan<- model.Repository) (*scanner.ScanResult, error) {
+	repos, err := c.Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.ScanReport(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res.Repos = repos
+	if found != nil {
+		for _, r := range repos {
+			select {
+			case found <- r:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+	return res, nil
+}
+
+// ScanReport returns the daemon's report on its last scan, without repos.
+func (c *Client) ScanReport(ctx context.Context) (*scanner.ScanResult, error) {
+	var res scanner.ScanResult
+	if err := c.do(ctx, http.MethodGet, "/v1/scan", nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) ScanPath(ctx context.Context, path string, maxDepth int) ([]model.Repository, error) {
+	return nil, errors.New("daemon client does not support scanning individual paths")
+}
+
+// --- status.Reader ---
+
+func (c *Client) GetStatus(ctx context.Context, repoPath string) (*model.RepoStatus, error) {
+	repo, err := c.Repo(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	if repo.Status == nil {
+		return nil, fmt.Errorf("daemon has no status for %s yet", repoPath)
+	}
+	return repo.Status, nil
+}
+
+// GetStatusFromOutput ignores the porcelain output: the daemon has already
+// parsed it by the time a client hears about a change.
+func (c *Client) GetStatusFromOutput(ctx context.Context, repoPath string, _ string) (*model.RepoStatus, error) {
+	return c.GetStatus(ctx, repoPath)
+}
+
+func (c *Client) GetStatusBatch(ctx context.Context, paths []string) (map[string]*model.RepoStatus, map[string]error) {
+	results := make(map[string]*model.RepoStatus)
+	errs := make(map[string]error)
+
+	repos, err := c.Repos(ctx)
+	if err != nil {
+		for _, p := range paths {
+			errs[p] = err
+		}
+		return results, errs
+	}
+
+	byPath := make(map[string]*model.RepoStatus, len(repos))
+	for _, r := range repos {
+		byPath[r.Path] = r.Status
+	}
+	for _, p := range paths {
+		if st := byPath[p]; st != nil {
+			results[p] = st
+		} else {
+			errs[p] = ErrNotFound
+		}
+	}
+	return results, errs
+}
+
+func (c *Client) Fetch(ctx context.Context, repoPath string) (*model.RepoStatus, error) {
+	var res FetchResult
+	if err := c.do(ctx, http.MethodPost, "/v1/fetch", url.Values{"path": {repoPath}}, nil, &res); err != nil {
+		return nil, err
+	}
+	if res.Error != "" {
+		return res.Status, errors.New(res.Error)
+	}
+	return res.Status, nil
+}
+
+func (c *Client) FetchBatch(ctx context.Context, paths []string) (map[string]*model.RepoStatus, map[string]error) {
+	errs := make(map[string]error)
+
+	var res FetchBatchResult
+	if err := c.do(ctx, http.MethodPost, "/v1/fetch", nil, paths, &res); err != nil {
+		for _, p := range paths {
+			errs[p] = err
+		}
+		return map[string]*model.RepoStatus{}, errs
+	}
+	for p, msg := range res.Errors {
+		errs[p] = errors.New(msg)
+	}
+	if res.Statuses == nil {
+		res.Statuses = make(map[string]*model.RepoStatus)
+	}
+	return res.Statuses, errs
+}
+
+// GetDiffStats serves the cached diff, falling back to reading it locally
+// when the daemon has none.
+func (c *Client) GetDiffStats(ctx context.Context, repoPath string) *model.DiffStats {
+	if repo, err := c.Repo(ctx, repoPath); err == nil && repo.Diff != nil {
+		return repo.Diff
+	}
+	return c.local.GetDiffStats(ctx, repoPath)
+}
+
+func (c *Client) GetDiffStatsBatch(ctx context.Context, paths []string) map[string]*model.DiffStats {
+	results := make(map[string]*model.DiffStats)
+
+	var missing []string
+	repos, err := c.Repos(ctx)
+	if err != nil {
+		return c.local.GetDiffStatsBatch(ctx, paths)
+	}
+	byPath := make(map[string]*model.DiffStats, len(repos))
+	for _, r := range repos {
+		byPath[r.Path] = r.Diff
+	}
+	for _, p := range paths {
+		if ds := byPath[p]; ds != nil {
+			results[p] = ds
+		} else {
+			missing = append(missing, p)
+		}
+	}
+	if len(missing) > 0 {
+		for p, ds := range c.local.GetDiffStatsBatch(ctx, missing) {
+			results[p] = ds
+		}
+	}
+	return results
+}
+
+func (c *Client) GetDetail(ctx context.Context, repoPath string) *model.RepoDetail {
+	return c.local.GetDetail(ctx, repoPath)
+}
+
+func (c *Client) GetRemotes(ctx context.Context, repoPath string) []model.Remote {
+	return c.local.GetRemotes(ctx, repoPath)
+}
+
+func (c *Client) RunAlias(ctx context.Context, repoPath string, cmd string) (string, error) {
+	return c.local.RunAlias(ctx, repoPath, cmd)
+}
+
+// --- watcher.RepoWatcher ---
+
+// Watcher relays the daemon's change events. The daemon watches every repo
+// it knows about, so Watch and Unwatch are no-ops.
+type Watcher struct {
+	client *Client
+	events chan watcher.Event // sent on and closed by Run only
+	stop   chan struct{}
+	once   sync.Once
+}
+
+func NewWatcher(c *Client) *Watcher {
+	return &Watcher{client: c, events: make(chan watcher.Event, 100), stop: make(chan struct{})}
+}
+
+func (w *Watcher) Events() <-chan watcher.Event { return w.events }
+func (w *Watcher) Watch(string) error           { return nil }
+func (w *Watcher) Unwatch(string)               {}
+
+// Run streams events from the daemon, reconnecting after a short pause if
+// the stream drops, until ctx is cancelled or the watcher is closed. It
+// closes the events channel when it returns.
+func (w *Watcher) Run(ctx context.Context) {
+	defer close(w.events)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-w.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		_ = w.stream(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (w *Watcher) stream(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint("/v1/events", nil), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := w.client.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		var ev watcher.Event
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			continue
+		}
+		select {
+		case w.events <- ev:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return sc.Err()
+}
+
+// Close stops Run, which closes the events channel once it's done
+// sending.
+func (w *Watcher) Close() error {
+	w.once.Do(func() { close(w.stop) })
+	return nil
+}
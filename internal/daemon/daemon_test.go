@@ -0,0 +1,255 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackchuka/gv/internal/config"
+	"github.com/jackchuka/gv/internal/metrics"
+	"github.com/jackchuka/gv/internal/model"
+	"github.com/jackchuka/gv/internal/scanner"
+	"github.com/jackchuka/gv/internal/status"
+	"github.com/jackchuka/gv/internal/watcher"
+)
+
+var (
+	_ scanner.Scanner     = (*Client)(nil)
+	_ status.Reader       = (*Client)(nil)
+	_ watcher.RepoWatcher = (*Watcher)(nil)
+	_ metrics.Source      = (*Service)(nil)
+)
+
+// startDaemon scans a temp dir holding one committed repo and serves it on
+// a temp socket.
+func startDaemon(t *testing.T) (*Service, *Client, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	root := t.TempDir()
+	repo := filepath.Join(root, "project")
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "init")
+	runGit(t, repo, "config", "user.email", "test@test.com")
+	runGit(t, repo, "config", "user.name", "Test")
+	if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte("a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", "a.txt")
+	runGit(t, repo, "commit", "-m", "initial")
+
+	cfg := config.NewConfig()
+	cfg.ScanPaths = []string{root}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	svc := NewService(cfg, nil)
+	if _, err := svc.Rescan(ctx); err != nil {
+		t.Fatalf("Rescan() error = %v", err)
+	}
+
+	socket := filepath.Join(t.TempDir(), "gv.sock")
+	srv := NewServer(svc)
+	go func() { _ = srv.ListenAndServe(ctx, socket) }()
+
+	var client *Client
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		c, err := Connect(ctx, socket)
+		if err == nil {
+			client = c
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Connect() error = %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Cleanup(client.CloseIdleConnections)
+
+	return svc, client, repo
+}
+
+func TestClient_Repos(t *testing.T) {
+	_, client, repo := startDaemon(t)
+	ctx := context.Background()
+
+	repos, err := client.Repos(ctx)
+	if err != nil {
+		t.Fatalf("Repos() error = %v", err)
+	}
+	if len(repos) != 1 || repos[0].Path != repo {
+		t.Fatalf("Repos() = %+v, want only %s", repos, repo)
+	}
+	if repos[0].Status == nil {
+		t.Fatal("repo status should be cached by the daemon")
+	}
+
+	h, err := client.Health(ctx)
+	if err != nil {
+		t.Fatalf("Health() error = %v", err)
+	}
+	if h.Repos != 1 || h.PID != os.Getpid() {
+		t.Errorf("Health() = %+v", h)
+	}
+}
+
+func TestClient_RepoNotFound(t *testing.T) {
+	_, client, _ := startDaemon(t)
+
+	_, err := client.GetStatus(context.Background(), "/does/not/exist")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetStatus() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestClient_ScanStream(t *testing.T) {
+	_, client, repo := startDaemon(t)
+	ctx := context.Background()
+
+	found := make(chan model.Repository, 10)
+	res, err := client.ScanStream(ctx, found)
+	if err != nil {
+		t.Fatalf("ScanStream() error = %v", err)
+	}
+	close(found)
+
+	var streamed []string
+	for r := range found {
+		streamed = append(streamed, r.Path)
+	}
+	if len(streamed) != 1 || streamed[0] != repo {
+		t.Errorf("streamed %v, want only %s", streamed, repo)
+	}
+	if len(res.Repos) != 1 {
+		t.Errorf("Repos = %+v, want 1 repo", res.Repos)
+	}
+	// The daemon's scan visited the root and the repo
+	if res.Dirs < 2 {
+		t.Errorf("Dirs = %d, want at least 2", res.Dirs)
+	}
+}
+
+func TestClient_FetchWithoutRemote(t *testing.T) {
+	_, client, repo := startDaemon(t)
+
+	st, err := client.Fetch(context.Background(), repo)
+	if st == nil {
+		t.Fatalf("Fetch() status = nil, err = %v", err)
+	}
+}
+
+func TestWatcher_RelaysChanges(t *testing.T) {
+	svc, client, repo := startDaemon(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := NewWatcher(client)
+	go w.Run(ctx)
+
+	// Wait for the event stream to subscribe
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		svc.subMu.Lock()
+		n := len(svc.subs)
+		svc.subMu.Unlock()
+		if n > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("watcher never subscribed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte("changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	svc.apply(ctx, watcher.Event{RepoPath: repo, Time: time.Now()})
+
+	select {
+	case ev := <-w.Events():
+		if ev.RepoPath != repo {
+			t.Errorf("event RepoPath = %q, want %q", ev.RepoPath, repo)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event relayed from daemon")
+	}
+
+	st, err := client.GetStatus(ctx, repo)
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	if st.Modified != 1 {
+		t.Errorf("Modified = %d, want 1 after change", st.Modified)
+	}
+}
+
+func TestWatcher_CloseEndsRun(t *testing.T) {
+	_, client, _ := startDaemon(t)
+
+	w := NewWatcher(client)
+	done := make(chan struct{})
+	go func() {
+		w.Run(context.Background())
+		close(done)
+	}()
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Close() // twice is fine
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run didn't return after Close")
+	}
+	if _, ok := <-w.Events(); ok {
+		t.Error("events channel still open after Run returned")
+	}
+}
+
+func TestServer_RefusesSecondDaemon(t *testing.T) {
+	svc, _, _ := startDaemon(t)
+
+	socket := filepath.Join(t.TempDir(), "gv.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() { _ = NewServer(svc).ListenAndServe(ctx, socket) }()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if c, err := Connect(ctx, socket); err == nil {
+			c.CloseIdleConnections()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("first server never came up")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	err := NewServer(svc).ListenAndServe(ctx, socket)
+	if err == nil {
+		t.Error("second ListenAndServe() on a live socket should fail")
+	}
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
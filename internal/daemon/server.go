@@ -0,0 +1,209 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jackchuka/gv/internal/model"
+)
+
+// Health describes a running daemon.
+type Health struct {
+	PID       int       `json:"pid"`
+	StartedAt time.Time `json:"started_at"`
+	ScannedAt time.Time `json:"scanned_at"`
+	Repos     int       `json:"repos"`
+}
+
+// FetchResult is the response of a single-repo fetch. Error is set when
+// the fetch failed but a status could still be read.
+type FetchResult struct {
+	Status *model.RepoStatus `json:"status"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// FetchBatchResult is the response of a multi-repo fetch.
+type FetchBatchResult struct {
+	Statuses map[string]*model.RepoStatus `json:"statuses"`
+	Errors   map[string]string            `json:"errors,omitempty"`
+}
+
+// Server exposes a Service over HTTP on a Unix domain socket.
+type Server struct {
+	svc     *Service
+	started time.Time
+	mux     *http.ServeMux
+}
+
+func NewServer(svc *Service) *Server {
+	s := &Server{svc: svc, started: time.Now(), mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /v1/health", s.handleHealth)
+	s.mux.HandleFunc("GET /v1/repos", s.handleRepos)
+	s.mux.HandleFunc("GET /v1/repo", s.handleRepo)
+	s.mux.HandleFunc("POST /v1/rescan", s.handleRescan)
+	s.mux.HandleFunc("GET /v1/scan", s.handleScan)
+	s.mux.HandleFunc("POST /v1/fetch", s.handleFetch)
+	s.mux.HandleFunc("GET /v1/events", s.handleEvents)
+	return s
+}
+
+// Handle registers an additional handler, e.g. for a metrics endpoint.
+func (s *Server) Handle(pattern string, h http.Handler) {
+	s.mux.Handle(pattern, h)
+}
+
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// ListenAndServe serves on socketPath until ctx is cancelled. A stale
+// socket left by a crashed daemon is removed; a live one is an error.
+func (s *Server) ListenAndServe(ctx context.Context, socketPath string) error {
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0700); err != nil {
+		return err
+	}
+	if _, err := os.Stat(socketPath); err == nil {
+		if c, err := Connect(ctx, socketPath); err == nil {
+			c.CloseIdleConnections()
+			return fmt.Errorf("daemon already running on %s", socketPath)
+		}
+		if err := os.Remove(socketPath); err != nil {
+			return err
+		}
+	}
+
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(socketPath) }()
+	if err := os.Chmod(socketPath, 0600); err != nil {
+		_ = ln.Close()
+		return err
+	}
+
+	srv := &http.Server{Handler: s.mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, Health{
+		PID:       os.Getpid(),
+		StartedAt: s.started,
+		ScannedAt: s.svc.ScannedAt(),
+		Repos:     len(s.svc.Repos()),
+	})
+}
+
+func (s *Server) handleRepos(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.svc.Repos())
+}
+
+func (s *Server) handleRepo(w http.ResponseWriter, r *http.Request) {
+	repo := s.svc.Repo(r.URL.Query().Get("path"))
+	if repo == nil {
+		http.Error(w, "repo not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, repo)
+}
+
+func (s *Server) handleRescan(w http.ResponseWriter, r *http.Request) {
+	repos, err := s.svc.Rescan(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, repos)
+}
+
+func (s *Server) handleScan(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.svc.LastScan())
+}
+
+// handleFetch fetches the repo named by ?path=, or the repos listed as a
+// JSON array in the request body.
+func (s *Server) handleFetch(w http.ResponseWriter, r *http.Request) {
+	if path := r.URL.Query().Get("path"); path != "" {
+		if s.svc.Repo(path) == nil {
+			http.Error(w, "repo not found", http.StatusNotFound)
+			return
+		}
+		st, err := s.svc.Fetch(r.Context(), path)
+		if st == nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res := FetchResult{Status: st}
+		if err != nil {
+			res.Error = err.Error()
+		}
+		writeJSON(w, http.StatusOK, res)
+		return
+	}
+
+	var paths []string
+	if err := json.NewDecoder(r.Body).Decode(&paths); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	statuses, errs := s.svc.FetchBatch(r.Context(), paths)
+	res := FetchBatchResult{Statuses: statuses, Errors: make(map[string]string, len(errs))}
+	for p, err := range errs {
+		res.Errors[p] = err.Error()
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// handleEvents streams change events as newline-delimited JSON until the
+// client disconnects.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	events, cancel := s.svc.Subscribe()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	enc := json.NewEncoder(w)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev := <-events:
+			if err := enc.Encode(ev); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
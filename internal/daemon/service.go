@@ -0,0 +1,360 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackchuka/gv/internal/cache"
+	"github.com/jackchuka/gv/internal/config"
+	"github.com/jackchuka/gv/internal/model"
+	"github.com/jackchuka/gv/internal/notify"
+	"github.com/jackchuka/gv/internal/scanner"
+	"github.com/jackchuka/gv/internal/status"
+	"github.com/jackchuka/gv/internal/watcher"
+)
+
+// Service keeps repo statuses fresh in the background: it scans the
+// configured paths, reads statuses, and applies poller events to a shared
+// in-memory cache that clients query through the Server.
+type Service struct {
+	scanner  scanner.Scanner
+	reader   status.Reader
+	poller   *watcher.Poller
+	notifier *notify.Notifier
+
+	mu        sync.RWMutex
+	repos     map[string]*model.Repository
+	scannedAt time.Time
+	scanTook  time.Duration
+	lastScan  *scanner.ScanResult
+
+	cachePath string      // snapshot file for prompts; empty disables persistence
+	dirty     atomic.Bool // cache changed since the last snapshot
+
+	subMu sync.Mutex
+	subs  map[chan watcher.Event]struct{}
+}
+
+func NewService(cfg *config.Config, notifier *notify.Notifier) *Service {
+	return &Service{
+		scanner:  scanner.NewWalker(cfg),
+		reader:   status.NewGitReader(),
+		poller:   watcher.NewPoller(cfg.PollInterval),
+		notifier: notifier,
+		repos:    make(map[string]*model.Repository),
+		subs:     make(map[chan watcher.Event]struct{}),
+	}
+}
+
+// PersistTo makes Run write snapshots of the cache to path, so commands
+// can serve statuses even while the daemon is not reachable.
+func (s *Service) PersistTo(path string) {
+	s.cachePath = path
+}
+
+func (s *Service) persist() {
+	if s.cachePath == "" || !s.dirty.Swap(false) {
+		return
+	}
+	if err := cache.Save(s.cachePath, s.Repos()); err != nil {
+		log.Printf("persist cache: %v", err)
+	}
+}
+
+// Run performs an initial scan, then applies poller events and rescans
+// every rescanInterval until ctx is cancelled.
+func (s *Service) Run(ctx context.Context, rescanInterval time.Duration) error {
+	if _, err := s.Rescan(ctx); err != nil {
+		return err
+	}
+
+	go s.poller.Run(ctx)
+	defer func() { _ = s.poller.Close() }()
+	defer s.persist()
+
+	persistTicker := time.NewTicker(2 * time.Second)
+	defer persistTicker.Stop()
+
+	var tick <-chan time.Time
+	if rescanInterval > 0 {
+		ticker := time.NewTicker(rescanInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	sem := make(chan struct{}, 4)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-tick:
+			if _, err := s.Rescan(ctx); err != nil {
+				log.Printf("rescan: %v", err)
+			}
+		case <-persistTicker.C:
+			s.persist()
+		case ev := <-s.poller.Events():
+			sem <- struct{}{}
+			go func() {
+				defer func() { <-sem }()
+				s.apply(ctx, ev)
+			}()
+		}
+	}
+}
+
+// Rescan rediscovers repos, reloads every status and diff, and updates the
+// set of watched repos. Previously known statuses are kept for comparison
+// so transitions are still detected across rescans.
+func (s *Service) Rescan(ctx context.Context) ([]model.Repository, error) {
+	start := time.Now()
+	scanCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	res, err := s.scanner.ScanStream(scanCtx, nil)
+	if err != nil {
+		return nil, err
+	}
+	found := res.Repos
+
+	paths := make([]string, len(found))
+	for i, r := range found {
+		paths[i] = r.Path
+		s.poller.SetInterval(r.Path, r.PollInterval)
+	}
+	status.ConfigureRemotes(s.reader, found)
+
+	statusCtx, statusCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer statusCancel()
+	statuses, _ := s.reader.GetStatusBatch(statusCtx, paths)
+
+	diffCtx, diffCancel := context.WithTimeout(ctx, 60*time.Second)
+	defer diffCancel()
+	diffs := s.reader.GetDiffStatsBatch(diffCtx, paths)
+
+	now := time.Now()
+	var events []notify.Event
+
+	s.mu.Lock()
+	next := make(map[string]*model.Repository, len(found))
+	for i := range found {
+		r := found[i]
+		var prev *model.RepoStatus
+		if old, ok := s.repos[r.Path]; ok {
+			prev = old.Status
+		}
+		r.Status = statuses[r.Path]
+		r.Diff = diffs[r.Path]
+		r.LastScanned = now
+		events = append(events, notify.Detect(&r, prev, r.Status)...)
+		next[r.Path] = &r
+	}
+	for path := range s.repos {
+		if _, ok := next[path]; !ok {
+			s.poller.Unwatch(path)
+		}
+	}
+	s.repos = next
+	s.scannedAt = now
+	s.scanTook = time.Since(start)
+	report := *res
+	report.Repos = nil // served by Repos
+	s.lastScan = &report
+	s.mu.Unlock()
+	s.dirty.Store(true)
+
+	for _, path := range paths {
+		_ = s.poller.Watch(path)
+	}
+	s.notify(ctx, events)
+
+	return s.Repos(), nil
+}
+
+// apply refreshes a repo after the poller reported a change, then tells
+// subscribers. Subscribers only hear about the change once the cache holds
+// the new status and diff.
+func (s *Service) apply(ctx context.Context, ev watcher.Event) {
+	readCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var (
+		st  *model.RepoStatus
+		err error
+	)
+	if len(ev.StatusOutput) > 0 {
+		st, err = s.reader.GetStatusFromOutput(readCtx, ev.RepoPath, string(ev.StatusOutput))
+	} else {
+		st, err = s.reader.GetStatus(readCtx, ev.RepoPath)
+	}
+	if err != nil {
+		return
+	}
+	ds := s.reader.GetDiffStats(readCtx, ev.RepoPath)
+
+	s.store(ctx, ev.RepoPath, st, ds)
+	s.publish(watcher.Event{RepoPath: ev.RepoPath, Time: ev.Time})
+}
+
+// store replaces the cached status (and diff, when non-nil) of a known repo.
+func (s *Service) store(ctx context.Context, path string, st *model.RepoStatus, ds *model.DiffStats) {
+	s.mu.Lock()
+	r, ok := s.repos[path]
+	if !ok {
+		s.mu.Unlock()
+		return
+	}
+	events := notify.Detect(r, r.Status, st)
+	// Copy-on-write so snapshots handed out by Repos stay untouched
+	updated := *r
+	updated.Status = st
+	if ds != nil {
+		updated.Diff = ds
+	}
+	updated.LastScanned = time.Now()
+	s.repos[path] = &updated
+	s.mu.Unlock()
+	s.dirty.Store(true)
+
+	s.notify(ctx, events)
+}
+
+func (s *Service) notify(ctx context.Context, events []notify.Event) {
+	if s.notifier == nil || len(events) == 0 {
+		return
+	}
+	go func() {
+		sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		if err := s.notifier.Dispatch(sendCtx, events); err != nil {
+			log.Printf("notify: %v", err)
+		}
+	}()
+}
+
+// Repos returns a snapshot of every known repo, sorted by path.
+func (s *Service) Repos() []model.Repository {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	repos := make([]model.Repository, 0, len(s.repos))
+	for _, r := range s.repos {
+		repos = append(repos, *r)
+	}
+	sort.Slice(repos, func(i, j int) bool { return repos[i].Path < repos[j].Path })
+	return repos
+}
+
+// Repo returns a snapshot of a single repo, or nil if it is unknown.
+func (s *Service) Repo(path string) *model.Repository {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.repos[path]
+	if !ok {
+		return nil
+	}
+	cp := *r
+	return &cp
+}
+
+// ScannedAt returns when the last full scan completed.
+func (s *Service) ScannedAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.scannedAt
+}
+
+// ScanDuration returns how long the last full scan took, including
+// reading statuses and diffs.
+func (s *Service) ScanDuration() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.scanTook
+}
+
+// LastScan reports on the most recent rescan, without its repos. It is
+// empty before the first scan.
+func (s *Service) LastScan() *scanner.ScanResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.lastScan == nil {
+		return &scanner.ScanResult{}
+	}
+	report := *s.lastScan
+	return &report
+}
+
+// PollDuration returns how long the last poll cycle took.
+func (s *Service) PollDuration() time.Duration {
+	return s.poller.LastPollDuration()
+}
+
+// Fetch fetches a repo, updates the cache and notifies subscribers.
+// Like status.Reader.Fetch, a non-nil status may accompany a fetch error.
+func (s *Service) Fetch(ctx context.Context, path string) (*model.RepoStatus, error) {
+	if s.fetchDisabled(path) {
+		return nil, fmt.Errorf("fetching is disabled for %s", path)
+	}
+	st, err := s.reader.Fetch(ctx, path)
+	if st != nil {
+		s.store(ctx, path, st, s.reader.GetDiffStats(ctx, path))
+		s.publish(watcher.Event{RepoPath: path, Time: time.Now()})
+	}
+	return st, err
+}
+
+// FetchBatch fetches several repos concurrently, leaving out those with
+// fetching disabled.
+func (s *Service) FetchBatch(ctx context.Context, paths []string) (map[string]*model.RepoStatus, map[string]error) {
+	paths = slices.DeleteFunc(slices.Clone(paths), s.fetchDisabled)
+	statuses, errs := s.reader.FetchBatch(ctx, paths)
+	diffs := s.reader.GetDiffStatsBatch(ctx, paths)
+	for path, st := range statuses {
+		s.store(ctx, path, st, diffs[path])
+		s.publish(watcher.Event{RepoPath: path, Time: time.Now()})
+	}
+	return statuses, errs
+}
+
+func (s *Service) fetchDisabled(path string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r, ok := s.repos[path]
+	return ok && r.FetchDisabled
+}
+
+// Subscribe registers for change events. The returned cancel function
+// must be called to release the subscription.
+func (s *Service) Subscribe() (<-chan watcher.Event, func()) {
+	ch := make(chan watcher.Event, 100)
+
+	s.subMu.Lock()
+	s.subs[ch] = struct{}{}
+	s.subMu.Unlock()
+
+	return ch, func() {
+		s.subMu.Lock()
+		delete(s.subs, ch)
+		s.subMu.Unlock()
+	}
+}
+
+func (s *Service) publish(ev watcher.Event) {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+
+	for ch := range s.subs {
+		select {
+		case ch <- ev:
+		default:
+			// Slow subscriber — drop rather than stall the daemon
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PRState is the state of a pull or merge request.
+type PRState string
+
+const (
+	PROpen   PRState = "open"
+	PRDraft  PRState = "draft"
+	PRMerged PRState = "merged"
+	PRClosed PRState = "closed"
+)
+
+// ReviewState sums up the reviews of a pull request.
+type ReviewState string
+
+const (
+	ReviewNone             ReviewState = ""
+	ReviewRequired         ReviewState = "review_required"
+	ReviewApproved         ReviewState = "approved"
+	ReviewChangesRequested ReviewState = "changes_requested"
+)
+
+// CheckState sums up the CI checks of a commit.
+type CheckState string
+
+const (
+	ChecksNone    CheckState = ""
+	ChecksPending CheckState = "pending"
+	ChecksSuccess CheckState = "success"
+	ChecksFailure CheckState = "failure"
+)
+
+// PullRequest is a branch's pull or merge request.
+type PullRequest struct {
+	Number int
+	Title  string
+	URL    string
+	State  PRState
+	Review ReviewState
+}
+
+// Status is what a forge says about a branch.
+type Status struct {
+	PR     *PullRequest // the most recent one from the branch; nil when there is none
+	Checks CheckState   // of the branch's head, or of the pull request's
+}
+
+// Ref names a branch and the repo its pull requests are opened in. For a
+// fork that is the original repo, while the branch lives in the fork.
+type Ref struct {
+	Owner, Repo         string // where pull requests are opened
+	HeadOwner, HeadRepo string // where the branch is
+	Branch              string
+}
+
+// Provider reads the pull request and checks of a branch from a forge's
+// API.
+type Provider interface {
+	BranchStatus(ctx context.Context, ref Ref) (*Status, error)
+}
+
+// RateLimitError is returned when the API refuses requests until Reset.
+type RateLimitError struct {
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return "rate limited until " + e.Reset.Format(time.Kitchen)
+}
+
+var (
+	ErrNoAPI        = errors.New("no API support")
+	ErrUnauthorized = errors.New("unauthorized; check the token")
+	ErrNotFound     = errors.New("repo not found; private repos need a token")
+)
+
+// defaultRetry is how long to back off after a rate limit that doesn't say
+// when it ends.
+const defaultRetry = time.Minute
+
+// tokenEnv are the environment variables a token is read from, for the
+// public host of each kind only: a self-hosted instance gets its token
+// from the config, so a token is never sent to a host it wasn't made for.
+var tokenEnv = map[string][]string{
+	"github.com": {"GITHUB_TOKEN", "GH_TOKEN"},
+	"gitlab.com": {"GITLAB_TOKEN"},
+	"gitea.com":  {"GITEA_TOKEN"},
+}
+
+// Provider returns the API client of the forge, with client making the
+// requests. It returns ErrNoAPI for kinds without one.
+func (f Forge) Provider(client *http.Client) (Provider, error) {
+	api := apiClient{client: client, base: f.API, token: f.Token}
+	switch f.Kind {
+	case GitHub:
+		api.auth = "Bearer "
+		return &github{api}, nil
+	case GitLab:
+		return &gitlab{api}, nil
+	case Gitea:
+		api.auth = "token "
+		return &gitea{api}, nil
+	}
+	return nil, fmt.Errorf("%s: %w", f.Kind, ErrNoAPI)
+}
+
+// apiRoot returns the API root of a forge at web root base.
+func apiRoot(kind Kind, base string) string {
+	switch kind {
+	case GitHub:
+		if base == "https://github.com" {
+			return "https://api.github.com"
+		}
+		return base + "/api/v3"
+	case GitLab:
+		return base + "/api/v4"
+	case Gitea:
+		return base + "/api/v1"
+	}
+	return ""
+}
+
+// envToken returns the token of a public host from the environment.
+func envToken(host string) string {
+	for _, name := range tokenEnv[host] {
+		if t := os.Getenv(name); t != "" {
+			return t
+		}
+	}
+	return ""
+}
+
+// apiClient makes the JSON requests of a provider.
+type apiClient struct {
+	client *http.Client
+	base   string
+	token  string
+	auth   string // Authorization header prefix; empty sends PRIVATE-TOKEN as GitLab wants
+}
+
+// get decodes the JSON response to GET path into v.
+func (c apiClient) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.base+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		if c.auth != "" {
+			req.Header.Set("Authorization", c.auth+c.token)
+		} else {
+			req.Header.Set("PRIVATE-TOKEN", c.token)
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		var uerr *url.Error
+		if errors.As(err, &uerr) { // without the long request URL
+			return fmt.Errorf("%s: %w", req.URL.Host, uerr.Err)
+		}
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// getChecks is get for the checks of a commit or branch. Those of one the
+// forge doesn't have, like a branch that was never pushed, are not found:
+// v is left as is, meaning no checks, rather than failing as a missing
+// repo would.
+func (c apiClient) getChecks(ctx context.Context, path string, v any) error {
+	if err := c.get(ctx, path, v); !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// checkResponse turns an unsuccessful response into an error, a
+// RateLimitError when the API says when to come back.
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	limited := resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0")
+	switch {
+	case limited:
+		return &RateLimitError{Reset: rateLimitReset(resp.Header, time.Now())}
+	case resp.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+	return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+}
+
+// rateLimitReset reads when a rate limit ends from Retry-After (seconds),
+// then the reset time GitHub (X-RateLimit-Reset) and GitLab
+// (RateLimit-Reset) send as a Unix time.
+func rateLimitReset(h http.Header, now time.Time) time.Time {
+	if s, err := strconv.Atoi(h.Get("Retry-After")); err == nil {
+		return now.Add(time.Duration(s) * time.Second)
+	}
+	for _, name := range []string{"X-RateLimit-Reset", "RateLimit-Reset"} {
+		if sec, err := strconv.ParseInt(h.Get(name), 10, 64); err == nil {
+			return time.Unix(sec, 0)
+		}
+	}
+	return now.Add(defaultRetry)
+}
+
+// combineChecks sums up check states: any failure fails, else anything
+// pending is pending.
+func combineChecks(states ...CheckState) CheckState {
+	out := ChecksNone
+	for _, s := range states {
+		switch {
+		case s == ChecksFailure:
+			return ChecksFailure
+		case s == ChecksPending:
+			out = ChecksPending
+		case s == ChecksSuccess && out == ChecksNone:
+			out = ChecksSuccess
+		}
+	}
+	return out
+}
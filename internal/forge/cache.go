@@ -0,0 +1,72 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Cache remembers branch statuses, and failures to read them, for a while,
+// and stops asking a host that rate limited it until the limit resets.
+type Cache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry // host and ref -> last answer
+	blocked map[string]time.Time  // host -> end of its rate limit
+}
+
+type cacheEntry struct {
+	status *Status
+	err    error
+	at     time.Time
+}
+
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]cacheEntry),
+		blocked: make(map[string]time.Time),
+	}
+}
+
+// BranchStatus returns the status of ref on host, from the cache while it
+// is fresh, else from p. While host is rate limited it returns the last
+// status it had, if any, with a RateLimitError.
+func (c *Cache) BranchStatus(ctx context.Context, host string, p Provider, ref Ref) (*Status, error) {
+	key := fmt.Sprintf("%s\x00%v", host, ref)
+
+	c.mu.Lock()
+	last, ok := c.entries[key]
+	if ok && c.now().Sub(last.at) < c.ttl {
+		c.mu.Unlock()
+		return last.status, last.err
+	}
+	if reset, limited := c.blocked[host]; limited {
+		if c.now().Before(reset) {
+			c.mu.Unlock()
+			return last.status, &RateLimitError{Reset: reset}
+		}
+		delete(c.blocked, host)
+	}
+	c.mu.Unlock()
+
+	status, err := p.BranchStatus(ctx, ref)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var rl *RateLimitError
+	switch {
+	case errors.As(err, &rl):
+		c.blocked[host] = rl.Reset
+		return last.status, err
+	case ctx.Err() != nil:
+		return last.status, err // not the forge's answer; ask again next time
+	}
+	c.entries[key] = cacheEntry{status: status, err: err, at: c.now()}
+	return status, err
+}
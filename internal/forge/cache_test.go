@@ -0,0 +1,68 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeProvider answers with err, or else a status, counting the calls.
+type fakeProvider struct {
+	calls int
+	err   error
+}
+
+func (p *fakeProvider) BranchStatus(ctx context.Context, ref Ref) (*Status, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &Status{Checks: ChecksSuccess}, nil
+}
+
+func TestCache_BranchStatus(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	c := NewCache(time.Minute)
+	c.now = func() time.Time { return now }
+	p := &fakeProvider{}
+	ctx := context.Background()
+
+	get := func() (*Status, error) { return c.BranchStatus(ctx, "github.com", p, forkRef) }
+
+	if st, err := get(); err != nil || st.Checks != ChecksSuccess || p.calls != 1 {
+		t.Fatalf("first call = %+v, %v after %d calls", st, err, p.calls)
+	}
+	if _, _ = get(); p.calls != 1 {
+		t.Errorf("fresh entry asked the provider again: %d calls", p.calls)
+	}
+
+	// Expired, and the host rate limits: the last status is kept
+	now = now.Add(2 * time.Minute)
+	reset := now.Add(10 * time.Minute)
+	p.err = &RateLimitError{Reset: reset}
+	st, err := get()
+	var rl *RateLimitError
+	if !errors.As(err, &rl) || st == nil || st.Checks != ChecksSuccess || p.calls != 2 {
+		t.Errorf("rate limited call = %+v, %v after %d calls", st, err, p.calls)
+	}
+
+	// Other branches on the host wait for the reset without asking
+	other := forkRef
+	other.Branch = "main"
+	if _, err := c.BranchStatus(ctx, "github.com", p, other); !errors.As(err, &rl) || p.calls != 2 {
+		t.Errorf("call while limited = %v after %d calls", err, p.calls)
+	}
+
+	// After the reset, failures are remembered too
+	now = reset.Add(time.Second)
+	p.err = ErrNotFound
+	for range 2 {
+		if _, err := get(); !errors.Is(err, ErrNotFound) {
+			t.Errorf("error = %v, want not found", err)
+		}
+	}
+	if p.calls != 3 {
+		t.Errorf("provider called %d times, want 3", p.calls)
+	}
+}
@@ -0,0 +1,314 @@
+// Package forge knows the web UIs of git hosting services: which service a
+// remote's host runs, and the URLs of a repo's page, its branches, commits
+// and compare or new pull request page there.
+package forge
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Kind is a hosting service, or the software a self-hosted instance runs.
+type Kind string
+
+const (
+	GitHub    Kind = "github"
+	GitLab    Kind = "gitlab"
+	Bitbucket Kind = "bitbucket"
+	Gitea     Kind = "gitea" // also Forgejo and Codeberg
+	Azure     Kind = "azure" // Azure DevOps
+)
+
+// Kinds lists every kind.
+var Kinds = []Kind{GitHub, GitLab, Bitbucket, Gitea, Azure}
+
+// Page is a page of a repo's web UI.
+type Page string
+
+const (
+	RepoPage    Page = "repo"
+	BranchPage  Page = "branch"
+	CommitPage  Page = "commit"
+	ComparePage Page = "compare" // the branch against the default branch
+)
+
+// Templates are the URLs of a forge's pages, with placeholders: {base} is
+// the web root, {owner} and {repo} name the repo, {branch} is the branch,
+// {default} the default branch and {commit} the commit shown.
+type Templates struct {
+	Repo    string `yaml:"repo,omitempty"`
+	Branch  string `yaml:"branch,omitempty"`
+	Commit  string `yaml:"commit,omitempty"`
+	Compare string `yaml:"compare,omitempty"` // a new pull request, or a compare view
+}
+
+var builtin = map[Kind]Templates{
+	GitHub: {
+		Repo:    "{base}/{owner}/{repo}",
+		Branch:  "{base}/{owner}/{repo}/tree/{branch}",
+		Commit:  "{base}/{owner}/{repo}/commit/{commit}",
+		Compare: "{base}/{owner}/{repo}/compare/{default}...{branch}?expand=1",
+	},
+	GitLab: {
+		Repo:    "{base}/{owner}/{repo}",
+		Branch:  "{base}/{owner}/{repo}/-/tree/{branch}",
+		Commit:  "{base}/{owner}/{repo}/-/commit/{commit}",
+		Compare: "{base}/{owner}/{repo}/-/merge_requests/new?merge_request[source_branch]={branch}&merge_request[target_branch]={default}",
+	},
+	Bitbucket: {
+		Repo:    "{base}/{owner}/{repo}",
+		Branch:  "{base}/{owner}/{repo}/src/{branch}",
+		Commit:  "{base}/{owner}/{repo}/commits/{commit}",
+		Compare: "{base}/{owner}/{repo}/pull-requests/new?source={branch}&dest={default}",
+	},
+	Gitea: {
+		Repo:    "{base}/{owner}/{repo}",
+		Branch:  "{base}/{owner}/{repo}/src/branch/{branch}",
+		Commit:  "{base}/{owner}/{repo}/commit/{commit}",
+		Compare: "{base}/{owner}/{repo}/compare/{default}...{branch}",
+	},
+	Azure: {
+		Repo:    "{base}/{owner}/_git/{repo}",
+		Branch:  "{base}/{owner}/_git/{repo}?version=GB{branch}",
+		Commit:  "{base}/{owner}/_git/{repo}/commit/{commit}",
+		Compare: "{base}/{owner}/_git/{repo}/pullrequestcreate?sourceRef={branch}&targetRef={default}",
+	},
+}
+
+// wellKnown are public hosts by kind.
+var wellKnown = map[string]Kind{
+	"github.com":    GitHub,
+	"gitlab.com":    GitLab,
+	"bitbucket.org": Bitbucket,
+	"codeberg.org":  Gitea,
+	"gitea.com":     Gitea,
+	"dev.azure.com": Azure,
+}
+
+// Def is a forge from the config, for a self-hosted instance: the software
+// it runs, its web root when that differs from the remote's host, and
+// templates replacing the kind's.
+type Def struct {
+	Type      Kind   `yaml:"type"`
+	URL       string `yaml:"url,omitempty"`   // default https://<host>
+	API       string `yaml:"api,omitempty"`   // API root; default derived from url
+	Token     string `yaml:"token,omitempty"` // API token; $VARS are expanded
+	Templates `yaml:",inline"`
+}
+
+// Forge is the web UI and API of a host.
+type Forge struct {
+	Kind  Kind
+	Base  string // web root without a trailing slash, e.g. https://github.com
+	API   string // API root, e.g. https://api.github.com; empty for kinds without API support
+	Token string
+	Templates
+}
+
+// For returns the forge of host: one from defs, keyed by host, a
+// well-known host, or one whose name gives it away, like
+// gitlab.example.com. It reports false when the kind can't be told. Public
+// hosts without a token in defs take one from the environment.
+func For(host string, defs map[string]Def) (Forge, bool) {
+	if def, ok := defs[host]; ok {
+		f, err := def.forge(host)
+		return f, err == nil
+	}
+	kind, ok := wellKnown[host]
+	if !ok {
+		kind, ok = guess(host)
+	}
+	if !ok {
+		return Forge{}, false
+	}
+	base := "https://" + host
+	return Forge{
+		Kind:      kind,
+		Base:      base,
+		API:       apiRoot(kind, base),
+		Token:     envToken(host),
+		Templates: builtin[kind],
+	}, true
+}
+
+// guess tells the kind of a self-hosted forge from a whole label of its
+// name, like gitlab in gitlab.example.com. Names that aren't DNS names,
+// like the ssh config aliases github.com-work or gitlab-corp, are not
+// guessed: their web root is unknown.
+func guess(host string) (Kind, bool) {
+	if strings.HasSuffix(host, ".visualstudio.com") {
+		return Azure, true
+	}
+	labels := strings.Split(strings.ToLower(host), ".")
+	if len(labels) < 2 || slices.ContainsFunc(labels, invalidLabel) || !isTLD(labels[len(labels)-1]) {
+		return "", false
+	}
+	for _, l := range labels[:len(labels)-1] {
+		switch l {
+		case "forgejo":
+			return Gitea, true
+		case string(GitLab), string(Gitea), string(GitHub), string(Bitbucket):
+			return Kind(l), true
+		}
+	}
+	return "", false
+}
+
+// invalidLabel reports whether l, lowercased, can't be a label of a DNS
+// name.
+func invalidLabel(l string) bool {
+	if l == "" || len(l) > 63 || l[0] == '-' || l[len(l)-1] == '-' {
+		return true
+	}
+	return strings.ContainsFunc(l, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-')
+	})
+}
+
+// isTLD reports whether l looks like a top-level domain: letters only.
+func isTLD(l string) bool {
+	return len(l) >= 2 && !strings.ContainsFunc(l, func(r rune) bool { return r < 'a' || r > 'z' })
+}
+
+func (d Def) forge(host string) (Forge, error) {
+	t, ok := builtin[d.Type]
+	if !ok {
+		return Forge{}, fmt.Errorf("unknown type %q (want one of %s)", d.Type, joinKinds())
+	}
+	for _, o := range []struct {
+		tmpl string
+		dst  *string
+	}{
+		{d.Repo, &t.Repo}, {d.Branch, &t.Branch}, {d.Commit, &t.Commit}, {d.Compare, &t.Compare},
+	} {
+		if o.tmpl == "" {
+			continue
+		}
+		if err := checkTemplate(o.tmpl); err != nil {
+			return Forge{}, err
+		}
+		*o.dst = o.tmpl
+	}
+
+	for _, u := range []string{d.URL, d.API} {
+		if p, err := url.Parse(u); u != "" && (err != nil || p.Scheme == "" || p.Host == "") {
+			return Forge{}, fmt.Errorf("%q: want an absolute URL like https://%s", u, host)
+		}
+	}
+
+	f := Forge{Kind: d.Type, Base: strings.TrimSuffix(d.URL, "/"), API: strings.TrimSuffix(d.API, "/"), Templates: t}
+	if f.Base == "" {
+		f.Base = "https://" + host
+	}
+	if f.API == "" {
+		f.API = apiRoot(f.Kind, f.Base)
+	}
+	if f.Token = os.ExpandEnv(d.Token); f.Token == "" {
+		f.Token = envToken(host)
+	}
+	return f, nil
+}
+
+// Check validates the forges of the config.
+func Check(defs map[string]Def) error {
+	for _, host := range slices.Sorted(maps.Keys(defs)) {
+		if _, err := defs[host].forge(host); err != nil {
+			return fmt.Errorf("forges: %s: %w", host, err)
+		}
+	}
+	return nil
+}
+
+var placeholder = regexp.MustCompile(`\{([^{}]*)\}`)
+
+var placeholders = []string{"base", "owner", "repo", "branch", "default", "commit"}
+
+func checkTemplate(tmpl string) error {
+	for _, m := range placeholder.FindAllStringSubmatch(tmpl, -1) {
+		if !slices.Contains(placeholders, m[1]) {
+			return fmt.Errorf("template %q: unknown placeholder {%s}", tmpl, m[1])
+		}
+	}
+	return nil
+}
+
+// Target is the repo, branch and commit a page shows.
+type Target struct {
+	Owner   string // may span several segments, e.g. a GitLab group path
+	Repo    string
+	Branch  string
+	Default string // the default branch
+	Commit  string
+}
+
+// Errors from URL when the target lacks what the page needs.
+var (
+	ErrNoBranch        = errors.New("not on a branch")
+	ErrNoCommit        = errors.New("no commits yet")
+	ErrNoDefault       = errors.New("default branch unknown; run git remote set-head <remote> --auto")
+	ErrOnDefaultBranch = errors.New("already on the default branch")
+)
+
+// URL returns the address of page for t.
+func (f Forge) URL(page Page, t Target) (string, error) {
+	var tmpl string
+	switch page {
+	case RepoPage:
+		tmpl = f.Repo
+	case BranchPage:
+		tmpl = f.Branch
+	case CommitPage:
+		tmpl = f.Commit
+	case ComparePage:
+		tmpl = f.Compare
+	default:
+		return "", fmt.Errorf("unknown page %q", page)
+	}
+
+	switch {
+	case strings.Contains(tmpl, "{branch}") && t.Branch == "":
+		return "", ErrNoBranch
+	case strings.Contains(tmpl, "{commit}") && t.Commit == "":
+		return "", ErrNoCommit
+	case strings.Contains(tmpl, "{default}") && t.Default == "":
+		return "", ErrNoDefault
+	case page == ComparePage && t.Branch == t.Default:
+		return "", ErrOnDefaultBranch
+	}
+
+	values := map[string]string{
+		"base":    f.Base,
+		"owner":   escapePath(t.Owner),
+		"repo":    url.PathEscape(t.Repo),
+		"branch":  escapePath(t.Branch),
+		"default": escapePath(t.Default),
+		"commit":  url.PathEscape(t.Commit),
+	}
+	return placeholder.ReplaceAllStringFunc(tmpl, func(m string) string {
+		return values[m[1:len(m)-1]]
+	}), nil
+}
+
+// escapePath escapes each segment of a slash-separated name, such as
+// feature/login.
+func escapePath(s string) string {
+	segs := strings.Split(s, "/")
+	for i, seg := range segs {
+		segs[i] = url.PathEscape(seg)
+	}
+	return strings.Join(segs, "/")
+}
+
+func joinKinds() string {
+	names := make([]string, len(Kinds))
+	for i, k := range Kinds {
+		names[i] = string(k)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,128 @@
+package forge
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFor(t *testing.T) {
+	defs := map[string]Def{
+		"git.corp.example": {Type: GitLab, URL: "https://code.corp.example/"},
+		"github.com-work":  {Type: GitHub, URL: "https://github.com"},
+	}
+	tests := []struct {
+		host     string
+		wantKind Kind
+		wantBase string
+	}{
+		{"github.com", GitHub, "https://github.com"},
+		{"codeberg.org", Gitea, "https://codeberg.org"},
+		{"gitlab.example.com", GitLab, "https://gitlab.example.com"},
+		{"forgejo.example.com", Gitea, "https://forgejo.example.com"},
+		{"git.Gitea.example.net", Gitea, "https://git.Gitea.example.net"},
+		{"acme.visualstudio.com", Azure, "https://acme.visualstudio.com"},
+		{"git.corp.example", GitLab, "https://code.corp.example"},
+		{"github.com-work", GitHub, "https://github.com"},
+	}
+	for _, tt := range tests {
+		f, ok := For(tt.host, defs)
+		if !ok || f.Kind != tt.wantKind || f.Base != tt.wantBase {
+			t.Errorf("For(%q) = %v %q, %v, want %v %q", tt.host, f.Kind, f.Base, ok, tt.wantKind, tt.wantBase)
+		}
+	}
+
+	if f, _ := For("github.com-work", defs); f.API != "https://api.github.com" {
+		t.Errorf("API of an alias for github.com = %q", f.API)
+	}
+
+	// Unknown hosts, and ssh config aliases that name a forge but aren't
+	// its host, need an entry in defs
+	for _, host := range []string{"git.example.org", "github.com-work", "gitlab-corp", "my-gitlab.example.com", "gitea", "gitlab.internal-1"} {
+		if f, ok := For(host, nil); ok {
+			t.Errorf("For(%q) = %+v, want no match", host, f)
+		}
+	}
+}
+
+func TestForge_URL(t *testing.T) {
+	target := Target{Owner: "group/sub", Repo: "app", Branch: "feature/login #2", Default: "main", Commit: "abc1234"}
+	tests := []struct {
+		host string
+		page Page
+		want string
+	}{
+		{"github.com", RepoPage, "https://github.com/group/sub/app"},
+		{"github.com", BranchPage, "https://github.com/group/sub/app/tree/feature/login%20%232"},
+		{"github.com", CommitPage, "https://github.com/group/sub/app/commit/abc1234"},
+		{"github.com", ComparePage, "https://github.com/group/sub/app/compare/main...feature/login%20%232?expand=1"},
+		{"gitlab.com", BranchPage, "https://gitlab.com/group/sub/app/-/tree/feature/login%20%232"},
+		{"gitlab.com", ComparePage, "https://gitlab.com/group/sub/app/-/merge_requests/new?merge_request[source_branch]=feature/login%20%232&merge_request[target_branch]=main"},
+		{"bitbucket.org", CommitPage, "https://bitbucket.org/group/sub/app/commits/abc1234"},
+		{"codeberg.org", BranchPage, "https://codeberg.org/group/sub/app/src/branch/feature/login%20%232"},
+		{"dev.azure.com", RepoPage, "https://dev.azure.com/group/sub/_git/app"},
+	}
+	for _, tt := range tests {
+		f, _ := For(tt.host, nil)
+		got, err := f.URL(tt.page, target)
+		if err != nil || got != tt.want {
+			t.Errorf("%s %s: URL() = %q, %v, want %q", tt.host, tt.page, got, err, tt.want)
+		}
+	}
+}
+
+func TestForge_URLErrors(t *testing.T) {
+	f, _ := For("github.com", nil)
+	tests := []struct {
+		page   Page
+		target Target
+		want   error
+	}{
+		{BranchPage, Target{Owner: "o", Repo: "r"}, ErrNoBranch},
+		{CommitPage, Target{Owner: "o", Repo: "r", Branch: "main"}, ErrNoCommit},
+		{ComparePage, Target{Owner: "o", Repo: "r", Branch: "feature"}, ErrNoDefault},
+		{ComparePage, Target{Owner: "o", Repo: "r", Branch: "main", Default: "main"}, ErrOnDefaultBranch},
+	}
+	for _, tt := range tests {
+		if _, err := f.URL(tt.page, tt.target); !errors.Is(err, tt.want) {
+			t.Errorf("URL(%s, %+v) error = %v, want %v", tt.page, tt.target, err, tt.want)
+		}
+	}
+}
+
+func TestDef_Templates(t *testing.T) {
+	defs := map[string]Def{
+		"git.corp.example": {
+			Type:      Gitea,
+			Templates: Templates{Commit: "{base}/c/{commit}"},
+		},
+	}
+	if err := Check(defs); err != nil {
+		t.Fatal(err)
+	}
+	f, _ := For("git.corp.example", defs)
+	target := Target{Owner: "o", Repo: "r", Branch: "b", Commit: "abc"}
+	if got, _ := f.URL(CommitPage, target); got != "https://git.corp.example/c/abc" {
+		t.Errorf("overridden commit URL = %q", got)
+	}
+	if got, _ := f.URL(BranchPage, target); got != "https://git.corp.example/o/r/src/branch/b" {
+		t.Errorf("inherited branch URL = %q", got)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		def  Def
+		want string
+	}{
+		{Def{Type: "sourcehut"}, "unknown type"},
+		{Def{Type: GitHub, URL: "code.corp.example"}, "absolute URL"},
+		{Def{Type: GitHub, Templates: Templates{Repo: "{base}/{project}"}}, "unknown placeholder {project}"},
+	}
+	for _, tt := range tests {
+		err := Check(map[string]Def{"git.corp.example": tt.def})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Check(%+v) = %v, want an error containing %q", tt.def, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// gitea reads pull requests, reviews and commit statuses from the Gitea
+// API, which Forgejo and Codeberg serve too.
+type gitea struct {
+	apiClient
+}
+
+// giteaPullPage bounds the recently updated pull requests searched for the
+// branch's, as the API can't filter by head branch.
+const giteaPullPage = 50
+
+func (g *gitea) BranchStatus(ctx context.Context, ref Ref) (*Status, error) {
+	var pulls []struct {
+		Number  int    `json:"number"`
+		Title   string `json:"title"`
+		HTMLURL string `json:"html_url"`
+		State   string `json:"state"` // open or closed
+		Merged  bool   `json:"merged"`
+		Draft   bool   `json:"draft"`
+		Head    struct {
+			Ref  string `json:"ref"`
+			SHA  string `json:"sha"`
+			Repo *struct {
+				Owner struct {
+					Login string `json:"login"`
+				} `json:"owner"`
+			} `json:"repo"` // nil once the fork is deleted
+		} `json:"head"`
+	}
+	q := url.Values{"state": {"all"}, "sort": {"recentupdate"}, "limit": {fmt.Sprint(giteaPullPage)}}
+	if err := g.get(ctx, repoPath(ref.Owner, ref.Repo)+"/pulls?"+q.Encode(), &pulls); err != nil {
+		return nil, err
+	}
+
+	st := &Status{}
+	owner, repo, commit := ref.HeadOwner, ref.HeadRepo, ref.Branch
+	for _, p := range pulls {
+		if p.Head.Ref != ref.Branch || (p.Head.Repo != nil && !strings.EqualFold(p.Head.Repo.Owner.Login, ref.HeadOwner)) {
+			continue
+		}
+		pr := &PullRequest{Number: p.Number, Title: p.Title, URL: p.HTMLURL, State: PROpen}
+		switch {
+		case p.Merged:
+			pr.State = PRMerged
+		case p.State == "closed":
+			pr.State = PRClosed
+		case p.Draft || isWIP(p.Title):
+			pr.State = PRDraft
+		}
+		if pr.State == PROpen || pr.State == PRDraft {
+			pr.Review = g.review(ctx, ref, p.Number)
+		}
+		st.PR = pr
+		owner, repo, commit = ref.Owner, ref.Repo, p.Head.SHA
+		break
+	}
+
+	var combined struct {
+		State      string `json:"state"` // pending, success, error, failure or warning
+		TotalCount int    `json:"total_count"`
+	}
+	if err := g.getChecks(ctx, repoPath(owner, repo)+"/commits/"+url.PathEscape(commit)+"/status", &combined); err != nil {
+		return nil, err
+	}
+	if combined.TotalCount > 0 {
+		st.Checks = statusCheck(combined.State)
+	}
+	return st, nil
+}
+
+// review sums up the latest review of each reviewer. It returns
+// ReviewNone when the reviews can't be read.
+func (g *gitea) review(ctx context.Context, ref Ref, number int) ReviewState {
+	var reviews []struct {
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		State     string `json:"state"` // APPROVED, REQUEST_CHANGES, REQUEST_REVIEW, COMMENT or PENDING
+		Dismissed bool   `json:"dismissed"`
+	}
+	if err := g.get(ctx, fmt.Sprintf("%s/pulls/%d/reviews", repoPath(ref.Owner, ref.Repo), number), &reviews); err != nil {
+		return ReviewNone
+	}
+	latest := make(map[string]string)
+	requested := false
+	for _, r := range reviews {
+		switch {
+		case r.Dismissed:
+			delete(latest, r.User.Login)
+		case r.State == "APPROVED" || r.State == "REQUEST_CHANGES":
+			latest[r.User.Login] = r.State
+		case r.State == "REQUEST_REVIEW":
+			requested = true
+		}
+	}
+	review := sumReviews(latest, "APPROVED", "REQUEST_CHANGES")
+	if review == ReviewNone && requested {
+		review = ReviewRequired
+	}
+	return review
+}
+
+// isWIP reports whether a title marks a pull request as a work in
+// progress, as Gitea does without a draft flag.
+func isWIP(title string) bool {
+	t := strings.ToUpper(title)
+	return strings.HasPrefix(t, "WIP:") || strings.HasPrefix(t, "[WIP]")
+}
@@ -0,0 +1,76 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestGitea_BranchStatus(t *testing.T) {
+	srv := fakeAPI(t, map[string]string{
+		"/repos/org/app/pulls?limit=50&sort=recentupdate&state=all": `[
+			{"number": 3, "title": "Other", "state": "open", "head": {"ref": "feat/y", "sha": "fff"}},
+			{"number": 2, "title": "Theirs", "state": "open", "head": {"ref": "feat/x", "sha": "eee", "repo": {"owner": {"login": "someone"}}}},
+			{"number": 1, "title": "Add x", "html_url": "https://codeberg.org/org/app/pulls/1", "state": "closed", "merged": true,
+			 "head": {"ref": "feat/x", "sha": "abc123", "repo": {"owner": {"login": "Me"}}}}]`,
+		"/repos/org/app/commits/abc123/status": `{"state": "success", "total_count": 2}`,
+	}, func(r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "token tok" {
+			t.Errorf("Authorization = %q", got)
+		}
+	})
+
+	st, err := provider(t, Gitea, srv).BranchStatus(context.Background(), forkRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := PullRequest{Number: 1, Title: "Add x", URL: "https://codeberg.org/org/app/pulls/1", State: PRMerged}
+	if st.PR == nil || *st.PR != want {
+		t.Errorf("PR = %+v, want %+v", st.PR, want)
+	}
+	if st.Checks != ChecksSuccess {
+		t.Errorf("Checks = %q, want success", st.Checks)
+	}
+}
+
+func TestGitea_Review(t *testing.T) {
+	srv := fakeAPI(t, map[string]string{
+		"/repos/org/app/pulls?limit=50&sort=recentupdate&state=all": `[
+			{"number": 4, "title": "WIP: Add x", "state": "open", "head": {"ref": "feat/x", "sha": "abc123"}}]`,
+		"/repos/org/app/pulls/4/reviews": `[
+			{"user": {"login": "a"}, "state": "REQUEST_CHANGES"},
+			{"user": {"login": "b"}, "state": "APPROVED"}]`,
+		"/repos/org/app/commits/abc123/status": `{"state": "", "total_count": 0}`,
+	}, nil)
+
+	st, err := provider(t, Gitea, srv).BranchStatus(context.Background(), forkRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.PR == nil || st.PR.State != PRDraft || st.PR.Review != ReviewChangesRequested || st.Checks != ChecksNone {
+		t.Errorf("status = %+v, PR %+v", st, st.PR)
+	}
+}
+
+func TestGitea_BranchStatusUnpushed(t *testing.T) {
+	srv := fakeAPI(t, map[string]string{
+		"/repos/org/app/pulls?limit=50&sort=recentupdate&state=all": `[]`,
+	}, nil)
+
+	st, err := provider(t, Gitea, srv).BranchStatus(context.Background(), forkRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.PR != nil || st.Checks != ChecksNone {
+		t.Errorf("status = %+v, want no PR and no checks", st)
+	}
+}
+
+func TestGitea_RepoNotFound(t *testing.T) {
+	srv := fakeAPI(t, nil, nil)
+
+	if _, err := provider(t, Gitea, srv).BranchStatus(context.Background(), forkRef); !errors.Is(err, ErrNotFound) {
+		t.Errorf("error = %v, want not found", err)
+	}
+}
@@ -0,0 +1,162 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// github reads pull requests, reviews, commit statuses and check runs from
+// the GitHub REST API, on github.com or GitHub Enterprise.
+type github struct {
+	apiClient
+}
+
+type githubPull struct {
+	Number             int        `json:"number"`
+	Title              string     `json:"title"`
+	HTMLURL            string     `json:"html_url"`
+	State              string     `json:"state"` // open or closed
+	Draft              bool       `json:"draft"`
+	MergedAt           *time.Time `json:"merged_at"`
+	RequestedReviewers []struct{} `json:"requested_reviewers"`
+	Head               struct {
+		SHA string `json:"sha"`
+	} `json:"head"`
+}
+
+func (g *github) BranchStatus(ctx context.Context, ref Ref) (*Status, error) {
+	q := url.Values{"head": {ref.HeadOwner + ":" + ref.Branch}, "state": {"all"}, "per_page": {"1"}}
+	var pulls []githubPull
+	if err := g.get(ctx, repoPath(ref.Owner, ref.Repo)+"/pulls?"+q.Encode(), &pulls); err != nil {
+		return nil, err
+	}
+
+	st := &Status{}
+	owner, repo, commit := ref.HeadOwner, ref.HeadRepo, ref.Branch
+	if len(pulls) > 0 {
+		p := pulls[0]
+		pr := &PullRequest{Number: p.Number, Title: p.Title, URL: p.HTMLURL, State: PROpen}
+		switch {
+		case p.MergedAt != nil:
+			pr.State = PRMerged
+		case p.State == "closed":
+			pr.State = PRClosed
+		case p.Draft:
+			pr.State = PRDraft
+		}
+		if pr.State == PROpen || pr.State == PRDraft {
+			pr.Review = g.review(ctx, ref, p)
+		}
+		st.PR = pr
+		owner, repo, commit = ref.Owner, ref.Repo, p.Head.SHA
+	}
+
+	checks, err := g.checks(ctx, owner, repo, commit)
+	if err != nil {
+		return nil, err
+	}
+	st.Checks = checks
+	return st, nil
+}
+
+// review sums up the latest review of each reviewer. It returns
+// ReviewNone when the reviews can't be read.
+func (g *github) review(ctx context.Context, ref Ref, p githubPull) ReviewState {
+	var reviews []struct {
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		State string `json:"state"`
+	}
+	path := fmt.Sprintf("%s/pulls/%d/reviews?per_page=100", repoPath(ref.Owner, ref.Repo), p.Number)
+	if err := g.get(ctx, path, &reviews); err != nil {
+		return ReviewNone
+	}
+	latest := make(map[string]string)
+	for _, r := range reviews { // oldest first
+		if r.State == "APPROVED" || r.State == "CHANGES_REQUESTED" || r.State == "DISMISSED" {
+			latest[r.User.Login] = r.State
+		}
+	}
+	review := sumReviews(latest, "APPROVED", "CHANGES_REQUESTED")
+	if review == ReviewNone && len(p.RequestedReviewers) > 0 {
+		review = ReviewRequired
+	}
+	return review
+}
+
+// checks combines the commit statuses and check runs of commit, a SHA or
+// branch name.
+func (g *github) checks(ctx context.Context, owner, repo, commit string) (CheckState, error) {
+	base := repoPath(owner, repo) + "/commits/" + url.PathEscape(commit)
+
+	var combined struct {
+		State      string `json:"state"` // pending, success, failure or error
+		TotalCount int    `json:"total_count"`
+	}
+	if err := g.getChecks(ctx, base+"/status", &combined); err != nil {
+		return ChecksNone, err
+	}
+	statuses := ChecksNone
+	if combined.TotalCount > 0 {
+		statuses = statusCheck(combined.State)
+	}
+
+	var runs struct {
+		CheckRuns []struct {
+			Status     string `json:"status"` // queued, in_progress or completed
+			Conclusion string `json:"conclusion"`
+		} `json:"check_runs"`
+	}
+	if err := g.getChecks(ctx, base+"/check-runs?per_page=100", &runs); err != nil {
+		return ChecksNone, err
+	}
+	states := []CheckState{statuses}
+	for _, r := range runs.CheckRuns {
+		switch {
+		case r.Status != "completed":
+			states = append(states, ChecksPending)
+		case r.Conclusion == "success" || r.Conclusion == "neutral" || r.Conclusion == "skipped":
+			states = append(states, ChecksSuccess)
+		default:
+			states = append(states, ChecksFailure)
+		}
+	}
+	return combineChecks(states...), nil
+}
+
+// statusCheck maps a combined commit status, as GitHub and Gitea name
+// them.
+func statusCheck(state string) CheckState {
+	switch state {
+	case "success":
+		return ChecksSuccess
+	case "pending":
+		return ChecksPending
+	case "failure", "error":
+		return ChecksFailure
+	}
+	return ChecksNone
+}
+
+// sumReviews sums up the latest review state of each reviewer: any change
+// request wins over approvals.
+func sumReviews(latest map[string]string, approved, changes string) ReviewState {
+	out := ReviewNone
+	for _, s := range latest {
+		switch s {
+		case changes:
+			return ReviewChangesRequested
+		case approved:
+			out = ReviewApproved
+		}
+	}
+	return out
+}
+
+// repoPath is the API path of a repo on GitHub and Gitea.
+func repoPath(owner, repo string) string {
+	return "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo)
+}
@@ -0,0 +1,167 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fakeAPI serves canned JSON bodies by escaped path and query; anything
+// else is not found.
+func fakeAPI(t *testing.T, routes map[string]string, check func(*http.Request)) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if check != nil {
+			check(r)
+		}
+		key := r.URL.EscapedPath()
+		if r.URL.RawQuery != "" {
+			key += "?" + r.URL.RawQuery
+		}
+		body, ok := routes[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func provider(t *testing.T, kind Kind, srv *httptest.Server) Provider {
+	t.Helper()
+	p, err := Forge{Kind: kind, API: srv.URL, Token: "tok"}.Provider(srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+var forkRef = Ref{Owner: "org", Repo: "app", HeadOwner: "me", HeadRepo: "app", Branch: "feat/x"}
+
+func TestGitHub_BranchStatus(t *testing.T) {
+	srv := fakeAPI(t, map[string]string{
+		"/repos/org/app/pulls?head=me%3Afeat%2Fx&per_page=1&state=all": `[{
+			"number": 42, "title": "Add x", "html_url": "https://github.com/org/app/pull/42",
+			"state": "open", "draft": false, "merged_at": null, "requested_reviewers": [],
+			"head": {"sha": "abc123"}}]`,
+		"/repos/org/app/pulls/42/reviews?per_page=100": `[
+			{"user": {"login": "a"}, "state": "CHANGES_REQUESTED"},
+			{"user": {"login": "a"}, "state": "APPROVED"},
+			{"user": {"login": "b"}, "state": "COMMENTED"}]`,
+		"/repos/org/app/commits/abc123/status":                  `{"state": "success", "total_count": 1}`,
+		"/repos/org/app/commits/abc123/check-runs?per_page=100": `{"check_runs": [{"status": "in_progress", "conclusion": null}]}`,
+	}, func(r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+			t.Errorf("Authorization = %q", got)
+		}
+	})
+
+	st, err := provider(t, GitHub, srv).BranchStatus(context.Background(), forkRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := PullRequest{Number: 42, Title: "Add x", URL: "https://github.com/org/app/pull/42", State: PROpen, Review: ReviewApproved}
+	if st.PR == nil || *st.PR != want {
+		t.Errorf("PR = %+v, want %+v", st.PR, want)
+	}
+	if st.Checks != ChecksPending {
+		t.Errorf("Checks = %q, want pending", st.Checks)
+	}
+}
+
+func TestGitHub_BranchStatusWithoutPR(t *testing.T) {
+	srv := fakeAPI(t, map[string]string{
+		"/repos/org/app/pulls?head=me%3Afeat%2Fx&per_page=1&state=all": `[]`,
+		"/repos/me/app/commits/feat%2Fx/status":                        `{"state": "pending", "total_count": 0}`,
+		"/repos/me/app/commits/feat%2Fx/check-runs?per_page=100":       `{"check_runs": [{"status": "completed", "conclusion": "success"}, {"status": "completed", "conclusion": "timed_out"}]}`,
+	}, nil)
+
+	st, err := provider(t, GitHub, srv).BranchStatus(context.Background(), forkRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.PR != nil || st.Checks != ChecksFailure {
+		t.Errorf("status = %+v, want no PR and failing checks", st)
+	}
+}
+
+func TestGitHub_BranchStatusUnpushed(t *testing.T) {
+	// The branch is on neither repo: its commits aren't found
+	srv := fakeAPI(t, map[string]string{
+		"/repos/org/app/pulls?head=me%3Afeat%2Fx&per_page=1&state=all": `[]`,
+	}, nil)
+
+	st, err := provider(t, GitHub, srv).BranchStatus(context.Background(), forkRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.PR != nil || st.Checks != ChecksNone {
+		t.Errorf("status = %+v, want no PR and no checks", st)
+	}
+}
+
+func TestGitHub_RateLimit(t *testing.T) {
+	reset := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	_, err := provider(t, GitHub, srv).BranchStatus(context.Background(), forkRef)
+	var rl *RateLimitError
+	if !errors.As(err, &rl) || !rl.Reset.Equal(reset) {
+		t.Errorf("error = %v, want a rate limit until %v", err, reset)
+	}
+}
+
+func TestCheckResponse(t *testing.T) {
+	tests := []struct {
+		code   int
+		header http.Header
+		want   error
+	}{
+		{http.StatusUnauthorized, nil, ErrUnauthorized},
+		{http.StatusNotFound, nil, ErrNotFound},
+		{http.StatusForbidden, http.Header{"X-Ratelimit-Remaining": {"3"}}, nil},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		for k, v := range tt.header {
+			rec.Header()[k] = v
+		}
+		rec.WriteHeader(tt.code)
+		err := checkResponse(rec.Result())
+		if tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%d: error = %v, want %v", tt.code, err, tt.want)
+		}
+		if tt.want == nil && (err == nil || errors.As(err, new(*RateLimitError))) {
+			t.Errorf("%d: error = %v, want a plain error", tt.code, err)
+		}
+	}
+}
+
+func TestRateLimitReset(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	tests := []struct {
+		header http.Header
+		want   time.Time
+	}{
+		{http.Header{"Retry-After": {"30"}}, now.Add(30 * time.Second)},
+		{http.Header{"Ratelimit-Reset": {"1700000600"}}, time.Unix(1_700_000_600, 0)},
+		{http.Header{}, now.Add(defaultRetry)},
+	}
+	for _, tt := range tests {
+		if got := rateLimitReset(tt.header, now); !got.Equal(tt.want) {
+			t.Errorf("rateLimitReset(%v) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
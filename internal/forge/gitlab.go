@@ -0,0 +1,100 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// gitlab reads merge requests, approvals and pipelines from the GitLab
+// REST API.
+type gitlab struct {
+	apiClient
+}
+
+func (g *gitlab) BranchStatus(ctx context.Context, ref Ref) (*Status, error) {
+	project := projectPath(ref.Owner, ref.Repo)
+	q := url.Values{"source_branch": {ref.Branch}, "state": {"all"}, "order_by": {"updated_at"}, "per_page": {"1"}}
+	var mrs []struct {
+		IID    int    `json:"iid"`
+		Title  string `json:"title"`
+		WebURL string `json:"web_url"`
+		State  string `json:"state"` // opened, closed, locked or merged
+		Draft  bool   `json:"draft"`
+		SHA    string `json:"sha"`
+	}
+	if err := g.get(ctx, project+"/merge_requests?"+q.Encode(), &mrs); err != nil {
+		return nil, err
+	}
+
+	st := &Status{}
+	pipelines := url.Values{"ref": {ref.Branch}}
+	if len(mrs) > 0 {
+		mr := mrs[0]
+		pr := &PullRequest{Number: mr.IID, Title: mr.Title, URL: mr.WebURL, State: PROpen}
+		switch {
+		case mr.State == "merged":
+			pr.State = PRMerged
+		case mr.State == "closed" || mr.State == "locked":
+			pr.State = PRClosed
+		case mr.Draft:
+			pr.State = PRDraft
+		}
+		if pr.State == PROpen || pr.State == PRDraft {
+			pr.Review = g.review(ctx, project, mr.IID)
+		}
+		st.PR = pr
+		pipelines = url.Values{"sha": {mr.SHA}}
+	} else {
+		project = projectPath(ref.HeadOwner, ref.HeadRepo)
+	}
+
+	pipelines.Set("per_page", "1")
+	var ps []struct {
+		Status string `json:"status"`
+	}
+	if err := g.getChecks(ctx, project+"/pipelines?"+pipelines.Encode(), &ps); err != nil {
+		return nil, err
+	}
+	if len(ps) > 0 {
+		st.Checks = pipelineCheck(ps[0].Status)
+	}
+	return st, nil
+}
+
+// review reads the approvals of merge request iid. It returns ReviewNone
+// when they can't be read.
+func (g *gitlab) review(ctx context.Context, project string, iid int) ReviewState {
+	var a struct {
+		Approved      bool `json:"approved"`
+		ApprovalsLeft int  `json:"approvals_left"`
+	}
+	if err := g.get(ctx, fmt.Sprintf("%s/merge_requests/%d/approvals", project, iid), &a); err != nil {
+		return ReviewNone
+	}
+	switch {
+	case a.ApprovalsLeft > 0:
+		return ReviewRequired
+	case a.Approved:
+		return ReviewApproved
+	}
+	return ReviewNone
+}
+
+// pipelineCheck maps a pipeline status.
+func pipelineCheck(status string) CheckState {
+	switch status {
+	case "success":
+		return ChecksSuccess
+	case "failed", "canceled":
+		return ChecksFailure
+	case "created", "waiting_for_resource", "preparing", "pending", "running", "scheduled":
+		return ChecksPending
+	}
+	return ChecksNone // skipped, manual
+}
+
+// projectPath is the API path of a project, addressed by its full path.
+func projectPath(owner, repo string) string {
+	return "/projects/" + url.PathEscape(owner+"/"+repo)
+}
@@ -0,0 +1,48 @@
+package forge
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestGitLab_BranchStatus(t *testing.T) {
+	srv := fakeAPI(t, map[string]string{
+		"/projects/org%2Fapp/merge_requests?order_by=updated_at&per_page=1&source_branch=feat%2Fx&state=all": `[{
+			"iid": 7, "title": "Draft: Add x", "web_url": "https://gitlab.com/org/app/-/merge_requests/7",
+			"state": "opened", "draft": true, "sha": "abc123"}]`,
+		"/projects/org%2Fapp/merge_requests/7/approvals":      `{"approved": false, "approvals_left": 1}`,
+		"/projects/org%2Fapp/pipelines?per_page=1&sha=abc123": `[{"status": "failed"}]`,
+	}, func(r *http.Request) {
+		if got := r.Header.Get("PRIVATE-TOKEN"); got != "tok" {
+			t.Errorf("PRIVATE-TOKEN = %q", got)
+		}
+	})
+
+	st, err := provider(t, GitLab, srv).BranchStatus(context.Background(), forkRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := PullRequest{Number: 7, Title: "Draft: Add x", URL: "https://gitlab.com/org/app/-/merge_requests/7", State: PRDraft, Review: ReviewRequired}
+	if st.PR == nil || *st.PR != want {
+		t.Errorf("PR = %+v, want %+v", st.PR, want)
+	}
+	if st.Checks != ChecksFailure {
+		t.Errorf("Checks = %q, want failure", st.Checks)
+	}
+}
+
+func TestGitLab_BranchStatusWithoutMR(t *testing.T) {
+	srv := fakeAPI(t, map[string]string{
+		"/projects/org%2Fapp/merge_requests?order_by=updated_at&per_page=1&source_branch=feat%2Fx&state=all": `[]`,
+		"/projects/me%2Fapp/pipelines?per_page=1&ref=feat%2Fx":                                               `[{"status": "running"}]`,
+	}, nil)
+
+	st, err := provider(t, GitLab, srv).BranchStatus(context.Background(), forkRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.PR != nil || st.Checks != ChecksPending {
+		t.Errorf("status = %+v, want no MR and a running pipeline", st)
+	}
+}
@@ -0,0 +1,129 @@
+// Package fuzzy implements fzf-style fuzzy matching: a pattern matches any
+// text containing its characters in order, and matches that are
+// contiguous or start at word boundaries score higher.
+package fuzzy
+
+import (
+	"unicode"
+)
+
+// Scoring, in the spirit of fzf's v1 algorithm
+const (
+	scoreMatch        = 16
+	scoreGapStart     = -3
+	scoreGapExtension = -1
+
+	bonusBoundary    = scoreMatch / 2 // after a separator or at the start
+	bonusCamel       = bonusBoundary - 1
+	bonusConsecutive = -(scoreGapStart + scoreGapExtension)
+	bonusFirstChar   = 2 // multiplier for the bonus of the first pattern character
+)
+
+// Result is a successful match.
+type Result struct {
+	Score     int
+	Positions []int // rune indexes of the matched characters in the text
+}
+
+// Match matches pattern against text, ignoring case. ok is false when
+// text does not contain every character of pattern in order. An empty
+// pattern matches everything with a zero score.
+func Match(pattern, text string) (res Result, ok bool) {
+	p := []rune(pattern)
+	if len(p) == 0 {
+		return Result{}, true
+	}
+	t := []rune(text)
+
+	// Find the first position where the whole pattern has been seen...
+	pi, end := 0, -1
+	for i, c := range t {
+		if fold(c) == fold(p[pi]) {
+			pi++
+			if pi == len(p) {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return Result{}, false
+	}
+
+	// ...then walk back to the latest start, giving the shortest window
+	// ending there.
+	pi, start := len(p)-1, end
+	for i := end; i >= 0; i-- {
+		if fold(t[i]) == fold(p[pi]) {
+			pi--
+			if pi < 0 {
+				start = i
+				break
+			}
+		}
+	}
+
+	res.Positions = make([]int, 0, len(p))
+	pi = 0
+	inGap, consecutive := false, 0
+	firstBonus := 0
+	for i := start; i <= end && pi < len(p); i++ {
+		if fold(t[i]) != fold(p[pi]) {
+			if inGap {
+				res.Score += scoreGapExtension
+			} else {
+				res.Score += scoreGapStart
+			}
+			inGap, consecutive = true, 0
+			continue
+		}
+
+		bonus := bonusAt(t, i)
+		if consecutive == 0 {
+			firstBonus = bonus
+		} else {
+			// A run keeps the bonus of its first character
+			if bonus == bonusBoundary {
+				firstBonus = bonus
+			}
+			bonus = max(bonus, firstBonus, bonusConsecutive)
+		}
+		if pi == 0 {
+			bonus *= bonusFirstChar
+		}
+		res.Score += scoreMatch + bonus
+		res.Positions = append(res.Positions, i)
+		inGap = false
+		consecutive++
+		pi++
+	}
+	return res, true
+}
+
+// bonusAt rewards matching the character at i of t by where it sits.
+func bonusAt(t []rune, i int) int {
+	if i == 0 {
+		return bonusBoundary
+	}
+	prev, cur := t[i-1], t[i]
+	switch {
+	case isSeparator(prev) && !isSeparator(cur):
+		return bonusBoundary
+	case unicode.IsLower(prev) && unicode.IsUpper(cur),
+		!unicode.IsDigit(prev) && unicode.IsDigit(cur):
+		return bonusCamel
+	}
+	return 0
+}
+
+func isSeparator(c rune) bool {
+	switch c {
+	case '/', '\\', '-', '_', '.', ' ', ':':
+		return true
+	}
+	return false
+}
+
+func fold(c rune) rune {
+	return unicode.ToLower(c)
+}
@@ -0,0 +1,63 @@
+package fuzzy
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, text string
+		ok            bool
+		positions     []int
+	}{
+		{"", "anything", true, nil},
+		{"gvtu", "gv/tui", true, []int{0, 1, 3, 4}},
+		{"GVTU", "gv/tui", true, []int{0, 1, 3, 4}},
+		{"tui", "gv/tui", true, []int{3, 4, 5}},
+		{"abc", "a-b-c", true, []int{0, 2, 4}},
+		{"cba", "abc", false, nil},
+		{"x", "", false, nil},
+		// The shortest window wins over the first occurrence
+		{"ab", "a..xab", true, []int{4, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.text, func(t *testing.T) {
+			res, ok := Match(tt.pattern, tt.text)
+			if ok != tt.ok {
+				t.Fatalf("Match() ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && !slices.Equal(res.Positions, tt.positions) {
+				t.Errorf("Positions = %v, want %v", res.Positions, tt.positions)
+			}
+		})
+	}
+}
+
+func TestMatch_Ranking(t *testing.T) {
+	// Each pattern should score the texts in the order given
+	tests := []struct {
+		pattern string
+		texts   []string
+	}{
+		{"gv", []string{"gv", "gv-tools", "git-view", "good-vibes-long-name", "legacy-v2"}},
+		{"tui", []string{"tui", "gv/tui", "t-u-i", "stuff-ui"}},
+		{"api", []string{"api", "acme-api", "a-pi-x", "rapid"}},
+		{"fb", []string{"FooBar", "foobar"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			prev := 1 << 30
+			for _, text := range tt.texts {
+				res, ok := Match(tt.pattern, text)
+				if !ok {
+					t.Fatalf("Match(%q, %q) did not match", tt.pattern, text)
+				}
+				if res.Score > prev {
+					t.Errorf("Match(%q, %q) score %d above the previous text's %d", tt.pattern, text, res.Score, prev)
+				}
+				prev = res.Score
+			}
+		})
+	}
+}
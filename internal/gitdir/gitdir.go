@@ -0,0 +1,186 @@
+// Package gitdir locates the git directories behind a path without running
+// git. It understands .git directories, .git files (linked worktrees,
+// --separate-git-dir, submodules), commondir indirection and bare repos.
+package gitdir
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Layout describes where the git data of a repository lives.
+type Layout struct {
+	GitDir    string // HEAD, index and per-worktree state
+	CommonDir string // objects, refs and config; equal to GitDir outside linked worktrees
+	Bare      bool   // no working tree of its own
+	Linked    bool   // linked worktree: GitDir has a commondir file or sits in worktrees/
+
+	// SuperGitDir is the git dir of the superproject when GitDir lives in
+	// its modules/ directory, i.e. this is a submodule.
+	SuperGitDir string
+}
+
+// Resolve returns the layout of the repository rooted at path, or nil if
+// path is not the root of a repository.
+func Resolve(path string) (*Layout, error) {
+	dotGit := filepath.Join(path, ".git")
+	info, err := os.Stat(dotGit)
+
+	l := &Layout{}
+	switch {
+	case err == nil && info.IsDir():
+		l.GitDir = dotGit
+	case err == nil:
+		gd, err := ReadGitFile(dotGit)
+		if err != nil {
+			return nil, err
+		}
+		if gd == "" {
+			return nil, nil
+		}
+		l.GitDir = gd
+	case os.IsNotExist(err):
+		if !IsGitDir(path) {
+			return nil, nil
+		}
+		l.GitDir = path
+		l.Bare = true
+	default:
+		return nil, err
+	}
+
+	l.CommonDir = l.GitDir
+	if content, err := os.ReadFile(filepath.Join(l.GitDir, "commondir")); err == nil {
+		l.CommonDir = resolveRel(l.GitDir, strings.TrimSpace(string(content)))
+		l.Linked = true
+	} else if !l.Bare && filepath.Base(filepath.Dir(l.GitDir)) == "worktrees" {
+		// Linked worktree whose admin dir is missing or pruned
+		l.CommonDir = filepath.Dir(filepath.Dir(l.GitDir))
+		l.Linked = true
+	}
+	if !l.Linked && !l.Bare {
+		// e.g. a .git file pointing at a bare repo that hosts worktrees
+		l.Bare = ConfigBool(filepath.Join(l.CommonDir, "config"), "core", "bare")
+	}
+	l.SuperGitDir = superGitDir(l.CommonDir)
+	return l, nil
+}
+
+// superGitDir returns the git dir whose modules/ directory holds gitDir.
+// Submodule names may contain slashes, so every ancestor is considered.
+func superGitDir(gitDir string) string {
+	for dir := filepath.Dir(gitDir); ; dir = filepath.Dir(dir) {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		if filepath.Base(dir) == "modules" && IsGitDir(parent) {
+			return parent
+		}
+	}
+}
+
+// ReadGitFile returns the absolute directory named by a "gitdir: <path>"
+// file. Relative paths are resolved against the file's directory.
+func ReadGitFile(file string) (string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	line := strings.TrimSpace(string(content))
+	if !strings.HasPrefix(line, "gitdir:") {
+		return "", nil
+	}
+	return resolveRel(filepath.Dir(file), strings.TrimSpace(strings.TrimPrefix(line, "gitdir:"))), nil
+}
+
+// IsGitDir reports whether dir looks like a git directory: a HEAD file
+// next to objects and refs directories.
+func IsGitDir(dir string) bool {
+	if info, err := os.Stat(filepath.Join(dir, "HEAD")); err != nil || info.IsDir() {
+		return false
+	}
+	for _, sub := range []string{"objects", "refs"} {
+		if info, err := os.Stat(filepath.Join(dir, sub)); err != nil || !info.IsDir() {
+			return false
+		}
+	}
+	return true
+}
+
+// Owner returns the repository path that commonDir belongs to: the
+// configured core.worktree (set for submodules), the directory whose .git
+// entry resolves to commonDir (a normal repo or a bare repo behind a .git
+// file), or commonDir itself for a plain bare repo or a separated git dir.
+func Owner(commonDir string) string {
+	if wt, ok := ConfigValue(filepath.Join(commonDir, "config"), "core", "worktree"); ok && wt != "" {
+		return resolveRel(commonDir, wt)
+	}
+	parent := filepath.Dir(commonDir)
+	if l, err := Resolve(parent); err == nil && l != nil && filepath.Clean(l.GitDir) == filepath.Clean(commonDir) {
+		return parent
+	}
+	return commonDir
+}
+
+// ConfigBool reads a boolean from a git config file. A key without a
+// value is true.
+func ConfigBool(file, section, key string) bool {
+	v, ok := ConfigValue(file, section, key)
+	if !ok {
+		return false
+	}
+	switch strings.ToLower(v) {
+	case "", "true", "yes", "on", "1":
+		return true
+	default:
+		return false
+	}
+}
+
+// ConfigValue reads the last value of section.key from a git config file.
+// Only plain "[section]" headers are recognized; includes are not followed.
+func ConfigValue(file, section, key string) (string, bool) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", false
+	}
+	defer func() { _ = f.Close() }()
+
+	inSection := false
+	value, found := "", false
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' {
+			name := strings.TrimSpace(strings.Trim(line, "[]"))
+			inSection = strings.EqualFold(name, section)
+			continue
+		}
+		if !inSection {
+			continue
+		}
+
+		k, v, _ := strings.Cut(line, "=")
+		if !strings.EqualFold(strings.TrimSpace(k), key) {
+			continue
+		}
+		if i := strings.IndexAny(v, "#;"); i >= 0 {
+			v = v[:i]
+		}
+		value, found = strings.Trim(strings.TrimSpace(v), `"`), true
+	}
+	return value, found
+}
+
+func resolveRel(base, p string) string {
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(base, p)
+	}
+	return filepath.Clean(p)
+}
@@ -0,0 +1,146 @@
+package gitdir
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolve_Layouts(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+	tmp := t.TempDir()
+
+	// Normal repo with a linked worktree
+	normal := filepath.Join(tmp, "normal")
+	runGit(t, tmp, "init", "-q", normal)
+	commit(t, normal)
+	wt := filepath.Join(tmp, "normal-wt")
+	runGit(t, normal, "worktree", "add", "-q", wt)
+
+	// Bare repo with a linked worktree
+	bare := filepath.Join(tmp, "bare.git")
+	runGit(t, tmp, "clone", "-q", "--bare", normal, bare)
+	bareWt := filepath.Join(tmp, "bare-wt")
+	runGit(t, bare, "worktree", "add", "-q", bareWt, "HEAD")
+
+	// Bare repo hidden behind a .git file (the ".bare" layout)
+	container := filepath.Join(tmp, "container")
+	runGit(t, tmp, "clone", "-q", "--bare", normal, filepath.Join(container, ".bare"))
+	if err := os.WriteFile(filepath.Join(container, ".git"), []byte("gitdir: ./.bare\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Separate git dir
+	separate := filepath.Join(tmp, "separate")
+	store := filepath.Join(tmp, "store", "separate.git")
+	if err := os.MkdirAll(filepath.Dir(store), 0755); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, tmp, "init", "-q", "--separate-git-dir", store, separate)
+
+	tests := []struct {
+		name      string
+		path      string
+		gitDir    string
+		commonDir string
+		bare      bool
+		linked    bool
+		owner     string
+	}{
+		{"normal", normal, filepath.Join(normal, ".git"), filepath.Join(normal, ".git"), false, false, normal},
+		{"worktree", wt, filepath.Join(normal, ".git", "worktrees", "normal-wt"), filepath.Join(normal, ".git"), false, true, normal},
+		{"bare", bare, bare, bare, true, false, bare},
+		{"bare worktree", bareWt, filepath.Join(bare, "worktrees", "bare-wt"), bare, false, true, bare},
+		{"bare behind .git file", container, filepath.Join(container, ".bare"), filepath.Join(container, ".bare"), true, false, container},
+		{"separate git dir", separate, store, store, false, false, store},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := Resolve(tt.path)
+			if err != nil || l == nil {
+				t.Fatalf("Resolve() = %v, %v", l, err)
+			}
+			if !sameDir(l.GitDir, tt.gitDir) {
+				t.Errorf("GitDir = %q, want %q", l.GitDir, tt.gitDir)
+			}
+			if !sameDir(l.CommonDir, tt.commonDir) {
+				t.Errorf("CommonDir = %q, want %q", l.CommonDir, tt.commonDir)
+			}
+			if l.Bare != tt.bare || l.Linked != tt.linked {
+				t.Errorf("Bare, Linked = %v, %v; want %v, %v", l.Bare, l.Linked, tt.bare, tt.linked)
+			}
+			if got := Owner(l.CommonDir); !sameDir(got, tt.owner) {
+				t.Errorf("Owner() = %q, want %q", got, tt.owner)
+			}
+		})
+	}
+
+	t.Run("not a repo", func(t *testing.T) {
+		if l, err := Resolve(tmp); l != nil || err != nil {
+			t.Errorf("Resolve() = %v, %v; want nil, nil", l, err)
+		}
+	})
+}
+
+func TestConfigBool(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"missing section", "[core]\n\tbare = false\n", false},
+		{"true", "[core]\n\tbare = false\n[gv]\n\tignore = true\n", true},
+		{"case insensitive", "[GV]\n\tIgnore = Yes\n", true},
+		{"bare key", "[gv]\n\tignore\n", true},
+		{"false", "[gv]\n\tignore = false\n", false},
+		{"last value wins", "[gv]\n\tignore = true\n[gv]\n\tignore = false\n", false},
+		{"inline comment", "[gv]\n\tignore = true # hidden from dashboards\n", true},
+		{"other section", "[other]\n\tignore = true\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "config")
+			if err := os.WriteFile(file, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if got := ConfigBool(file, "gv", "ignore"); got != tt.want {
+				t.Errorf("ConfigBool() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@test.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@test.com")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+func commit(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte("hi\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", "README")
+	runGit(t, dir, "commit", "-q", "-m", "initial")
+}
+
+// sameDir compares paths after resolving symlinks (macOS temp dirs).
+func sameDir(a, b string) bool {
+	ra, errA := filepath.EvalSymlinks(a)
+	rb, errB := filepath.EvalSymlinks(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return ra == rb
+}
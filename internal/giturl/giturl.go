@@ -0,0 +1,102 @@
+// Package giturl splits git remote URLs into the host, owner and repository
+// name that hosting services address a repo by.
+package giturl
+
+import (
+	"slices"
+	"strings"
+)
+
+// URL is a remote URL reduced to where the repository lives.
+type URL struct {
+	Host  string // Without user or port, e.g. "github.com"
+	Owner string // User, org, GitLab group path ("group/subgroup") or Azure DevOps "org/project"; empty for a top-level Gerrit project
+	Repo  string // Repository name without .git
+}
+
+// gerritSSHPort is the port Gerrit serves git over SSH on.
+const gerritSSHPort = "29418"
+
+// Parse splits a remote URL as git prints it, that is after insteadOf
+// rewrites. It understands scp-like (git@host:path), ssh://, git:// and
+// http(s):// URLs, plus the quirks of some hosts:
+//   - Azure DevOps: the _git path segment and the v3/ prefix of SSH URLs are
+//     dropped, and ssh.dev.azure.com becomes dev.azure.com
+//   - Gerrit: the a/ prefix of authenticated HTTP URLs is dropped on hosts
+//     that look like Gerrit (SSH port 29418, or "review" or "gerrit" in the
+//     name, or googlesource.com)
+//
+// It reports false for local paths, file:// URLs and URLs without a path.
+func Parse(raw string) (URL, bool) {
+	raw = strings.TrimSpace(raw)
+	var scheme, authority, path string
+	if s, rest, ok := strings.Cut(raw, "://"); ok {
+		scheme = strings.ToLower(s)
+		authority, path, _ = strings.Cut(rest, "/")
+	} else if a, p, ok := strings.Cut(raw, ":"); ok && !strings.Contains(a, "/") && len(a) > 1 {
+		// scp-like; a single letter before the colon is a Windows drive
+		scheme, authority, path = "ssh", a, p
+	}
+	if scheme == "" || scheme == "file" || authority == "" {
+		return URL{}, false
+	}
+
+	if i := strings.LastIndex(authority, "@"); i >= 0 {
+		authority = authority[i+1:]
+	}
+	host, port := authority, ""
+	if i := strings.LastIndex(authority, ":"); i >= 0 && !strings.HasSuffix(authority, "]") {
+		host, port = authority[:i], authority[i+1:]
+	}
+	host = strings.ToLower(host)
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	segs := strings.FieldsFunc(path, func(r rune) bool { return r == '/' })
+
+	switch {
+	case host == "ssh.dev.azure.com", host == "vs-ssh.visualstudio.com":
+		host = "dev.azure.com"
+		if len(segs) > 0 && segs[0] == "v3" {
+			segs = segs[1:]
+		}
+	case host == "dev.azure.com", strings.HasSuffix(host, ".visualstudio.com"):
+		if len(segs) > 0 && strings.EqualFold(segs[0], "DefaultCollection") {
+			segs = segs[1:]
+		}
+		segs = slices.DeleteFunc(segs, func(s string) bool { return s == "_git" })
+	case isGerrit(host, port):
+		if (scheme == "http" || scheme == "https") && len(segs) > 1 && segs[0] == "a" {
+			segs = segs[1:]
+		}
+	}
+
+	if len(segs) == 0 {
+		return URL{}, false
+	}
+	return URL{
+		Host:  host,
+		Owner: strings.Join(segs[:len(segs)-1], "/"),
+		Repo:  segs[len(segs)-1],
+	}, true
+}
+
+// TopOwner returns the first segment of the owner: the user or org on
+// GitHub, the top-level group on GitLab, the org on Azure DevOps.
+func (u URL) TopOwner() string {
+	top, _, _ := strings.Cut(u.Owner, "/")
+	return top
+}
+
+// Slug returns owner/repo, or just the repo when there is no owner.
+func (u URL) Slug() string {
+	if u.Owner == "" {
+		return u.Repo
+	}
+	return u.Owner + "/" + u.Repo
+}
+
+func isGerrit(host, port string) bool {
+	return port == gerritSSHPort ||
+		strings.Contains(host, "review") || strings.Contains(host, "gerrit") ||
+		strings.HasSuffix(host, ".googlesource.com")
+}
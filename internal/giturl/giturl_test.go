@@ -0,0 +1,109 @@
+package giturl
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want URL
+	}{
+		{
+			name: "SSH colon format",
+			url:  "git@github.com:jackchuka/gv.git",
+			want: URL{Host: "github.com", Owner: "jackchuka", Repo: "gv"},
+		},
+		{
+			name: "SSH URL with port",
+			url:  "ssh://git@github.com:22/jackchuka/gv.git",
+			want: URL{Host: "github.com", Owner: "jackchuka", Repo: "gv"},
+		},
+		{
+			name: "HTTPS with user and trailing slash",
+			url:  "https://me@GitHub.com/jackchuka/gv/",
+			want: URL{Host: "github.com", Owner: "jackchuka", Repo: "gv"},
+		},
+		{
+			name: "git protocol",
+			url:  "git://example.org/tools/gv",
+			want: URL{Host: "example.org", Owner: "tools", Repo: "gv"},
+		},
+		{
+			name: "GitLab subgroups",
+			url:  "git@gitlab.com:group/subgroup/deeper/repo.git",
+			want: URL{Host: "gitlab.com", Owner: "group/subgroup/deeper", Repo: "repo"},
+		},
+		{
+			name: "Azure DevOps HTTPS",
+			url:  "https://acme@dev.azure.com/acme/Platform/_git/api",
+			want: URL{Host: "dev.azure.com", Owner: "acme/Platform", Repo: "api"},
+		},
+		{
+			name: "Azure DevOps SSH",
+			url:  "git@ssh.dev.azure.com:v3/acme/Platform/api",
+			want: URL{Host: "dev.azure.com", Owner: "acme/Platform", Repo: "api"},
+		},
+		{
+			name: "Azure DevOps legacy host",
+			url:  "https://acme.visualstudio.com/DefaultCollection/Platform/_git/api",
+			want: URL{Host: "acme.visualstudio.com", Owner: "Platform", Repo: "api"},
+		},
+		{
+			name: "Gerrit SSH",
+			url:  "ssh://me@gerrit.example.com:29418/platform/build",
+			want: URL{Host: "gerrit.example.com", Owner: "platform", Repo: "build"},
+		},
+		{
+			name: "Gerrit authenticated HTTP",
+			url:  "https://review.example.com/a/platform/build",
+			want: URL{Host: "review.example.com", Owner: "platform", Repo: "build"},
+		},
+		{
+			name: "Gerrit top-level project",
+			url:  "https://android.googlesource.com/a/manifest",
+			want: URL{Host: "android.googlesource.com", Repo: "manifest"},
+		},
+		{
+			name: "a/ kept elsewhere",
+			url:  "https://github.com/a/repo",
+			want: URL{Host: "github.com", Owner: "a", Repo: "repo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Parse(tt.url)
+			if !ok || got != tt.want {
+				t.Errorf("Parse(%q) = %+v, %v, want %+v", tt.url, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Rejects(t *testing.T) {
+	for _, url := range []string{
+		"",
+		"/srv/git/gv.git",
+		"../gv",
+		"file:///srv/git/gv.git",
+		`C:\src\gv`,
+		"https://github.com",
+	} {
+		if got, ok := Parse(url); ok {
+			t.Errorf("Parse(%q) = %+v, want no match", url, got)
+		}
+	}
+}
+
+func TestURL_TopOwnerAndSlug(t *testing.T) {
+	u := URL{Host: "gitlab.com", Owner: "group/subgroup", Repo: "repo"}
+	if got := u.TopOwner(); got != "group" {
+		t.Errorf("TopOwner() = %q, want group", got)
+	}
+	if got := u.Slug(); got != "group/subgroup/repo" {
+		t.Errorf("Slug() = %q, want group/subgroup/repo", got)
+	}
+	if got := (URL{Repo: "manifest"}).Slug(); got != "manifest" {
+		t.Errorf("Slug() = %q, want manifest", got)
+	}
+}
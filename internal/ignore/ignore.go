@@ -0,0 +1,226 @@
+// Package ignore implements gitignore-style path matching for
+// ignore_patterns and .gvignore files.
+//
+// Patterns follow gitignore rules with a few additions for absolute paths:
+//
+//   - "**" matches any number of directories, anywhere in the pattern;
+//     within a segment it behaves like "*"
+//   - "*", "?" and character classes ("[a-z]", "[!0-9]") match within a
+//     single path segment
+//   - a leading "!" re-includes paths excluded by an earlier pattern; the
+//     last matching pattern wins
+//   - a pattern without a slash (other than a trailing one) matches at any
+//     depth; one with a slash is anchored to the scan root
+//   - a pattern starting with "~/" or "/" is matched against the absolute
+//     path; a leading "/" also anchors to the scan root as in gitignore
+//   - "dir/**" also matches "dir" itself, so whole trees are pruned,
+//     unless a later "!" pattern may re-include something inside it
+//
+// A path is ignored when it, or any of its parent directories below the
+// root, is ignored.
+package ignore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+type pattern struct {
+	negate   bool
+	anchored bool // matched against the whole relative path
+	absolute bool // matched against the absolute path (and, for "/x", the relative one)
+	self     bool // a trailing "**" also matches the directory itself
+	segs     []string
+}
+
+// Matcher matches paths against an ordered list of patterns.
+type Matcher struct {
+	patterns []pattern
+}
+
+// New compiles patterns. Blank lines and lines starting with "#" are
+// skipped. Invalid patterns are left out and reported in the error; the
+// returned Matcher is usable either way.
+func New(patterns []string) (*Matcher, error) {
+	m := &Matcher{}
+	var errs []error
+	for _, raw := range patterns {
+		p, ok, err := compile(raw)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if ok {
+			m.patterns = append(m.patterns, p)
+		}
+	}
+
+	negated := false
+	for i := len(m.patterns) - 1; i >= 0; i-- {
+		p := &m.patterns[i]
+		p.self = !p.negate && !negated && len(p.segs) > 1 && p.segs[len(p.segs)-1] == "**"
+		negated = negated || p.negate
+	}
+	return m, errors.Join(errs...)
+}
+
+func compile(raw string) (pattern, bool, error) {
+	s := strings.TrimRight(raw, " \t\r")
+	if s == "" || strings.HasPrefix(s, "#") {
+		return pattern{}, false, nil
+	}
+
+	var p pattern
+	if strings.HasPrefix(s, `\!`) || strings.HasPrefix(s, `\#`) {
+		s = s[1:]
+	} else if strings.HasPrefix(s, "!") {
+		p.negate = true
+		s = s[1:]
+	}
+
+	s = filepath.ToSlash(s)
+	if s == "~" || strings.HasPrefix(s, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return pattern{}, false, fmt.Errorf("ignore pattern %q: %w", raw, err)
+		}
+		s = filepath.ToSlash(home) + s[1:]
+	}
+	if vol := filepath.VolumeName(filepath.FromSlash(s)); vol != "" {
+		s = s[len(vol):]
+	}
+
+	// Directories are all we match, so a trailing slash changes nothing
+	s = strings.TrimSuffix(s, "/")
+	p.absolute = strings.HasPrefix(s, "/")
+	s = strings.TrimPrefix(s, "/")
+	p.anchored = p.absolute || strings.Contains(s, "/")
+	if s == "" {
+		return pattern{}, false, nil
+	}
+
+	for _, seg := range strings.Split(s, "/") {
+		if seg == "" {
+			continue
+		}
+		if seg != "**" {
+			seg = normalizeSegment(seg)
+			if _, err := path.Match(seg, ""); err != nil {
+				return pattern{}, false, fmt.Errorf("ignore pattern %q: %w", raw, err)
+			}
+		}
+		p.segs = append(p.segs, seg)
+	}
+	return p, true, nil
+}
+
+// normalizeSegment converts gitignore syntax to path.Match syntax: "[!"
+// negates a class, and "**" inside a segment is just "*".
+func normalizeSegment(seg string) string {
+	seg = strings.ReplaceAll(seg, "[!", "[^")
+	for strings.Contains(seg, "**") {
+		seg = strings.ReplaceAll(seg, "**", "*")
+	}
+	return seg
+}
+
+// Match reports whether path is ignored. Relative patterns are evaluated
+// against path relative to root; with an empty root, or a path outside
+// root, they are evaluated against path as given.
+func (m *Matcher) Match(root, p string) bool {
+	if m == nil || len(m.patterns) == 0 {
+		return false
+	}
+
+	abs := splitPath(p)
+	rel := abs
+	if root != "" {
+		if r, err := filepath.Rel(root, p); err == nil && r != ".." && !strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+			rel = splitPath(r)
+		}
+	}
+
+	// A parent that is ignored takes its whole subtree with it
+	offset := len(abs) - len(rel)
+	for n := 1; n <= len(rel); n++ {
+		if m.decide(abs[:offset+n], rel[:n]) {
+			return true
+		}
+	}
+	return false
+}
+
+// decide applies every pattern in order to one path; the last match wins.
+func (m *Matcher) decide(abs, rel []string) bool {
+	ignored := false
+	for _, p := range m.patterns {
+		if p.matches(abs, rel) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
+
+func (p *pattern) matches(abs, rel []string) bool {
+	switch {
+	case p.absolute:
+		return p.matchSegs(abs) || p.matchSegs(rel)
+	case p.anchored:
+		return p.matchSegs(rel)
+	default:
+		// Unanchored patterns match the trailing segments at any depth
+		for i := range rel {
+			if p.matchSegs(rel[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (p *pattern) matchSegs(segs []string) bool {
+	return matchSegs(p.segs, segs) || (p.self && matchSegs(p.segs[:len(p.segs)-1], segs))
+}
+
+func matchSegs(pat, segs []string) bool {
+	for len(pat) > 0 {
+		if pat[0] == "**" {
+			rest := pat[1:]
+			if len(rest) == 0 {
+				return len(segs) > 0
+			}
+			for i := 0; i <= len(segs); i++ {
+				if matchSegs(rest, segs[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segs) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pat[0], segs[0]); !ok {
+			return false
+		}
+		pat, segs = pat[1:], segs[1:]
+	}
+	return len(segs) == 0
+}
+
+func splitPath(p string) []string {
+	p = filepath.ToSlash(p)
+	if vol := filepath.VolumeName(filepath.FromSlash(p)); vol != "" {
+		p = p[len(vol):]
+	}
+	var segs []string
+	for _, s := range strings.Split(p, "/") {
+		if s != "" && s != "." {
+			segs = append(segs, s)
+		}
+	}
+	return segs
+}
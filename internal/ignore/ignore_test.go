@@ -0,0 +1,127 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatcher_Match(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	root := "/home/user/code"
+
+	tests := []struct {
+		name     string
+		patterns []string
+		path     string
+		want     bool
+	}{
+		// The shapes handled by the old matcher
+		{"doublestar dir itself", []string{"**/node_modules/**"}, root + "/app/node_modules", true},
+		{"doublestar below dir", []string{"**/node_modules/**"}, root + "/app/node_modules/pkg", true},
+		{"doublestar deep below dir", []string{"**/node_modules/**"}, root + "/app/node_modules/a/b/c", true},
+		{"doublestar other dir", []string{"**/node_modules/**"}, root + "/app/src", false},
+		{"doublestar prefix name", []string{"**/node_modules/**"}, root + "/app/node_modules_old", false},
+		{"simple glob", []string{"*.tmp"}, root + "/cache.tmp", true},
+		{"simple glob nested", []string{"*.tmp"}, root + "/a/b/cache.tmp", true},
+		{"simple glob miss", []string{"*.tmp"}, root + "/cache.go", false},
+
+		// Doublestar anywhere
+		{"leading doublestar ext", []string{"**/*.bak"}, root + "/a/b/old.bak", true},
+		{"leading doublestar ext at root", []string{"**/*.bak"}, root + "/old.bak", true},
+		{"doublestar segment glob", []string{"**/tmp-*/**"}, root + "/a/tmp-123/repo", true},
+		{"doublestar segment glob miss", []string{"**/tmp-*/**"}, root + "/a/tmp/repo", false},
+		{"middle doublestar", []string{"a/**/z"}, root + "/a/z", true},
+		{"middle doublestar deep", []string{"a/**/z"}, root + "/a/b/c/z", true},
+		{"middle doublestar miss", []string{"a/**/z"}, root + "/b/a/z", false},
+		{"doublestar in segment", []string{"foo**bar"}, root + "/x/fooXbar", true},
+
+		// Character classes
+		{"class", []string{"v[0-9]"}, root + "/v1", true},
+		{"class miss", []string{"v[0-9]"}, root + "/vx", false},
+		{"negated class", []string{"v[!0-9]"}, root + "/vx", true},
+		{"negated class miss", []string{"v[!0-9]"}, root + "/v1", false},
+		{"question mark", []string{"tm?"}, root + "/tmp", true},
+
+		// Anchoring
+		{"unanchored matches any depth", []string{"archive"}, root + "/a/b/archive", true},
+		{"anchored to root", []string{"archive/**"}, root + "/archive/old", true},
+		{"anchored to root only", []string{"archive/**"}, root + "/a/archive/old", false},
+		{"anchored middle slash", []string{"a/archive"}, root + "/a/archive", true},
+		{"anchored middle slash miss", []string{"a/archive"}, root + "/x/a/archive", false},
+		{"leading slash anchors", []string{"/archive"}, root + "/archive", true},
+		{"leading slash anchors miss", []string{"/archive"}, root + "/a/archive", false},
+		{"trailing slash ignored", []string{"build/"}, root + "/x/build", true},
+		{"root itself never matches", []string{"code"}, root, false},
+
+		// Absolute and home patterns
+		{"absolute path", []string{"/home/user/code/archive/**"}, root + "/archive/old", true},
+		{"absolute path miss", []string{"/home/user/code/archive/**"}, root + "/active", false},
+		{"home path", []string{"~/code/archive/**"}, filepath.Join(home, "code", "archive", "x"), true},
+
+		// Parents take their subtree with them
+		{"parent ignored", []string{"node_modules"}, root + "/a/node_modules/pkg/lib", true},
+
+		// Negation: the last match wins
+		{"negation re-includes", []string{"**/vendor/**", "!**/vendor/keep"}, root + "/a/vendor/keep", false},
+		{"negation keeps parent walkable", []string{"**/vendor/**", "!**/vendor/keep"}, root + "/a/vendor", false},
+		{"negation other still ignored", []string{"**/vendor/**", "!**/vendor/keep"}, root + "/a/vendor/other", true},
+		{"negation then exclude", []string{"*.log", "!important.log", "*.log"}, root + "/important.log", true},
+		{"negation alone", []string{"!keep"}, root + "/keep", false},
+		{"escaped bang", []string{`\!bang`}, root + "/!bang", true},
+
+		// Comments and blanks
+		{"comment", []string{"# tmp", "", "  "}, root + "/# tmp", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := New(tt.patterns)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if got := m.Match(root, tt.path); got != tt.want {
+				t.Errorf("Match(%q, %q) with %q = %v, want %v", root, tt.path, tt.patterns, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcher_NoRoot(t *testing.T) {
+	m, _ := New([]string{"**/node_modules/**", "*.tmp"})
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/home/user/project/node_modules/pkg", true},
+		{"/home/user/project/src", false},
+		{"file.tmp", true},
+		{"file.go", false},
+	}
+	for _, tt := range tests {
+		if got := m.Match("", tt.path); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestNew_InvalidPattern(t *testing.T) {
+	m, err := New([]string{"[unclosed", "*.tmp"})
+	if err == nil {
+		t.Error("New() should report the invalid pattern")
+	}
+	if !m.Match("", "a.tmp") {
+		t.Error("valid patterns should still be compiled")
+	}
+}
+
+func TestMatcher_Nil(t *testing.T) {
+	var m *Matcher
+	if m.Match("/", "/a") {
+		t.Error("nil matcher should match nothing")
+	}
+}
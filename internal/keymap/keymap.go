@@ -0,0 +1,232 @@
+// Package keymap holds the actions of the TUI with their default keys and
+// applies the keys section of the config to them.
+//
+// A key is either a single key as bubbletea names it ("f", "ctrl+d",
+// "home") or a sequence of characters pressed one after another ("gg",
+// "gf"). A key that starts a sequence can't be bound on its own.
+package keymap
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Actions, as named in the config
+const (
+	Up         = "up"
+	Down       = "down"
+	Top        = "top"
+	Bottom     = "bottom"
+	HalfDown   = "half_down"
+	HalfUp     = "half_up"
+	Filter     = "filter"
+	Jump       = "jump"
+	Palette    = "palette"
+	Reload     = "reload"
+	ScanReport = "scan_report"
+	Fetch      = "fetch"
+	FetchAll   = "fetch_all"
+	Editor     = "editor"
+	Open       = "open"
+	CopyPath   = "copy_path"
+	Shell      = "shell"
+	Pin        = "pin"
+	EditTags   = "edit_tags"
+
+	Browse        = "browse"
+	BrowseBranch  = "browse_branch"
+	BrowseCommit  = "browse_commit"
+	BrowseCompare = "browse_compare"
+
+	ViewAll       = "view_all"
+	ViewDirty     = "view_dirty"
+	ViewAhead     = "view_ahead"
+	ViewConflicts = "view_conflicts"
+	ViewPinned    = "view_pinned"
+	TagPicker     = "tag_picker"
+	NextView      = "next_view"
+	SortDiff      = "sort_diff"
+	SortChurn     = "sort_churn"
+	Detail        = "detail"
+	Columns       = "columns"
+
+	FocusDetail    = "focus_detail"
+	NextTab        = "next_tab"
+	PrevTab        = "prev_tab"
+	DetailWider    = "detail_wider"
+	DetailNarrower = "detail_narrower"
+
+	Help = "help"
+	Quit = "quit"
+)
+
+// Reserved keys work in every prompt and can't be bound.
+var Reserved = []string{"esc", "enter"}
+
+// Binding is an action with its keys.
+type Binding struct {
+	Action string
+	Group  string // heading in the help overlay
+	Desc   string
+	Title  string   // name in the command palette
+	Keys   []string // in config form, e.g. "gg"
+}
+
+var defaults = []Binding{
+	{Up, "Navigation", "up", "Move up", []string{"up", "k"}},
+	{Down, "Navigation", "down", "Move down", []string{"down", "j"}},
+	{Top, "Navigation", "top", "Jump to top", []string{"gg", "home"}},
+	{Bottom, "Navigation", "bottom", "Jump to bottom", []string{"G", "end"}},
+	{HalfDown, "Navigation", "½ page down", "Half page down", []string{"ctrl+d"}},
+	{HalfUp, "Navigation", "½ page up", "Half page up", []string{"ctrl+u"}},
+	{Filter, "Navigation", "filter", "Filter repos", []string{"/"}},
+	{Jump, "Navigation", "jump to repo", "Jump to repo", []string{"'"}},
+	{Palette, "Navigation", "command palette", "Command palette", []string{"ctrl+p"}},
+
+	{Reload, "Actions", "reload", "Reload repos", []string{"r"}},
+	{ScanReport, "Actions", "scan report", "Show scan report", []string{"S"}},
+	{Fetch, "Actions", "fetch", "Fetch selected repo", []string{"f"}},
+	{FetchAll, "Actions", "fetch all", "Fetch all repos", []string{"F"}},
+	{Editor, "Actions", "editor", "Open in editor", []string{"e"}},
+	{Open, "Actions", "open finder", "Open in file manager", []string{"o"}},
+	{Browse, "Actions", "browse repo", "Open repo in browser", []string{"b"}},
+	{BrowseBranch, "Actions", "browse branch", "Open branch in browser", []string{"B"}},
+	{BrowseCommit, "Actions", "browse commit", "Open HEAD commit in browser", []string{"gc"}},
+	{BrowseCompare, "Actions", "compare / new PR", "Compare branch or open a pull request", []string{"gp"}},
+	{CopyPath, "Actions", "copy path", "Copy repo path", []string{"y"}},
+	{Pin, "Actions", "pin/unpin", "Pin or unpin repo", []string{"p"}},
+	{EditTags, "Actions", "edit tags", "Edit repo tags", []string{"t"}},
+	{Shell, "Actions", "shell", "Open shell in repo", []string{":"}},
+
+	{ViewAll, "Views & Sort", "all", "Show all repos", []string{"1"}},
+	{ViewDirty, "Views & Sort", "dirty", "Show dirty repos", []string{"2"}},
+	{ViewAhead, "Views & Sort", "ahead", "Show repos ahead of remote", []string{"3"}},
+	{ViewConflicts, "Views & Sort", "conflict", "Show repos with conflicts", []string{"4"}},
+	{ViewPinned, "Views & Sort", "pinned", "Show pinned repos", []string{"P"}},
+	{TagPicker, "Views & Sort", "filter by tag", "Filter by tag", []string{"T"}},
+	{NextView, "Views & Sort", "next saved view", "Next saved view", []string{"v"}},
+	{SortDiff, "Views & Sort", "sort:diff", "Sort by diff volume", []string{"5"}},
+	{SortChurn, "Views & Sort", "sort:churn", "Sort by file churn", []string{"6"}},
+	{Detail, "Views & Sort", "detail", "Toggle detail panel", []string{"d"}},
+	{Columns, "Views & Sort", "cycle columns", "Cycle column presets", []string{"c"}},
+
+	{FocusDetail, "Detail panel", "focus/scroll", "Focus detail panel", []string{"tab"}},
+	{NextTab, "Detail panel", "next tab", "Next detail tab", []string{"]"}},
+	{PrevTab, "Detail panel", "previous tab", "Previous detail tab", []string{"["}},
+	{DetailWider, "Detail panel", "wider", "Widen detail panel", []string{"<"}},
+	{DetailNarrower, "Detail panel", "narrower", "Narrow detail panel", []string{">"}},
+
+	{Help, "", "help", "Help", []string{"?"}},
+	{Quit, "", "quit", "Quit", []string{"q", "ctrl+c"}},
+}
+
+// Defaults returns every action with its default keys, in help order.
+func Defaults() []Binding {
+	out := make([]Binding, len(defaults))
+	for i, b := range defaults {
+		b.Keys = slices.Clone(b.Keys)
+		out[i] = b
+	}
+	return out
+}
+
+// Actions lists the action names.
+func Actions() []string {
+	names := make([]string, len(defaults))
+	for i, b := range defaults {
+		names[i] = b.Action
+	}
+	return names
+}
+
+// Keys is the list of keys bound to an action. In YAML it may also be a
+// single key.
+type Keys []string
+
+func (k *Keys) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*k = Keys{value.Value}
+		return nil
+	}
+	var list []string
+	if err := value.Decode(&list); err != nil {
+		return err
+	}
+	*k = list
+	return nil
+}
+
+// Build applies overrides to the default bindings. An action in overrides
+// gets exactly the keys listed there; an empty list unbinds it. It fails
+// on unknown actions and on keys bound twice or shadowed by a sequence.
+func Build(overrides map[string]Keys) ([]Binding, error) {
+	bindings := Defaults()
+	actions := Actions()
+	for action, keys := range overrides {
+		i := slices.Index(actions, action)
+		if i < 0 {
+			return nil, fmt.Errorf("unknown action %q", action)
+		}
+		bindings[i].Keys = slices.Clone(keys)
+	}
+
+	type owner struct{ action, key string }
+	var seqs []owner
+	seen := make(map[string]owner)
+	for _, b := range bindings {
+		for _, k := range b.Keys {
+			seq := Split(k)
+			if len(seq) == 0 {
+				return nil, fmt.Errorf("%s: empty key", b.Action)
+			}
+			if slices.Contains(Reserved, seq[0]) {
+				return nil, fmt.Errorf("%s: %s is reserved", b.Action, k)
+			}
+			id := strings.Join(seq, " ")
+			if o, dup := seen[id]; dup {
+				return nil, fmt.Errorf("%s is bound to both %s and %s", k, o.action, b.Action)
+			}
+			seen[id] = owner{b.Action, k}
+			seqs = append(seqs, owner{b.Action, k})
+		}
+	}
+
+	// A key that starts a sequence would fire before the sequence completes
+	for _, a := range seqs {
+		for _, b := range seqs {
+			sa, sb := Split(a.key), Split(b.key)
+			if len(sa) < len(sb) && slices.Equal(sa, sb[:len(sa)]) {
+				return nil, fmt.Errorf("%s (%s) starts %s (%s); bind one of them to another key",
+					a.key, a.action, b.key, b.action)
+			}
+		}
+	}
+	return bindings, nil
+}
+
+// named are multi-character key names that are single keys, not sequences.
+var named = []string{
+	"up", "down", "left", "right", "home", "end", "pgup", "pgdown",
+	"enter", "esc", "tab", "space", "backspace", "delete", "insert",
+	"f1", "f2", "f3", "f4", "f5", "f6", "f7", "f8", "f9", "f10", "f11", "f12",
+}
+
+// Split turns a key from the config into the keys pressed in turn:
+// "ctrl+d" and "home" are one key, "gg" is g then g.
+func Split(k string) []string {
+	if utf8.RuneCountInString(k) <= 1 || strings.Contains(k, "+") || slices.Contains(named, k) {
+		if k == "" {
+			return nil
+		}
+		return []string{k}
+	}
+	var seq []string
+	for _, r := range k {
+		seq = append(seq, string(r))
+	}
+	return seq
+}
@@ -0,0 +1,87 @@
+package keymap
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestDefaultsAreValid(t *testing.T) {
+	if _, err := Build(nil); err != nil {
+		t.Fatalf("Build(nil) error = %v", err)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	bindings, err := Build(map[string]Keys{
+		Fetch:    {"ctrl+f"},
+		FetchAll: {"gf"},
+		Columns:  {},
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	keys := make(map[string][]string)
+	for _, b := range bindings {
+		keys[b.Action] = b.Keys
+	}
+	if !slices.Equal(keys[Fetch], []string{"ctrl+f"}) || !slices.Equal(keys[FetchAll], []string{"gf"}) {
+		t.Errorf("fetch = %v, fetch_all = %v", keys[Fetch], keys[FetchAll])
+	}
+	if len(keys[Columns]) != 0 {
+		t.Errorf("columns = %v, want unbound", keys[Columns])
+	}
+	if !slices.Equal(keys[Quit], []string{"q", "ctrl+c"}) {
+		t.Errorf("quit = %v, want the defaults", keys[Quit])
+	}
+}
+
+func TestBuild_Errors(t *testing.T) {
+	tests := []struct {
+		overrides map[string]Keys
+		want      string
+	}{
+		{map[string]Keys{"fetchh": {"x"}}, "unknown action"},
+		{map[string]Keys{Fetch: {"p"}}, "bound to both"},
+		{map[string]Keys{Fetch: {"g"}}, "starts"},
+		{map[string]Keys{Pin: {"ddd"}}, "starts"},
+		{map[string]Keys{Fetch: {"esc"}}, "reserved"},
+		{map[string]Keys{Fetch: {""}}, "empty key"},
+	}
+	for _, tt := range tests {
+		_, err := Build(tt.overrides)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Build(%v) error = %v, want %q", tt.overrides, err, tt.want)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := map[string][]string{
+		"f":      {"f"},
+		"gg":     {"g", "g"},
+		"gf":     {"g", "f"},
+		"ctrl+d": {"ctrl+d"},
+		"home":   {"home"},
+		"f5":     {"f5"},
+		"↑":      {"↑"},
+		"":       nil,
+	}
+	for in, want := range tests {
+		if got := Split(in); !slices.Equal(got, want) {
+			t.Errorf("Split(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestKeys_UnmarshalYAML(t *testing.T) {
+	var m map[string]Keys
+	if err := yaml.Unmarshal([]byte("fetch: x\nquit: [q, ctrl+q]\n"), &m); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(m["fetch"], Keys{"x"}) || !slices.Equal(m["quit"], Keys{"q", "ctrl+q"}) {
+		t.Errorf("got %v", m)
+	}
+}
@@ -0,0 +1,171 @@
+// Package metrics exposes repo health in the OpenMetrics text format so
+// Prometheus (or anything that speaks the format) can scrape gv.
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackchuka/gv/internal/model"
+)
+
+// ContentType is the media type of the exposition written by Write.
+const ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// Source supplies the state to export. daemon.Service implements it.
+type Source interface {
+	Repos() []model.Repository
+	ScannedAt() time.Time
+	ScanDuration() time.Duration
+	PollDuration() time.Duration
+}
+
+type sample struct {
+	labels string
+	value  float64
+}
+
+type family struct {
+	name    string
+	help    string
+	unit    string
+	samples []sample
+}
+
+// Write renders the current state of src. now is used for ages.
+func Write(w io.Writer, src Source, now time.Time) error {
+	repos := src.Repos()
+	sum := model.Summarize(repos)
+
+	families := []*family{
+		gauge("gv_repos", "Repositories discovered", sum.TotalRepos),
+		gauge("gv_repos_dirty", "Repositories with uncommitted changes", sum.DirtyRepos),
+		gauge("gv_repos_ahead", "Repositories with unpushed commits", sum.AheadRepos),
+		gauge("gv_repos_behind", "Repositories behind their upstream", sum.BehindRepos),
+		gauge("gv_repos_in_sync", "Clean repositories level with their upstream", sum.InSyncRepos),
+		gauge("gv_repos_conflicted", "Repositories with conflicts or in a merge, rebase or other special state", sum.ConflictRepos),
+		{name: "gv_scan_duration_seconds", help: "Duration of the last full scan", unit: "seconds",
+			samples: []sample{{value: src.ScanDuration().Seconds()}}},
+		{name: "gv_poll_duration_seconds", help: "Duration of the last poll cycle", unit: "seconds",
+			samples: []sample{{value: src.PollDuration().Seconds()}}},
+	}
+	if at := src.ScannedAt(); !at.IsZero() {
+		families = append(families, &family{
+			name: "gv_last_scan_timestamp_seconds", help: "Unix time of the last full scan", unit: "seconds",
+			samples: []sample{{value: float64(at.UnixNano()) / 1e9}},
+		})
+	}
+
+	dirty := &family{name: "gv_repo_dirty_files", help: "Staged, modified and untracked files"}
+	staged := &family{name: "gv_repo_staged_files", help: "Files staged for commit"}
+	ahead := &family{name: "gv_repo_ahead_commits", help: "Commits not yet pushed to the upstream"}
+	behind := &family{name: "gv_repo_behind_commits", help: "Upstream commits not yet pulled"}
+	stashes := &family{name: "gv_repo_stashes", help: "Stash entries"}
+	conflict := &family{name: "gv_repo_conflict", help: "1 while the repo has conflicts or is mid merge, rebase, cherry-pick, revert or bisect"}
+	age := &family{name: "gv_repo_last_commit_age_seconds", help: "Time since the last commit on HEAD", unit: "seconds"}
+
+	for _, r := range repos {
+		if r.Status == nil {
+			continue
+		}
+		st := r.Status
+		l := repoLabels(&r)
+		dirty.add(l, st.Staged+st.Modified+st.Untracked)
+		staged.add(l, st.Staged)
+		ahead.add(l, st.Ahead)
+		behind.add(l, st.Behind)
+		stashes.add(l, st.Stashes)
+		conflict.add(l, boolValue(st.HasConflict()))
+		if !st.LastCommit.IsZero() {
+			age.samples = append(age.samples, sample{labels: l, value: now.Sub(st.LastCommit).Seconds()})
+		}
+	}
+	families = append(families, dirty, staged, ahead, behind, stashes, conflict, age)
+
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		f.write(bw)
+	}
+	bw.WriteString("# EOF\n")
+	return bw.Flush()
+}
+
+// Handler serves the exposition of src on every request.
+func Handler(src Source) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		_ = Write(w, src, time.Now())
+	})
+}
+
+// ListenAndServe serves /metrics on addr until ctx is cancelled.
+func ListenAndServe(ctx context.Context, addr string, src Source) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", Handler(src))
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func gauge(name, help string, v int) *family {
+	return &family{name: name, help: help, samples: []sample{{value: float64(v)}}}
+}
+
+func (f *family) add(labels string, v int) {
+	f.samples = append(f.samples, sample{labels: labels, value: float64(v)})
+}
+
+func (f *family) write(w *bufio.Writer) {
+	fmt.Fprintf(w, "# TYPE %s gauge\n", f.name)
+	if f.unit != "" {
+		fmt.Fprintf(w, "# UNIT %s %s\n", f.name, f.unit)
+	}
+	fmt.Fprintf(w, "# HELP %s %s.\n", f.name, f.help)
+	for _, s := range f.samples {
+		w.WriteString(f.name)
+		if s.labels != "" {
+			w.WriteString("{" + s.labels + "}")
+		}
+		w.WriteString(" " + strconv.FormatFloat(s.value, 'f', -1, 64) + "\n")
+	}
+}
+
+func repoLabels(r *model.Repository) string {
+	return `repo="` + escape(r.DisplayName()) + `",path="` + escape(r.Path) + `"`
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+func boolValue(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
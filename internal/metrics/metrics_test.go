@@ -0,0 +1,156 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackchuka/gv/internal/model"
+)
+
+type fakeSource struct {
+	repos []model.Repository
+	at    time.Time
+}
+
+func (f *fakeSource) Repos() []model.Repository   { return f.repos }
+func (f *fakeSource) ScannedAt() time.Time        { return f.at }
+func (f *fakeSource) ScanDuration() time.Duration { return 1500 * time.Millisecond }
+func (f *fakeSource) PollDuration() time.Duration { return 250 * time.Millisecond }
+
+func newFakeSource(now time.Time) *fakeSource {
+	return &fakeSource{
+		at: now,
+		repos: []model.Repository{
+			{Path: "/code/clean", Status: &model.RepoStatus{Remote: "origin/main", LastCommit: now.Add(-time.Hour)}},
+			{Path: "/code/busy", Status: &model.RepoStatus{Staged: 1, Modified: 2, Untracked: 3, Ahead: 4, Behind: 5, Stashes: 2, Conflicts: 1}},
+			{Path: `/code/we"ird`, Status: &model.RepoStatus{}},
+			{Path: "/code/loading"},
+		},
+	}
+}
+
+func TestWrite(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var b strings.Builder
+	if err := Write(&b, newFakeSource(now), now); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+
+	want := []string{
+		"gv_repos 4\n",
+		"gv_repos_dirty 1\n",
+		"gv_repos_in_sync 1\n",
+		"gv_repos_conflicted 1\n",
+		"gv_scan_duration_seconds 1.5\n",
+		"gv_poll_duration_seconds 0.25\n",
+		"# UNIT gv_scan_duration_seconds seconds\n",
+		`gv_repo_dirty_files{repo="busy",path="/code/busy"} 6` + "\n",
+		`gv_repo_staged_files{repo="busy",path="/code/busy"} 1` + "\n",
+		`gv_repo_ahead_commits{repo="busy",path="/code/busy"} 4` + "\n",
+		`gv_repo_behind_commits{repo="busy",path="/code/busy"} 5` + "\n",
+		`gv_repo_stashes{repo="busy",path="/code/busy"} 2` + "\n",
+		`gv_repo_conflict{repo="busy",path="/code/busy"} 1` + "\n",
+		`gv_repo_conflict{repo="clean",path="/code/clean"} 0` + "\n",
+		`gv_repo_last_commit_age_seconds{repo="clean",path="/code/clean"} 3600` + "\n",
+		`path="/code/we\"ird"`,
+	}
+	for _, w := range want {
+		if !strings.Contains(out, w) {
+			t.Errorf("output missing %q", w)
+		}
+	}
+
+	if strings.Contains(out, "/code/loading") {
+		t.Error("repos without a status should not be exported")
+	}
+	if strings.Count(out, "gv_repo_last_commit_age_seconds{") != 1 {
+		t.Error("last commit age should only be exported when known")
+	}
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Error("exposition must end with # EOF")
+	}
+}
+
+func TestWrite_ConflictedIsSumOfRepoConflict(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	src := &fakeSource{at: now, repos: []model.Repository{
+		{Path: "/code/unmerged", Status: &model.RepoStatus{Conflicts: 2}},
+		{Path: "/code/rebasing", Status: &model.RepoStatus{RebaseHead: true}},
+		{Path: "/code/clean", Status: &model.RepoStatus{}},
+	}}
+	var b strings.Builder
+	if err := Write(&b, src, now); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+
+	if !strings.Contains(out, "gv_repos_conflicted 2\n") {
+		t.Errorf("gv_repos_conflicted should count both repos:\n%s", out)
+	}
+	for _, w := range []string{
+		`gv_repo_conflict{repo="unmerged",path="/code/unmerged"} 1` + "\n",
+		`gv_repo_conflict{repo="rebasing",path="/code/rebasing"} 1` + "\n",
+		`gv_repo_conflict{repo="clean",path="/code/clean"} 0` + "\n",
+	} {
+		if !strings.Contains(out, w) {
+			t.Errorf("output missing %q", w)
+		}
+	}
+}
+
+func TestListenAndServe_Scrape(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("cannot listen on loopback")
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- ListenAndServe(ctx, addr, newFakeSource(time.Now())) }()
+
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		resp, err = http.Get("http://" + addr + "/metrics")
+		if err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("scrape: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != ContentType {
+		t.Errorf("Content-Type = %q, want %q", ct, ContentType)
+	}
+	if !strings.Contains(string(body), "gv_repos 4\n") {
+		t.Errorf("scrape body missing gv_repos:\n%s", body)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("ListenAndServe() error = %v", err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Handler(newFakeSource(time.Now())).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "# TYPE gv_repo_dirty_files gauge") {
+		t.Error("missing type metadata")
+	}
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// Activity summarizes the history of a repo over a period, for reports.
+type Activity struct {
+	Since     time.Time
+	Commits   int
+	Added     int // Lines added by those commits
+	Deleted   int // Lines deleted by those commits
+	FileChurn map[string]int
+
+	Branches []BranchInfo // Local branches
+	Stashes  []StashEntry
+}
+
+func (a *Activity) TopChurnFiles(n int) []FileChurnEntry {
+	return topChurn(a.FileChurn, n)
+}
+
+// Unpushed returns local branches holding commits their upstream lacks,
+// including branches that have no upstream at all.
+func (a *Activity) Unpushed() []BranchInfo {
+	var out []BranchInfo
+	for _, b := range a.Branches {
+		if b.Upstream == "" || b.UpstreamGone || b.Ahead > 0 {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+type BranchInfo struct {
+	Name         string
+	Upstream     string // Short upstream ref, empty if none is configured
+	Ahead        int
+	Behind       int
+	UpstreamGone bool // Upstream configured but deleted on the remote
+	LastCommit   time.Time
+}
+
+type StashEntry struct {
+	Ref     string // e.g. stash@{0}
+	Message string
+	Time    time.Time
+}
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// RepoDetail is what the TUI's detail panel shows beyond the status and
+// diff stats. It is read for the selected repo only.
+type RepoDetail struct {
+	Branches  []BranchInfo // Local branches
+	Stashes   []StashEntry
+	Remotes   []Remote
+	Commits   []Commit // Most recent first
+	Untracked []string // Untracked files; wholly untracked directories end in /
+
+	CollectedAt time.Time
+}
+
+type Remote struct {
+	Name     string
+	FetchURL string
+	PushURL  string // Same as FetchURL unless a push URL is configured
+
+	// Parsed from FetchURL; empty for local paths
+	Host  string
+	Owner string // May span several segments, e.g. a GitLab group path
+	Repo  string
+
+	DefaultBranch string // e.g. "main"; empty when unknown
+
+	// HEAD compared with the remote's branch of the same name, else its
+	// default branch; Branch is empty when it has neither
+	Branch string // e.g. "upstream/main"
+	Ahead  int
+	Behind int
+}
+
+type Commit struct {
+	Hash    string // Abbreviated
+	Author  string
+	Subject string
+	Time    time.Time
+}
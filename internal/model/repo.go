@@ -4,17 +4,59 @@ package model
 import (
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 )
 
+// RepoKind distinguishes how a repository is laid out on disk.
+type RepoKind string
+
+const (
+	KindNormal    RepoKind = "normal"    // Working tree with its own git dir
+	KindWorktree  RepoKind = "worktree"  // Linked worktree of another repo
+	KindBare      RepoKind = "bare"      // No working tree; may host worktrees
+	KindSubmodule RepoKind = "submodule" // Submodule checked out inside a superproject
+)
+
 type Repository struct {
 	Path         string      // Absolute path to repo root
 	Name         string      // Display name (derived from path or config)
+	Kind         RepoKind    // Layout on disk (empty means KindNormal)
+	GitDir       string      // Resolved git directory (may live outside Path)
 	IsWorktree   bool        // True if this is a linked worktree
-	MainWorktree string      // If IsWorktree, path to main repo
+	MainWorktree string      // If IsWorktree, path to main repo (normal or bare)
+	Superproject string      // If a submodule, path to the enclosing repo
 	Status       *RepoStatus // Current status (nil if not yet scanned)
 	Diff         *DiffStats  // Line-level diff and activity data (nil if not loaded)
 	LastScanned  time.Time   // When status was last refreshed
+
+	// Settings from the repos section of the config
+	Group         string        // Group name (empty if ungrouped)
+	Tags          []string      // Free-form labels
+	Pinned        bool          // Listed before other repos
+	PollInterval  time.Duration // Poll interval override (0 uses the global one)
+	FetchDisabled bool          // Never fetched by gv
+	DefaultRemote string        // Remote to fetch and derive Owner from (empty means origin)
+}
+
+func (r *Repository) IsBare() bool {
+	return r.Kind == KindBare
+}
+
+func (r *Repository) IsSubmodule() bool {
+	return r.Kind == KindSubmodule
+}
+
+// ParentPath returns the repo this one is listed under: the main repo of a
+// worktree or the superproject of a submodule. Empty for top-level repos.
+func (r *Repository) ParentPath() string {
+	switch {
+	case r.IsWorktree:
+		return r.MainWorktree
+	case r.IsSubmodule():
+		return r.Superproject
+	}
+	return ""
 }
 
 func (r *Repository) DisplayName() string {
@@ -33,12 +75,19 @@ type RepoStatus struct {
 	Staged    int // Number of staged files
 	Modified  int // Number of modified files
 	Untracked int // Number of untracked files
+	Conflicts int // Number of unmerged (conflicted) files
 
 	// Remote state
-	Remote string // Tracking remote (e.g., "origin/main")
-	Owner  string // Owner/org from remote URL (e.g., "jackchuka")
-	Ahead  int    // Commits ahead of remote
-	Behind int    // Commits behind remote
+	Remote       string // Tracking remote (e.g., "origin/main")
+	Owner        string // Owner/org from remote URL (e.g., "jackchuka")
+	Ahead        int    // Commits ahead of remote
+	Behind       int    // Commits behind remote
+	UpstreamGone bool   // Tracking branch is configured but no longer exists
+
+	// Fork state, against the "upstream" remote's branch of the same name
+	// or its default branch
+	ForkBranch string // e.g. "upstream/main"; empty when there is no upstream remote
+	ForkBehind int    // Commits on ForkBranch that HEAD lacks
 
 	// Special states
 	Stashes    int  // Number of stashes
@@ -52,10 +101,39 @@ type RepoStatus struct {
 	LastCommit   time.Time // Time of last commit
 	LastModified time.Time // Last working tree modification
 
+	// Submodules that differ from what the superproject records, keyed by
+	// superproject-relative path. Submodules at the recorded commit with a
+	// clean tree are absent.
+	Submodules map[string]SubmoduleState
+
 	// Custom command outputs
 	Aliases map[string]string // alias name -> output
 }
 
+// SubmoduleState is a submodule's state as seen from its superproject
+// (the S field of git status --porcelain=v2).
+type SubmoduleState struct {
+	NewCommits bool // Checked out commit differs from the recorded one
+	Modified   bool // Tracked changes inside the submodule
+	Untracked  bool // Untracked files inside the submodule
+}
+
+// Label describes the state briefly; empty when the submodule is at the
+// recorded commit and clean.
+func (s SubmoduleState) Label() string {
+	var parts []string
+	if s.NewCommits {
+		parts = append(parts, "new commits")
+	}
+	if s.Modified {
+		parts = append(parts, "modified")
+	}
+	if s.Untracked {
+		parts = append(parts, "untracked")
+	}
+	return strings.Join(parts, ", ")
+}
+
 func (s *RepoStatus) IsDirty() bool {
 	return s.Staged > 0 || s.Modified > 0 || s.Untracked > 0
 }
@@ -64,6 +142,35 @@ func (s *RepoStatus) HasSpecialState() bool {
 	return s.MergeHead || s.RebaseHead || s.CherryPick || s.Reverting || s.Bisecting
 }
 
+// SpecialState returns a label for the operation in progress, or "".
+func (s *RepoStatus) SpecialState() string {
+	switch {
+	case s.MergeHead:
+		return "MERGE"
+	case s.RebaseHead:
+		return "REBASE"
+	case s.CherryPick:
+		return "CHERRY-PICK"
+	case s.Reverting:
+		return "REVERT"
+	case s.Bisecting:
+		return "BISECT"
+	}
+	return ""
+}
+
+// HasConflict reports whether the repo has conflicted files or an operation
+// in progress that may leave conflicts behind.
+func (s *RepoStatus) HasConflict() bool {
+	return s.Conflicts > 0 || s.HasSpecialState()
+}
+
+// HasDiverged reports whether the branch is both ahead of and behind its
+// upstream, meaning a plain push would be rejected as non-fast-forward.
+func (s *RepoStatus) HasDiverged() bool {
+	return s.Ahead > 0 && s.Behind > 0
+}
+
 type FileDiffStat struct {
 	Path    string // Relative file path
 	Added   int    // Lines added
@@ -107,8 +214,14 @@ func (d *DiffStats) TotalDiffVolume() int {
 }
 
 func (d *DiffStats) TopChurnFiles(n int) []FileChurnEntry {
-	entries := make([]FileChurnEntry, 0, len(d.FileChurn))
-	for path, count := range d.FileChurn {
+	return topChurn(d.FileChurn, n)
+}
+
+// topChurn returns the n most touched files, most touched first.
+// n <= 0 returns all of them.
+func topChurn(churn map[string]int, n int) []FileChurnEntry {
+	entries := make([]FileChurnEntry, 0, len(churn))
+	for path, count := range churn {
 		entries = append(entries, FileChurnEntry{Path: path, Count: count})
 	}
 	sort.Slice(entries, func(i, j int) bool {
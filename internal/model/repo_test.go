@@ -33,6 +33,43 @@ func TestRepository_DisplayName(t *testing.T) {
 	}
 }
 
+func TestRepository_ParentPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		repo     Repository
+		expected string
+	}{
+		{"normal repo", Repository{Path: "/code/app"}, ""},
+		{"worktree", Repository{Path: "/code/app-wt", IsWorktree: true, MainWorktree: "/code/app"}, "/code/app"},
+		{"submodule", Repository{Path: "/code/app/lib", Kind: KindSubmodule, Superproject: "/code/app"}, "/code/app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.repo.ParentPath(); got != tt.expected {
+				t.Errorf("ParentPath() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSubmoduleState_Label(t *testing.T) {
+	tests := []struct {
+		state    SubmoduleState
+		expected string
+	}{
+		{SubmoduleState{}, ""},
+		{SubmoduleState{NewCommits: true}, "new commits"},
+		{SubmoduleState{NewCommits: true, Modified: true, Untracked: true}, "new commits, modified, untracked"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.state.Label(); got != tt.expected {
+			t.Errorf("Label() of %+v = %q, want %q", tt.state, got, tt.expected)
+		}
+	}
+}
+
 func TestRepoStatus_IsDirty(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -119,6 +156,28 @@ func TestRepoStatus_HasSpecialState(t *testing.T) {
 	}
 }
 
+func TestRepoStatus_SpecialState(t *testing.T) {
+	tests := []struct {
+		status   RepoStatus
+		expected string
+	}{
+		{RepoStatus{}, ""},
+		{RepoStatus{MergeHead: true}, "MERGE"},
+		{RepoStatus{RebaseHead: true}, "REBASE"},
+		{RepoStatus{CherryPick: true}, "CHERRY-PICK"},
+		{RepoStatus{Reverting: true}, "REVERT"},
+		{RepoStatus{Bisecting: true}, "BISECT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if got := tt.status.SpecialState(); got != tt.expected {
+				t.Errorf("SpecialState() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestDiffStats_TotalDiffVolume(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,69 @@
+package model
+
+// Summary aggregates status and diff counters across repos.
+type Summary struct {
+	TotalRepos    int
+	DirtyRepos    int
+	AheadRepos    int
+	BehindRepos   int
+	InSyncRepos   int
+	ConflictRepos int // with conflicts or mid merge, rebase and the like
+
+	TotalStaged    int
+	TotalModified  int
+	TotalUntracked int
+	TotalAhead     int
+	TotalBehind    int
+	TotalStashes   int
+
+	TotalAdded   int
+	TotalDeleted int
+	TotalNet     int
+
+	DailyCommits [7]int
+}
+
+func Summarize(repos []Repository) Summary {
+	s := Summary{}
+
+	for _, r := range repos {
+		s.TotalRepos++
+		if r.Status == nil {
+			continue
+		}
+		if r.Status.IsDirty() {
+			s.DirtyRepos++
+		}
+		if r.Status.Ahead > 0 {
+			s.AheadRepos++
+		}
+		if r.Status.Behind > 0 {
+			s.BehindRepos++
+		}
+		if r.Status.Remote != "" && !r.Status.IsDirty() && r.Status.Ahead == 0 && r.Status.Behind == 0 {
+			s.InSyncRepos++
+		}
+		if r.Status.HasConflict() {
+			s.ConflictRepos++
+		}
+
+		s.TotalStaged += r.Status.Staged
+		s.TotalModified += r.Status.Modified
+		s.TotalUntracked += r.Status.Untracked
+		s.TotalAhead += r.Status.Ahead
+		s.TotalBehind += r.Status.Behind
+		s.TotalStashes += r.Status.Stashes
+
+		if r.Diff != nil {
+			s.TotalAdded += r.Diff.TotalAdded
+			s.TotalDeleted += r.Diff.TotalDeleted
+
+			for i := 0; i < 7; i++ {
+				s.DailyCommits[i] += r.Diff.DailyCommits[i]
+			}
+		}
+	}
+
+	s.TotalNet = s.TotalAdded - s.TotalDeleted
+	return s
+}
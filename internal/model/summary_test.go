@@ -0,0 +1,51 @@
+package model
+
+import "testing"
+
+func TestSummarize(t *testing.T) {
+	repos := []Repository{
+		{Path: "/a", Status: &RepoStatus{Remote: "origin/main"}}, // in sync
+		{Path: "/b", Status: &RepoStatus{Remote: "origin/main", Modified: 2, Untracked: 1, Ahead: 3, Stashes: 1}},
+		{Path: "/c", Status: &RepoStatus{Staged: 1, Behind: 2, MergeHead: true},
+			Diff: &DiffStats{TotalAdded: 10, TotalDeleted: 4, DailyCommits: [7]int{0, 0, 0, 0, 0, 1, 2}}},
+		{Path: "/d"}, // not loaded yet
+	}
+
+	s := Summarize(repos)
+
+	checks := []struct {
+		name      string
+		got, want int
+	}{
+		{"TotalRepos", s.TotalRepos, 4},
+		{"DirtyRepos", s.DirtyRepos, 2},
+		{"AheadRepos", s.AheadRepos, 1},
+		{"BehindRepos", s.BehindRepos, 1},
+		{"InSyncRepos", s.InSyncRepos, 1},
+		{"ConflictRepos", s.ConflictRepos, 1},
+		{"TotalStaged", s.TotalStaged, 1},
+		{"TotalModified", s.TotalModified, 2},
+		{"TotalUntracked", s.TotalUntracked, 1},
+		{"TotalAhead", s.TotalAhead, 3},
+		{"TotalBehind", s.TotalBehind, 2},
+		{"TotalStashes", s.TotalStashes, 1},
+		{"TotalAdded", s.TotalAdded, 10},
+		{"TotalDeleted", s.TotalDeleted, 4},
+		{"TotalNet", s.TotalNet, 6},
+		{"DailyCommits[6]", s.DailyCommits[6], 2},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %d, want %d", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestSummarize_ConflictsWithoutSpecialState(t *testing.T) {
+	// Unmerged files left by a conflicted stash pop: no MERGE_HEAD
+	repos := []Repository{{Path: "/a", Status: &RepoStatus{Conflicts: 2}}}
+
+	if got := Summarize(repos).ConflictRepos; got != 1 {
+		t.Errorf("ConflictRepos = %d, want 1", got)
+	}
+}
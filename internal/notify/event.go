@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jackchuka/gv/internal/model"
+)
+
+// Kind identifies a repo state transition.
+type Kind string
+
+const (
+	KindBehind       Kind = "behind"        // branch fell behind its upstream
+	KindConflict     Kind = "conflict"      // conflicted files or a merge/rebase appeared
+	KindUpstreamGone Kind = "upstream_gone" // tracking branch was deleted on the remote
+	KindPushRejected Kind = "push_rejected" // branch diverged, a plain push would be rejected
+)
+
+// Kinds lists every transition kind in a stable order.
+var Kinds = []Kind{KindBehind, KindConflict, KindUpstreamGone, KindPushRejected}
+
+type Event struct {
+	Kind     Kind      `json:"event"`
+	RepoPath string    `json:"repo"`
+	RepoName string    `json:"name"`
+	Branch   string    `json:"branch,omitempty"`
+	Message  string    `json:"message"`
+	Time     time.Time `json:"time"`
+}
+
+// Detect compares two consecutive statuses of a repo and returns the
+// transitions between them. A nil prev means the repo was just loaded,
+// which is never reported as a transition.
+func Detect(repo *model.Repository, prev, curr *model.RepoStatus) []Event {
+	if prev == nil || curr == nil {
+		return nil
+	}
+
+	now := time.Now()
+	name := repo.DisplayName()
+	newEvent := func(kind Kind, msg string) Event {
+		return Event{
+			Kind:     kind,
+			RepoPath: repo.Path,
+			RepoName: name,
+			Branch:   curr.Branch,
+			Message:  msg,
+			Time:     now,
+		}
+	}
+
+	var events []Event
+	if prev.Behind == 0 && curr.Behind > 0 {
+		events = append(events, newEvent(KindBehind,
+			fmt.Sprintf("%s is %d commit(s) behind %s", name, curr.Behind, curr.Remote)))
+	}
+	if !prev.HasConflict() && curr.HasConflict() {
+		events = append(events, newEvent(KindConflict,
+			fmt.Sprintf("%s has a conflict on %s", name, branchOrHash(curr))))
+	}
+	if !prev.UpstreamGone && curr.UpstreamGone {
+		events = append(events, newEvent(KindUpstreamGone,
+			fmt.Sprintf("%s: upstream %s is gone", name, curr.Remote)))
+	}
+	if !prev.HasDiverged() && curr.HasDiverged() {
+		events = append(events, newEvent(KindPushRejected,
+			fmt.Sprintf("%s has diverged from %s (%d ahead, %d behind), push would be rejected",
+				name, curr.Remote, curr.Ahead, curr.Behind)))
+	}
+	return events
+}
+
+func branchOrHash(s *model.RepoStatus) string {
+	if s.Branch != "" {
+		return s.Branch
+	}
+	return s.CommitHash
+}
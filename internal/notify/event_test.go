@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"testing"
+
+	"github.com/jackchuka/gv/internal/model"
+)
+
+func TestDetect(t *testing.T) {
+	repo := &model.Repository{Path: "/code/gv"}
+
+	tests := []struct {
+		name string
+		prev *model.RepoStatus
+		curr *model.RepoStatus
+		want []Kind
+	}{
+		{
+			name: "initial load is not a transition",
+			prev: nil,
+			curr: &model.RepoStatus{Behind: 3},
+			want: nil,
+		},
+		{
+			name: "no change",
+			prev: &model.RepoStatus{Behind: 1},
+			curr: &model.RepoStatus{Behind: 2},
+			want: nil,
+		},
+		{
+			name: "became behind",
+			prev: &model.RepoStatus{Remote: "origin/main"},
+			curr: &model.RepoStatus{Remote: "origin/main", Behind: 2},
+			want: []Kind{KindBehind},
+		},
+		{
+			name: "conflicted files appeared",
+			prev: &model.RepoStatus{},
+			curr: &model.RepoStatus{Conflicts: 1},
+			want: []Kind{KindConflict},
+		},
+		{
+			name: "rebase started",
+			prev: &model.RepoStatus{},
+			curr: &model.RepoStatus{RebaseHead: true},
+			want: []Kind{KindConflict},
+		},
+		{
+			name: "upstream gone",
+			prev: &model.RepoStatus{Remote: "origin/feat"},
+			curr: &model.RepoStatus{Remote: "origin/feat", UpstreamGone: true},
+			want: []Kind{KindUpstreamGone},
+		},
+		{
+			name: "diverged after fetch",
+			prev: &model.RepoStatus{Ahead: 2},
+			curr: &model.RepoStatus{Ahead: 2, Behind: 1},
+			want: []Kind{KindBehind, KindPushRejected},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := Detect(repo, tt.prev, tt.curr)
+			if len(events) != len(tt.want) {
+				t.Fatalf("Detect() returned %d events, want %d: %+v", len(events), len(tt.want), events)
+			}
+			for i, ev := range events {
+				if ev.Kind != tt.want[i] {
+					t.Errorf("events[%d].Kind = %q, want %q", i, ev.Kind, tt.want[i])
+				}
+				if ev.RepoPath != repo.Path {
+					t.Errorf("events[%d].RepoPath = %q, want %q", i, ev.RepoPath, repo.Path)
+				}
+				if ev.RepoName != "gv" {
+					t.Errorf("events[%d].RepoName = %q, want %q", i, ev.RepoName, "gv")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jackchuka/gv/internal/config"
+)
+
+type rule struct {
+	kinds    []Kind // empty matches every kind
+	sink     Sink
+	throttle time.Duration
+}
+
+func (r *rule) matches(k Kind) bool {
+	return len(r.kinds) == 0 || slices.Contains(r.kinds, k)
+}
+
+// Notifier routes transition events to sinks according to configured rules,
+// suppressing repeats of the same repo and event within each rule's throttle.
+type Notifier struct {
+	rules []rule
+	now   func() time.Time
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time // "rule index|repo|kind" -> last send time
+}
+
+// New builds a Notifier from config rules. It returns nil, nil when no
+// rules are configured so callers can treat a nil Notifier as disabled.
+func New(rules []config.NotifyRule) (*Notifier, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	n := &Notifier{
+		now:      time.Now,
+		lastSent: make(map[string]time.Time),
+	}
+	for i, rc := range rules {
+		r, err := newRule(rc)
+		if err != nil {
+			return nil, fmt.Errorf("notifications[%d]: %w", i, err)
+		}
+		n.rules = append(n.rules, r)
+	}
+	return n, nil
+}
+
+func newRule(rc config.NotifyRule) (rule, error) {
+	r := rule{throttle: rc.Throttle}
+
+	for _, e := range rc.Events {
+		k := Kind(e)
+		if !slices.Contains(Kinds, k) {
+			return rule{}, fmt.Errorf("unknown event %q", e)
+		}
+		r.kinds = append(r.kinds, k)
+	}
+
+	switch rc.Sink {
+	case "desktop":
+		r.sink = DesktopSink{}
+	case "webhook":
+		if rc.URL == "" {
+			return rule{}, errors.New("webhook sink requires url")
+		}
+		r.sink = &WebhookSink{URL: rc.URL, Headers: rc.Headers, Client: &http.Client{Timeout: 10 * time.Second}}
+	case "command":
+		if rc.Command == "" {
+			return rule{}, errors.New("command sink requires command")
+		}
+		r.sink = &CommandSink{Command: rc.Command}
+	default:
+		return rule{}, fmt.Errorf("unknown sink %q", rc.Sink)
+	}
+	return r, nil
+}
+
+// Dispatch sends each event to every matching, non-throttled rule.
+// All sinks are attempted; their errors are joined.
+func (n *Notifier) Dispatch(ctx context.Context, events []Event) error {
+	if n == nil || len(events) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, ev := range events {
+		for i := range n.rules {
+			r := &n.rules[i]
+			if !r.matches(ev.Kind) {
+				continue
+			}
+			undo := n.allow(i, r, ev)
+			if undo == nil {
+				continue
+			}
+			if err := r.sink.Send(ctx, ev); err != nil {
+				undo() // not sent: let the next attempt through
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// allow records a send for the rule when it is outside the throttle
+// window. It returns a func that forgets the send again, for one that
+// failed, or nil when the rule is throttled.
+func (n *Notifier) allow(idx int, r *rule, ev Event) (undo func()) {
+	key := fmt.Sprintf("%d|%s|%s", idx, ev.RepoPath, ev.Kind)
+	now := n.now()
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	last, sent := n.lastSent[key]
+	if sent && r.throttle > 0 && now.Sub(last) < r.throttle {
+		return nil
+	}
+	n.lastSent[key] = now
+	return func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		if !n.lastSent[key].Equal(now) {
+			return // sent again since
+		}
+		if sent {
+			n.lastSent[key] = last
+		} else {
+			delete(n.lastSent, key)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackchuka/gv/internal/config"
+)
+
+type webhookRecorder struct {
+	mu     sync.Mutex
+	events []Event
+	header http.Header
+}
+
+func (w *webhookRecorder) handler(status int) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		var ev Event
+		if err := json.NewDecoder(req.Body).Decode(&ev); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.mu.Lock()
+		w.events = append(w.events, ev)
+		w.header = req.Header.Clone()
+		w.mu.Unlock()
+		rw.WriteHeader(status)
+	}
+}
+
+func (w *webhookRecorder) count() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.events)
+}
+
+func TestNew_NoRules(t *testing.T) {
+	n, err := New(nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if n != nil {
+		t.Error("New(nil) should return a nil Notifier")
+	}
+	// A nil Notifier is a no-op
+	if err := n.Dispatch(context.Background(), []Event{{Kind: KindBehind}}); err != nil {
+		t.Errorf("nil Dispatch() error = %v", err)
+	}
+}
+
+func TestNew_InvalidRules(t *testing.T) {
+	tests := []struct {
+		name string
+		rule config.NotifyRule
+	}{
+		{"unknown sink", config.NotifyRule{Sink: "pager"}},
+		{"unknown event", config.NotifyRule{Sink: "desktop", Events: []string{"exploded"}}},
+		{"webhook without url", config.NotifyRule{Sink: "webhook"}},
+		{"command without command", config.NotifyRule{Sink: "command"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New([]config.NotifyRule{tt.rule}); err == nil {
+				t.Error("New() should fail")
+			}
+		})
+	}
+}
+
+func TestNotifier_Webhook(t *testing.T) {
+	rec := &webhookRecorder{}
+	srv := httptest.NewServer(rec.handler(http.StatusNoContent))
+	defer srv.Close()
+
+	n, err := New([]config.NotifyRule{{
+		Sink:    "webhook",
+		URL:     srv.URL,
+		Headers: map[string]string{"X-Token": "secret"},
+		Events:  []string{"behind"},
+	}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	events := []Event{
+		{Kind: KindBehind, RepoPath: "/code/gv", RepoName: "gv", Branch: "main", Message: "gv is behind"},
+		{Kind: KindConflict, RepoPath: "/code/gv", RepoName: "gv"},
+	}
+	if err := n.Dispatch(context.Background(), events); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+
+	if rec.count() != 1 {
+		t.Fatalf("webhook received %d events, want 1 (conflict is not routed)", rec.count())
+	}
+	got := rec.events[0]
+	if got.Kind != KindBehind || got.RepoPath != "/code/gv" || got.Branch != "main" {
+		t.Errorf("webhook payload = %+v", got)
+	}
+	if rec.header.Get("X-Token") != "secret" {
+		t.Errorf("X-Token header = %q, want %q", rec.header.Get("X-Token"), "secret")
+	}
+}
+
+func TestNotifier_WebhookErrorStatus(t *testing.T) {
+	rec := &webhookRecorder{}
+	srv := httptest.NewServer(rec.handler(http.StatusInternalServerError))
+	defer srv.Close()
+
+	n, err := New([]config.NotifyRule{{Sink: "webhook", URL: srv.URL}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	err = n.Dispatch(context.Background(), []Event{{Kind: KindConflict, RepoPath: "/code/gv"}})
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("Dispatch() error = %v, want a 500 error", err)
+	}
+}
+
+func TestNotifier_Throttle(t *testing.T) {
+	rec := &webhookRecorder{}
+	srv := httptest.NewServer(rec.handler(http.StatusOK))
+	defer srv.Close()
+
+	n, err := New([]config.NotifyRule{{Sink: "webhook", URL: srv.URL, Throttle: time.Minute}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	n.now = func() time.Time { return now }
+
+	ev := []Event{{Kind: KindBehind, RepoPath: "/code/gv"}}
+	other := []Event{{Kind: KindBehind, RepoPath: "/code/other"}}
+	ctx := context.Background()
+
+	_ = n.Dispatch(ctx, ev)
+	_ = n.Dispatch(ctx, ev)    // throttled
+	_ = n.Dispatch(ctx, other) // different repo, not throttled
+	if rec.count() != 2 {
+		t.Fatalf("webhook received %d events within throttle window, want 2", rec.count())
+	}
+
+	now = now.Add(2 * time.Minute)
+	_ = n.Dispatch(ctx, ev)
+	if rec.count() != 3 {
+		t.Errorf("webhook received %d events after throttle window, want 3", rec.count())
+	}
+}
+
+func TestNotifier_ThrottleAfterFailedSend(t *testing.T) {
+	rec := &webhookRecorder{}
+	status := http.StatusInternalServerError
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rec.handler(status)(rw, req)
+	}))
+	defer srv.Close()
+
+	n, err := New([]config.NotifyRule{{Sink: "webhook", URL: srv.URL, Throttle: time.Minute}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	n.now = func() time.Time { return now }
+
+	ev := []Event{{Kind: KindBehind, RepoPath: "/code/gv"}}
+	ctx := context.Background()
+
+	if err := n.Dispatch(ctx, ev); err == nil {
+		t.Fatal("Dispatch() should fail on a 500")
+	}
+	status = http.StatusOK
+	now = now.Add(time.Second)
+	if err := n.Dispatch(ctx, ev); err != nil {
+		t.Fatalf("retry Dispatch() error = %v", err)
+	}
+	if rec.count() != 2 {
+		t.Fatalf("webhook received %d events, want the failed one retried", rec.count())
+	}
+
+	now = now.Add(time.Second)
+	_ = n.Dispatch(ctx, ev) // throttled after the successful send
+	if rec.count() != 2 {
+		t.Errorf("webhook received %d events within throttle window, want 2", rec.count())
+	}
+}
+
+func TestNotifier_Command(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("command hook test uses sh")
+	}
+
+	out := filepath.Join(t.TempDir(), "out.txt")
+	n, err := New([]config.NotifyRule{{
+		Sink:    "command",
+		Command: `printf '%s %s' "$GV_EVENT" "$GV_REPO_NAME" > ` + out,
+	}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ev := []Event{{Kind: KindUpstreamGone, RepoPath: "/code/gv", RepoName: "gv"}}
+	if err := n.Dispatch(context.Background(), ev); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "upstream_gone gv" {
+		t.Errorf("command output = %q, want %q", got, "upstream_gone gv")
+	}
+}
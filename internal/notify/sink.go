@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// Sink delivers a single event to an external destination.
+type Sink interface {
+	Send(ctx context.Context, ev Event) error
+}
+
+// DesktopSink shows freedesktop notifications via notify-send.
+type DesktopSink struct{}
+
+func (DesktopSink) Send(ctx context.Context, ev Event) error {
+	title := "gv: " + string(ev.Kind)
+	cmd := exec.CommandContext(ctx, "notify-send", "--app-name=gv", title, ev.Message)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("notify-send: %w: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// WebhookSink POSTs the event as JSON to URL.
+type WebhookSink struct {
+	URL     string
+	Headers map[string]string
+	Client  *http.Client
+}
+
+func (s *WebhookSink) Send(ctx context.Context, ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s: %s", s.URL, resp.Status)
+	}
+	return nil
+}
+
+// CommandSink runs a shell command with the event as JSON on stdin and
+// its fields exposed as GV_* environment variables.
+type CommandSink struct {
+	Command string
+}
+
+func (s *CommandSink) Send(ctx context.Context, ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", s.Command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", s.Command)
+	}
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(),
+		"GV_EVENT="+string(ev.Kind),
+		"GV_REPO="+ev.RepoPath,
+		"GV_REPO_NAME="+ev.RepoName,
+		"GV_BRANCH="+ev.Branch,
+		"GV_MESSAGE="+ev.Message,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("command hook: %w: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package prompt
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/jackchuka/gv/internal/model"
+)
+
+const DefaultFormat = `{{.Branch}}` +
+	`{{if .Ahead}} ↑{{.Ahead}}{{end}}{{if .Behind}} ↓{{.Behind}}{{end}}` +
+	`{{if .Staged}} +{{.Staged}}{{end}}{{if .Modified}} ~{{.Modified}}{{end}}{{if .Untracked}} ?{{.Untracked}}{{end}}` +
+	`{{if .State}} {{.State}}{{end}}`
+
+// Data is the value passed to prompt templates.
+type Data struct {
+	Name      string
+	Path      string
+	Branch    string // branch name, or the short hash when detached
+	Hash      string
+	Remote    string
+	Ahead     int
+	Behind    int
+	Staged    int
+	Modified  int
+	Untracked int
+	Conflicts int
+	Stashes   int
+	Dirty     bool
+	State     string // MERGE, REBASE, ... or empty
+	Source    string // where the status came from: daemon, cache or git
+}
+
+func NewData(repo *model.Repository, source string) Data {
+	d := Data{
+		Name:   repo.DisplayName(),
+		Path:   repo.Path,
+		Source: source,
+	}
+	s := repo.Status
+	if s == nil {
+		return d
+	}
+
+	d.Branch = s.Branch
+	if d.Branch == "" {
+		d.Branch = s.CommitHash
+	}
+	d.Hash = s.CommitHash
+	d.Remote = s.Remote
+	d.Ahead = s.Ahead
+	d.Behind = s.Behind
+	d.Staged = s.Staged
+	d.Modified = s.Modified
+	d.Untracked = s.Untracked
+	d.Conflicts = s.Conflicts
+	d.Stashes = s.Stashes
+	d.Dirty = s.IsDirty()
+	d.State = s.SpecialState()
+	return d
+}
+
+// Render executes format against d. An empty format uses DefaultFormat.
+func Render(format string, d Data) (string, error) {
+	if format == "" {
+		format = DefaultFormat
+	}
+	tmpl, err := template.New("prompt").Parse(format)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, d); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
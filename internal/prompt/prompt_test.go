@@ -0,0 +1,151 @@
+package prompt
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackchuka/gv/internal/cache"
+	"github.com/jackchuka/gv/internal/model"
+)
+
+func TestRender(t *testing.T) {
+	repo := &model.Repository{
+		Path: "/code/gv",
+		Status: &model.RepoStatus{
+			Branch:     "main",
+			Ahead:      2,
+			Behind:     1,
+			Modified:   3,
+			Untracked:  1,
+			RebaseHead: true,
+		},
+	}
+
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{"default format", "", "main ↑2 ↓1 ~3 ?1 REBASE"},
+		{"custom format", "{{.Name}}@{{.Branch}}{{if .Dirty}}*{{end}}", "gv@main*"},
+		{"source field", "{{.Source}}", "cache"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.format, NewData(repo, "cache"))
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender_DetachedUsesHash(t *testing.T) {
+	repo := &model.Repository{
+		Path:   "/code/gv",
+		Status: &model.RepoStatus{DetachedHead: true, CommitHash: "abc1234"},
+	}
+	got, err := Render("", NewData(repo, "git"))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got != "abc1234" {
+		t.Errorf("Render() = %q, want %q", got, "abc1234")
+	}
+}
+
+func TestRender_InvalidTemplate(t *testing.T) {
+	if _, err := Render("{{.Branch", Data{}); err == nil {
+		t.Error("Render() should fail on an invalid template")
+	}
+}
+
+type fakeSource struct {
+	name  string
+	repo  *model.Repository
+	err   error
+	delay time.Duration
+	calls int
+}
+
+func (f *fakeSource) Name() string { return f.name }
+
+func (f *fakeSource) Lookup(ctx context.Context, _ string) (*model.Repository, error) {
+	f.calls++
+	if f.delay > 0 {
+		select {
+		case <-time.After(f.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return f.repo, f.err
+}
+
+func TestResolve_Order(t *testing.T) {
+	withStatus := &model.Repository{Path: "/code/gv", Status: &model.RepoStatus{Branch: "main"}}
+
+	down := &fakeSource{name: "daemon", err: errors.New("no daemon")}
+	noStatus := &fakeSource{name: "cache", repo: &model.Repository{Path: "/code/gv"}}
+	git := &fakeSource{name: "git", repo: withStatus}
+	never := &fakeSource{name: "never", repo: withStatus}
+
+	repo, src := Resolve(context.Background(), "/code/gv", down, noStatus, git, never)
+	if repo != withStatus || src != "git" {
+		t.Errorf("Resolve() = (%v, %q), want git's repo", repo, src)
+	}
+	if never.calls != 0 {
+		t.Error("sources after the first hit should not be consulted")
+	}
+}
+
+func TestResolve_StopsAtDeadline(t *testing.T) {
+	slow := &fakeSource{name: "daemon", delay: time.Second}
+	after := &fakeSource{name: "git", repo: &model.Repository{Status: &model.RepoStatus{}}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	repo, _ := Resolve(ctx, "/code/gv", slow, after)
+	if repo != nil {
+		t.Error("Resolve() should give up once the budget is spent")
+	}
+	if after.calls != 0 {
+		t.Error("sources after the deadline should be skipped")
+	}
+}
+
+func TestCacheSource_MaxAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repos.json")
+	repos := []model.Repository{
+		{Path: "/code/fresh", Status: &model.RepoStatus{}, LastScanned: time.Now()},
+		{Path: "/code/stale", Status: &model.RepoStatus{}, LastScanned: time.Now().Add(-time.Hour)},
+	}
+	if err := cache.Save(path, repos); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	fresh := CacheSource{Path: path, MaxAge: time.Minute}
+	anyAge := CacheSource{Path: path}
+
+	if r, _ := fresh.Lookup(ctx, "/code/fresh"); r == nil {
+		t.Error("fresh entry should be served")
+	}
+	if r, _ := fresh.Lookup(ctx, "/code/stale"); r != nil {
+		t.Error("stale entry should be skipped when MaxAge is set")
+	}
+	if r, _ := anyAge.Lookup(ctx, "/code/stale"); r == nil {
+		t.Error("stale entry should be served when MaxAge is zero")
+	}
+	if r, _ := anyAge.Lookup(ctx, "/code/unknown"); r != nil {
+		t.Error("unknown repo should not be found")
+	}
+}
@@ -0,0 +1,89 @@
+package prompt
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackchuka/gv/internal/cache"
+	"github.com/jackchuka/gv/internal/daemon"
+	"github.com/jackchuka/gv/internal/model"
+	"github.com/jackchuka/gv/internal/status"
+)
+
+// Source supplies the status of a single repo. Lookup returns nil, nil
+// when the source has nothing (or nothing fresh enough) for the repo.
+type Source interface {
+	Name() string
+	Lookup(ctx context.Context, repoPath string) (*model.Repository, error)
+}
+
+// Resolve asks each source in order and returns the first repo with a
+// status, together with the name of the source that answered. Remaining
+// sources are skipped once ctx is done.
+func Resolve(ctx context.Context, repoPath string, sources ...Source) (*model.Repository, string) {
+	for _, src := range sources {
+		if ctx.Err() != nil {
+			break
+		}
+		repo, err := src.Lookup(ctx, repoPath)
+		if err == nil && repo != nil && repo.Status != nil {
+			return repo, src.Name()
+		}
+	}
+	return nil, ""
+}
+
+// DaemonSource reads from a running daemon.
+type DaemonSource struct {
+	SocketPath string
+}
+
+func (DaemonSource) Name() string { return "daemon" }
+
+func (s DaemonSource) Lookup(ctx context.Context, repoPath string) (*model.Repository, error) {
+	client, err := daemon.Connect(ctx, s.SocketPath)
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseIdleConnections()
+	return client.Repo(ctx, repoPath)
+}
+
+// CacheSource reads from the persisted snapshot. Entries older than MaxAge
+// are ignored; a zero MaxAge accepts any age.
+type CacheSource struct {
+	Path   string
+	MaxAge time.Duration
+}
+
+func (CacheSource) Name() string { return "cache" }
+
+func (s CacheSource) Lookup(_ context.Context, repoPath string) (*model.Repository, error) {
+	snap, err := cache.Load(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	repo := snap.Find(repoPath)
+	if repo == nil {
+		return nil, nil
+	}
+	if s.MaxAge > 0 && time.Since(repo.LastScanned) > s.MaxAge {
+		return nil, nil
+	}
+	return repo, nil
+}
+
+// GitSource reads the status directly with git.
+type GitSource struct {
+	Reader status.Reader
+}
+
+func (GitSource) Name() string { return "git" }
+
+func (s GitSource) Lookup(ctx context.Context, repoPath string) (*model.Repository, error) {
+	st, err := s.Reader.GetStatus(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Repository{Path: repoPath, Status: st, LastScanned: time.Now()}, nil
+}
@@ -0,0 +1,419 @@
+// Package query parses the filter language of the repo list, such as
+//
+//	dirty ahead>0 branch:feat/* owner:acme age>14d -worktree
+//
+// A query is a list of terms that must all match. A term is a keyword
+// (dirty), a comparison (ahead>0, age>14d), a glob over a text field
+// (branch:feat/*), a tag (#go) or free text matched against name, path,
+// group and tags. A leading - negates a term, and double quotes keep
+// spaces inside one.
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackchuka/gv/internal/model"
+)
+
+// now is replaced in tests.
+var now = time.Now
+
+// Query is a parsed filter. The zero value matches every repo.
+type Query struct {
+	terms []term
+}
+
+type term struct {
+	negate bool
+	match  func(r *model.Repository) bool
+}
+
+// Error describes a term that could not be parsed. Pos is the byte offset
+// of the term in the query.
+type Error struct {
+	Pos  int
+	Term string
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Term, e.Msg)
+}
+
+// Parse parses s. An empty s yields a query that matches everything.
+func Parse(s string) (*Query, error) {
+	words, err := split(s)
+	if err != nil {
+		return nil, err
+	}
+	q := &Query{}
+	for _, w := range words {
+		t, err := parseTerm(w.text)
+		if err != nil {
+			return nil, &Error{Pos: w.pos, Term: w.text, Msg: err.Error()}
+		}
+		q.terms = append(q.terms, t)
+	}
+	return q, nil
+}
+
+// Match reports whether r satisfies every term of q. Terms about the
+// status or diff never match a repo whose status or diff is not loaded,
+// and their negation always does.
+func (q *Query) Match(r *model.Repository) bool {
+	if q == nil {
+		return true
+	}
+	for _, t := range q.terms {
+		if t.match(r) == t.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// Empty reports whether q has no terms.
+func (q *Query) Empty() bool {
+	return q == nil || len(q.terms) == 0
+}
+
+type word struct {
+	text string
+	pos  int
+}
+
+// split breaks s into whitespace-separated words, keeping quoted runs
+// together and dropping the quotes.
+func split(s string) ([]word, error) {
+	var (
+		words   []word
+		cur     strings.Builder
+		start   = -1
+		inQuote = -1
+	)
+	flush := func() {
+		if start >= 0 {
+			words = append(words, word{cur.String(), start})
+			cur.Reset()
+			start = -1
+		}
+	}
+	for i, c := range s {
+		switch {
+		case c == '"':
+			if start < 0 {
+				start = i
+			}
+			if inQuote >= 0 {
+				inQuote = -1
+			} else {
+				inQuote = i
+			}
+		case inQuote < 0 && (c == ' ' || c == '\t'):
+			flush()
+		default:
+			if start < 0 {
+				start = i
+			}
+			cur.WriteRune(c)
+		}
+	}
+	if inQuote >= 0 {
+		return nil, &Error{Pos: inQuote, Term: s[inQuote:], Msg: "unterminated quote"}
+	}
+	flush()
+	return words, nil
+}
+
+func parseTerm(s string) (term, error) {
+	var t term
+	if rest, ok := strings.CutPrefix(s, "-"); ok && rest != "" {
+		t.negate = true
+		s = rest
+	}
+
+	if tag, ok := strings.CutPrefix(s, "#"); ok && tag != "" {
+		m, err := globField("tag", tag)
+		t.match = m
+		return t, err
+	}
+
+	if m, ok := repoKeywords[strings.ToLower(s)]; ok {
+		t.match = m
+		return t, nil
+	}
+	if m, ok := keywords[strings.ToLower(s)]; ok {
+		t.match = withStatus(m)
+		return t, nil
+	}
+
+	if i := strings.IndexAny(s, ":<>="); i > 0 {
+		field, rest := strings.ToLower(s[:i]), s[i:]
+		var err error
+		if rest[0] == ':' {
+			t.match, err = globField(field, rest[1:])
+		} else {
+			t.match, err = compareField(field, rest)
+		}
+		return t, err
+	}
+
+	t.match = func(r *model.Repository) bool { return MatchText(r, s) }
+	return t, nil
+}
+
+// MatchText reports whether text occurs, ignoring case, in r's name, path,
+// group or one of its tags.
+func MatchText(r *model.Repository, text string) bool {
+	if containsFold(r.DisplayName(), text) || containsFold(r.Path, text) || containsFold(r.Group, text) {
+		return true
+	}
+	return slices.ContainsFunc(r.Tags, func(tag string) bool { return containsFold(tag, text) })
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// withStatus adapts a status predicate to a repo one that fails while the
+// status is unknown.
+func withStatus(f func(*model.RepoStatus) bool) func(*model.Repository) bool {
+	return func(r *model.Repository) bool { return r.Status != nil && f(r.Status) }
+}
+
+// keywords are about the status.
+var keywords = map[string]func(*model.RepoStatus) bool{
+	"dirty":     func(s *model.RepoStatus) bool { return s.IsDirty() },
+	"clean":     func(s *model.RepoStatus) bool { return !s.IsDirty() },
+	"ahead":     func(s *model.RepoStatus) bool { return s.Ahead > 0 },
+	"behind":    func(s *model.RepoStatus) bool { return s.Behind > 0 },
+	"diverged":  func(s *model.RepoStatus) bool { return s.HasDiverged() },
+	"conflict":  func(s *model.RepoStatus) bool { return s.HasConflict() },
+	"gone":      func(s *model.RepoStatus) bool { return s.UpstreamGone },
+	"detached":  func(s *model.RepoStatus) bool { return s.DetachedHead },
+	"stashed":   func(s *model.RepoStatus) bool { return s.Stashes > 0 },
+	"untracked": func(s *model.RepoStatus) bool { return s.Untracked > 0 },
+}
+
+// repoKeywords need no status.
+var repoKeywords = map[string]func(*model.Repository) bool{
+	"worktree":  func(r *model.Repository) bool { return r.IsWorktree },
+	"submodule": func(r *model.Repository) bool { return r.IsSubmodule() },
+	"bare":      func(r *model.Repository) bool { return r.IsBare() },
+	"pinned":    func(r *model.Repository) bool { return r.Pinned },
+	"nofetch":   func(r *model.Repository) bool { return r.FetchDisabled },
+}
+
+// textFields are matched with field:glob.
+var textFields = map[string]func(*model.Repository) []string{
+	"name":  func(r *model.Repository) []string { return []string{r.DisplayName()} },
+	"path":  func(r *model.Repository) []string { return []string{r.Path} },
+	"group": func(r *model.Repository) []string { return []string{r.Group} },
+	"tag":   func(r *model.Repository) []string { return r.Tags },
+	"kind": func(r *model.Repository) []string {
+		if r.Kind == "" {
+			return []string{string(model.KindNormal)}
+		}
+		return []string{string(r.Kind)}
+	},
+	"branch": statusField(func(s *model.RepoStatus) string { return s.Branch }),
+	"owner":  statusField(func(s *model.RepoStatus) string { return s.Owner }),
+	"remote": statusField(func(s *model.RepoStatus) string { return s.Remote }),
+	"state":  statusField(func(s *model.RepoStatus) string { return s.SpecialState() }),
+}
+
+func statusField(f func(*model.RepoStatus) string) func(*model.Repository) []string {
+	return func(r *model.Repository) []string {
+		if r.Status == nil {
+			return nil
+		}
+		return []string{f(r.Status)}
+	}
+}
+
+func globField(field, pattern string) (func(*model.Repository) bool, error) {
+	values, ok := textFields[field]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q (want one of %s)", field, fieldNames(textFields))
+	}
+	if pattern == "" {
+		return nil, fmt.Errorf("missing pattern after %s:", field)
+	}
+	re := globRegexp(pattern)
+	return func(r *model.Repository) bool {
+		return slices.ContainsFunc(values(r), re.MatchString)
+	}, nil
+}
+
+// globRegexp compiles a glob in which * matches any run of characters,
+// slashes included, and ? any one character, ignoring case. A pattern
+// without wildcards must match the whole value.
+func globRegexp(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("(?i)^")
+	for _, c := range pattern {
+		switch c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// numFields are compared with <, <=, >, >= and =.
+var numFields = map[string]func(*model.Repository) (int, bool){
+	"ahead":     statusNum(func(s *model.RepoStatus) int { return s.Ahead }),
+	"behind":    statusNum(func(s *model.RepoStatus) int { return s.Behind }),
+	"staged":    statusNum(func(s *model.RepoStatus) int { return s.Staged }),
+	"modified":  statusNum(func(s *model.RepoStatus) int { return s.Modified }),
+	"untracked": statusNum(func(s *model.RepoStatus) int { return s.Untracked }),
+	"conflicts": statusNum(func(s *model.RepoStatus) int { return s.Conflicts }),
+	"stashes":   statusNum(func(s *model.RepoStatus) int { return s.Stashes }),
+	"changes":   statusNum(func(s *model.RepoStatus) int { return s.Staged + s.Modified + s.Untracked }),
+	"added":     diffNum(func(d *model.DiffStats) int { return d.TotalAdded }),
+	"deleted":   diffNum(func(d *model.DiffStats) int { return d.TotalDeleted }),
+	"diff":      diffNum(func(d *model.DiffStats) int { return d.TotalDiffVolume() }),
+	"commits": diffNum(func(d *model.DiffStats) int {
+		n := 0
+		for _, c := range d.DailyCommits {
+			n += c
+		}
+		return n
+	}),
+}
+
+func statusNum(f func(*model.RepoStatus) int) func(*model.Repository) (int, bool) {
+	return func(r *model.Repository) (int, bool) {
+		if r.Status == nil {
+			return 0, false
+		}
+		return f(r.Status), true
+	}
+}
+
+func diffNum(f func(*model.DiffStats) int) func(*model.Repository) (int, bool) {
+	return func(r *model.Repository) (int, bool) {
+		if r.Diff == nil {
+			return 0, false
+		}
+		return f(r.Diff), true
+	}
+}
+
+// durationFields hold the time since an event and take values like 14d.
+var durationFields = map[string]func(*model.Repository) (time.Time, bool){
+	"age": func(r *model.Repository) (time.Time, bool) {
+		if r.Status == nil || r.Status.LastCommit.IsZero() {
+			return time.Time{}, false
+		}
+		return r.Status.LastCommit, true
+	},
+	"modified_age": func(r *model.Repository) (time.Time, bool) {
+		if r.Status == nil || r.Status.LastModified.IsZero() {
+			return time.Time{}, false
+		}
+		return r.Status.LastModified, true
+	},
+}
+
+func compareField(field, rest string) (func(*model.Repository) bool, error) {
+	op, value := splitOp(rest)
+	if value == "" {
+		return nil, fmt.Errorf("missing value after %s%s", field, op)
+	}
+
+	if get, ok := numFields[field]; ok {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", value)
+		}
+		return func(r *model.Repository) bool {
+			v, ok := get(r)
+			return ok && compare(op, v, n)
+		}, nil
+	}
+
+	if get, ok := durationFields[field]; ok {
+		d, err := ParseDuration(value)
+		if err != nil {
+			return nil, err
+		}
+		return func(r *model.Repository) bool {
+			t, ok := get(r)
+			return ok && compare(op, now().Sub(t), d)
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown field %q (want one of %s, %s)", field, fieldNames(numFields), fieldNames(durationFields))
+}
+
+func splitOp(s string) (op, value string) {
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if v, ok := strings.CutPrefix(s, op); ok {
+			return op, v
+		}
+	}
+	return s[:1], s[1:]
+}
+
+func compare[T int | time.Duration](op string, a, b T) bool {
+	switch op {
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	default:
+		return a == b
+	}
+}
+
+var durationUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+	"y": 365 * 24 * time.Hour,
+}
+
+// ParseDuration parses a whole number followed by one of the units s, m,
+// h, d (days), w (weeks) or y (365-day years).
+func ParseDuration(s string) (time.Duration, error) {
+	i := strings.IndexFunc(s, func(c rune) bool { return c < '0' || c > '9' })
+	if i <= 0 {
+		return 0, fmt.Errorf("%q is not a duration like 14d", s)
+	}
+	unit, ok := durationUnits[s[i:]]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q in %q (want s, m, h, d, w or y)", s[i:], s)
+	}
+	n, err := strconv.Atoi(s[:i])
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a duration like 14d", s)
+	}
+	return time.Duration(n) * unit, nil
+}
+
+func fieldNames[V any](fields map[string]V) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
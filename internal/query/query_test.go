@@ -0,0 +1,141 @@
+package query
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackchuka/gv/internal/model"
+)
+
+func TestQuery_Match(t *testing.T) {
+	fixed := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fixed }
+	t.Cleanup(func() { now = time.Now })
+
+	repos := map[string]model.Repository{
+		"api": {
+			Path:  "/code/acme/api",
+			Group: "work",
+			Tags:  []string{"go", "backend"},
+			Status: &model.RepoStatus{
+				Branch: "feat/login", Owner: "acme", Remote: "origin/feat/login",
+				Modified: 2, Ahead: 3, LastCommit: fixed.Add(-20 * 24 * time.Hour),
+			},
+			Diff: &model.DiffStats{TotalAdded: 40, TotalDeleted: 10, DailyCommits: [7]int{1, 0, 2}},
+		},
+		"web": {
+			Path:   "/code/acme/web",
+			Pinned: true,
+			Status: &model.RepoStatus{Branch: "main", Owner: "acme", Behind: 1, LastCommit: fixed.Add(-time.Hour)},
+		},
+		"api-wt": {
+			Path: "/code/acme/api-wt", IsWorktree: true, MainWorktree: "/code/acme/api", Kind: model.KindWorktree,
+			Status: &model.RepoStatus{Branch: "fix/typo", Owner: "acme", MergeHead: true},
+		},
+		"dots": {
+			Path: "/home/me/dotfiles", Name: "dotfiles", FetchDisabled: true,
+		},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"api", "web", "api-wt", "dots"}},
+		{"dirty", []string{"api"}},
+		{"clean", []string{"web", "api-wt"}},
+		{"-dirty", []string{"web", "api-wt", "dots"}},
+		{"ahead>0", []string{"api"}},
+		{"ahead>=3 behind=0", []string{"api"}},
+		{"behind<1", []string{"api", "api-wt"}},
+		{"branch:feat/*", []string{"api"}},
+		{"branch:FEAT/*", []string{"api"}},
+		{"branch:main", []string{"web"}},
+		{"owner:acme -worktree", []string{"api", "web"}},
+		{"age>14d", []string{"api"}},
+		{"age<1d", []string{"web"}},
+		{"worktree", []string{"api-wt"}},
+		{"kind:worktree", []string{"api-wt"}},
+		{"conflict", []string{"api-wt"}},
+		{"state:merge", []string{"api-wt"}},
+		{"pinned", []string{"web"}},
+		{"nofetch", []string{"dots"}},
+		{"#go", []string{"api"}},
+		{"tag:back*", []string{"api"}},
+		{"group:work", []string{"api"}},
+		{"diff>40 commits=3", []string{"api"}},
+		{"api", []string{"api", "api-wt"}},
+		{"api -wt", []string{"api"}},
+		{"dot", []string{"dots"}},
+		{"dirty ahead>0 branch:feat/* owner:acme age>14d -worktree", []string{"api"}},
+		{`"acme/api"`, []string{"api", "api-wt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.query, err)
+			}
+			want := make(map[string]bool)
+			for _, name := range tt.want {
+				want[name] = true
+			}
+			for name, r := range repos {
+				if got := q.Match(&r); got != want[name] {
+					t.Errorf("Match(%s) = %v, want %v", name, got, want[name])
+				}
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+	}{
+		{"dirty colour:red", 6},
+		{"ahead>x", 0},
+		{"ahead>", 0},
+		{"age>14q", 0},
+		{"age>d", 0},
+		{"branch:", 0},
+		{`dirty "unterminated`, 6},
+		{"-size>3", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			var qe *Error
+			if !errors.As(err, &qe) {
+				t.Fatalf("Parse(%q) error = %v, want *Error", tt.query, err)
+			}
+			if qe.Pos != tt.pos {
+				t.Errorf("Pos = %d, want %d", qe.Pos, tt.pos)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"30s", 30 * time.Second},
+		{"90m", 90 * time.Minute},
+		{"12h", 12 * time.Hour},
+		{"14d", 14 * 24 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+		{"1y", 365 * 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// Formats lists the values accepted by Write.
+var Formats = []string{"md", "html", "json"}
+
+// Write renders rep in format: md, html or json.
+func Write(w io.Writer, rep *Report, format string) error {
+	switch format {
+	case "md":
+		return markdownTmpl.Execute(w, rep)
+	case "html":
+		return htmlTmpl.Execute(w, rep)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rep)
+	default:
+		return fmt.Errorf("unknown format %q (want %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+var funcs = map[string]any{
+	"date":        func(r *Report) string { return r.Since.Format("2006-01-02") },
+	"today":       func(r *Report) string { return r.Generated.Format("2006-01-02") },
+	"plural":      plural,
+	"branchState": branchState,
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, word)
+	}
+	if strings.HasSuffix(word, "h") {
+		return fmt.Sprintf("%d %ses", n, word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
+
+var markdownTmpl = template.Must(template.New("md").Funcs(funcs).Parse(
+	`# Activity {{date .}} – {{today .}}
+
+{{with .Totals}}**{{plural .Commits "commit"}}** in {{plural .Active "repo"}} · +{{.Added}} −{{.Deleted}} lines · {{.Dirty}} dirty · {{plural .Unpushed "unpushed branch"}} · {{plural .Stashes "stash"}}{{end}}
+{{range .Repos}}
+## {{.Name}}{{if .Branch}} ({{.Branch}}){{end}}
+
+{{if .Commits}}- {{plural .Commits "commit"}}, +{{.Added}} −{{.Deleted}}
+{{end}}{{with .HotFiles}}- Hot files:{{range $i, $f := .}}{{if $i}},{{end}} ` + "`{{$f.Path}}`" + ` ({{$f.Count}}){{end}}
+{{end}}{{if .Dirty}}- Uncommitted changes: {{.Changes}}
+{{end}}{{range .Unpushed}}- Unpushed: ` + "`{{.Name}}`" + ` ({{branchState .}})
+{{end}}{{range .Stashes}}- Stash ` + "`{{.Ref}}`" + `: {{.Message}}
+{{end}}{{else}}
+_No activity._
+{{end}}`))
+
+var htmlTmpl = htmltemplate.Must(htmltemplate.New("html").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Activity {{date .}} – {{today .}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 48em; margin: 2em auto; color: #222; }
+h2 { border-bottom: 1px solid #ddd; padding-bottom: .2em; }
+code { background: #f4f4f4; padding: 0 .2em; }
+.add { color: #1a7f37; } .del { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>Activity {{date .}} – {{today .}}</h1>
+{{with .Totals}}<p><strong>{{plural .Commits "commit"}}</strong> in {{plural .Active "repo"}} · <span class="add">+{{.Added}}</span> <span class="del">−{{.Deleted}}</span> lines · {{.Dirty}} dirty · {{plural .Unpushed "unpushed branch"}} · {{plural .Stashes "stash"}}</p>{{end}}
+{{range .Repos}}
+<h2>{{.Name}}{{if .Branch}} <small>({{.Branch}})</small>{{end}}</h2>
+<ul>
+{{if .Commits}}<li>{{plural .Commits "commit"}}, <span class="add">+{{.Added}}</span> <span class="del">−{{.Deleted}}</span></li>
+{{end}}{{with .HotFiles}}<li>Hot files:{{range $i, $f := .}}{{if $i}},{{end}} <code>{{$f.Path}}</code> ({{$f.Count}}){{end}}</li>
+{{end}}{{if .Dirty}}<li>Uncommitted changes: {{.Changes}}</li>
+{{end}}{{range .Unpushed}}<li>Unpushed: <code>{{.Name}}</code> ({{branchState .}})</li>
+{{end}}{{range .Stashes}}<li>Stash <code>{{.Ref}}</code>: {{.Message}}</li>
+{{end}}</ul>
+{{else}}
+<p><em>No activity.</em></p>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,153 @@
+// Package report turns repo statuses and recent history into a standup
+// style activity report rendered as Markdown, HTML or JSON.
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackchuka/gv/internal/model"
+)
+
+// hotFiles is the number of most touched files listed per repo.
+const hotFiles = 5
+
+type Report struct {
+	Since     time.Time    `json:"since"`
+	Generated time.Time    `json:"generated"`
+	Totals    Totals       `json:"totals"`
+	Repos     []RepoReport `json:"repos"`
+}
+
+type Totals struct {
+	Repos    int `json:"repos"`  // Repos scanned
+	Active   int `json:"active"` // Repos with commits in the period
+	Commits  int `json:"commits"`
+	Added    int `json:"added"`
+	Deleted  int `json:"deleted"`
+	Dirty    int `json:"dirty"`
+	Unpushed int `json:"unpushed"` // Branches with unpushed commits
+	Stashes  int `json:"stashes"`
+}
+
+// RepoReport is the section of a single repo. Only repos with commits,
+// local changes, unpushed branches or stashes are listed.
+type RepoReport struct {
+	Name     string                 `json:"name"`
+	Path     string                 `json:"path"`
+	Branch   string                 `json:"branch,omitempty"`
+	Commits  int                    `json:"commits"`
+	Added    int                    `json:"added"`
+	Deleted  int                    `json:"deleted"`
+	HotFiles []model.FileChurnEntry `json:"hot_files,omitempty"`
+
+	Dirty     bool `json:"dirty"`
+	Staged    int  `json:"staged,omitempty"`
+	Modified  int  `json:"modified,omitempty"`
+	Untracked int  `json:"untracked,omitempty"`
+
+	Unpushed []model.BranchInfo `json:"unpushed,omitempty"`
+	Stashes  []model.StashEntry `json:"stashes,omitempty"`
+}
+
+// Build assembles a report from repos (with statuses) and their activity
+// keyed by path. Branches without an upstream are only reported as
+// unpushed when they have commits in the period, so old local branches
+// don't swamp the report.
+func Build(repos []model.Repository, activity map[string]*model.Activity, since, now time.Time) *Report {
+	rep := &Report{Since: since, Generated: now}
+
+	for _, r := range repos {
+		rep.Totals.Repos++
+
+		rr := RepoReport{Name: r.DisplayName(), Path: r.Path}
+		if st := r.Status; st != nil {
+			rr.Branch = st.Branch
+			rr.Dirty = st.IsDirty()
+			rr.Staged, rr.Modified, rr.Untracked = st.Staged, st.Modified, st.Untracked
+		}
+		if a := activity[r.Path]; a != nil {
+			rr.Commits, rr.Added, rr.Deleted = a.Commits, a.Added, a.Deleted
+			rr.HotFiles = a.TopChurnFiles(hotFiles)
+			rr.Stashes = a.Stashes
+			for _, b := range a.Unpushed() {
+				if b.Upstream == "" && b.LastCommit.Before(since) {
+					continue
+				}
+				rr.Unpushed = append(rr.Unpushed, b)
+			}
+		}
+
+		if rr.Commits == 0 && !rr.Dirty && len(rr.Unpushed) == 0 && len(rr.Stashes) == 0 {
+			continue
+		}
+
+		if rr.Commits > 0 {
+			rep.Totals.Active++
+		}
+		if rr.Dirty {
+			rep.Totals.Dirty++
+		}
+		rep.Totals.Commits += rr.Commits
+		rep.Totals.Added += rr.Added
+		rep.Totals.Deleted += rr.Deleted
+		rep.Totals.Unpushed += len(rr.Unpushed)
+		rep.Totals.Stashes += len(rr.Stashes)
+		rep.Repos = append(rep.Repos, rr)
+	}
+
+	// Busiest repos first
+	sort.SliceStable(rep.Repos, func(i, j int) bool {
+		if rep.Repos[i].Commits != rep.Repos[j].Commits {
+			return rep.Repos[i].Commits > rep.Repos[j].Commits
+		}
+		return rep.Repos[i].Name < rep.Repos[j].Name
+	})
+
+	return rep
+}
+
+// ParseSince accepts a lookback such as 7d, 2w or 36h, or a date in
+// YYYY-MM-DD form, and returns the start of the period relative to now.
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t, nil
+	}
+
+	if n := len(s); n > 1 && (s[n-1] == 'd' || s[n-1] == 'w') {
+		v, err := strconv.Atoi(s[:n-1])
+		if err != nil || v < 0 {
+			return time.Time{}, fmt.Errorf("invalid period %q", s)
+		}
+		if s[n-1] == 'w' {
+			v *= 7
+		}
+		return now.AddDate(0, 0, -v), nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("invalid period %q (want e.g. 7d, 2w, 36h or 2006-01-02)", s)
+	}
+	return now.Add(-d), nil
+}
+
+// Changes formats the uncommitted file counts as +staged ~modified ?untracked.
+func (r RepoReport) Changes() string {
+	return fmt.Sprintf("+%d ~%d ?%d", r.Staged, r.Modified, r.Untracked)
+}
+
+func branchState(b model.BranchInfo) string {
+	switch {
+	case b.Upstream == "":
+		return "no upstream"
+	case b.UpstreamGone:
+		return b.Upstream + " gone"
+	default:
+		return fmt.Sprintf("%d ahead of %s", b.Ahead, b.Upstream)
+	}
+}
@@ -0,0 +1,168 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackchuka/gv/internal/model"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{"7d", time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC), false},
+		{"2w", time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), false},
+		{"36h", time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC), false},
+		{"2026-03-01", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), false},
+		{"xd", time.Time{}, true},
+		{"yesterday", time.Time{}, true},
+		{"-1h", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSince(tt.in, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSince(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseSince(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func testReport() *Report {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	since := now.AddDate(0, 0, -7)
+
+	repos := []model.Repository{
+		{Path: "/code/api", Status: &model.RepoStatus{Branch: "main"}},
+		{Path: "/code/web", Status: &model.RepoStatus{Branch: "dev", Modified: 2}},
+		{Path: "/code/quiet", Status: &model.RepoStatus{Branch: "main"}},
+	}
+	activity := map[string]*model.Activity{
+		"/code/api": {
+			Commits: 3, Added: 40, Deleted: 10,
+			FileChurn: map[string]int{"server.go": 3, "README.md": 1},
+			Branches: []model.BranchInfo{
+				{Name: "main", Upstream: "origin/main"},
+				{Name: "feature", Upstream: "origin/feature", Ahead: 2},
+				{Name: "old-local", LastCommit: since.AddDate(0, -1, 0)},
+				{Name: "new-local", LastCommit: now},
+			},
+		},
+		"/code/web": {
+			Stashes: []model.StashEntry{{Ref: "stash@{0}", Message: "WIP on dev: <b>tweak</b>"}},
+		},
+		"/code/quiet": {
+			Branches: []model.BranchInfo{{Name: "main", Upstream: "origin/main"}},
+		},
+	}
+	return Build(repos, activity, since, now)
+}
+
+func TestBuild(t *testing.T) {
+	rep := testReport()
+
+	if len(rep.Repos) != 2 {
+		t.Fatalf("got %d repos, want 2 (quiet repo omitted)", len(rep.Repos))
+	}
+	api, web := rep.Repos[0], rep.Repos[1]
+	if api.Name != "api" || web.Name != "web" {
+		t.Fatalf("order = %s, %s; want busiest first", api.Name, web.Name)
+	}
+
+	if len(api.HotFiles) != 2 || api.HotFiles[0].Path != "server.go" {
+		t.Errorf("HotFiles = %v", api.HotFiles)
+	}
+	var unpushed []string
+	for _, b := range api.Unpushed {
+		unpushed = append(unpushed, b.Name)
+	}
+	if strings.Join(unpushed, ",") != "feature,new-local" {
+		t.Errorf("Unpushed = %v, want feature and new-local", unpushed)
+	}
+
+	want := Totals{Repos: 3, Active: 1, Commits: 3, Added: 40, Deleted: 10, Dirty: 1, Unpushed: 2, Stashes: 1}
+	if rep.Totals != want {
+		t.Errorf("Totals = %+v, want %+v", rep.Totals, want)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	rep := testReport()
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"md", []string{
+			"# Activity 2026-03-08 – 2026-03-15",
+			"**3 commits** in 1 repo",
+			"## api (main)",
+			"- Hot files: `server.go` (3), `README.md` (1)",
+			"- Unpushed: `feature` (2 ahead of origin/feature)",
+			"- Unpushed: `new-local` (no upstream)",
+			"- Uncommitted changes: +0 ~2 ?0",
+			"- Stash `stash@{0}`: WIP on dev: <b>tweak</b>",
+		}},
+		{"html", []string{
+			"<h2>api <small>(main)</small></h2>",
+			"<code>server.go</code> (3)",
+			"WIP on dev: &lt;b&gt;tweak&lt;/b&gt;",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var b strings.Builder
+			if err := Write(&b, rep, tt.format); err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(b.String(), w) {
+					t.Errorf("output missing %q:\n%s", w, b.String())
+				}
+			}
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var b strings.Builder
+		if err := Write(&b, rep, "json"); err != nil {
+			t.Fatal(err)
+		}
+		var got Report
+		if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Totals != rep.Totals || len(got.Repos) != 2 {
+			t.Errorf("round trip = %+v", got)
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		if err := Write(&strings.Builder{}, rep, "pdf"); err == nil {
+			t.Error("unknown format should fail")
+		}
+	})
+}
+
+func TestWrite_Empty(t *testing.T) {
+	rep := Build(nil, nil, time.Now().AddDate(0, 0, -1), time.Now())
+	var b strings.Builder
+	if err := Write(&b, rep, "md"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "_No activity._") {
+		t.Errorf("empty report = %q", b.String())
+	}
+}
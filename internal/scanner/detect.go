@@ -2,59 +2,86 @@
 package scanner
 
 import (
+	"bufio"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/jackchuka/gv/internal/gitdir"
 	"github.com/jackchuka/gv/internal/model"
 )
 
+// detectGitDir returns the repository rooted at path, nil if path is not a
+// repository, or ErrOptedOut if the repository asked to be skipped.
 func detectGitDir(path string) (*model.Repository, error) {
-	gitPath := filepath.Join(path, ".git")
-
-	info, err := os.Stat(gitPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
+	layout, err := gitdir.Resolve(path)
+	if err != nil || layout == nil {
 		return nil, err
 	}
 
 	repo := &model.Repository{
-		Path: path,
+		Path:   path,
+		Kind:   model.KindNormal,
+		GitDir: layout.GitDir,
 	}
 
-	if info.IsDir() {
-		// Normal git repository
-		repo.IsWorktree = false
-		return repo, nil
+	switch {
+	case layout.Linked:
+		repo.Kind = model.KindWorktree
+		repo.IsWorktree = true
+		repo.MainWorktree = gitdir.Owner(layout.CommonDir)
+	case layout.Bare:
+		repo.Kind = model.KindBare
+	case layout.SuperGitDir != "":
+		repo.Kind = model.KindSubmodule
+		repo.Superproject = gitdir.Owner(layout.SuperGitDir)
 	}
 
-	// .git is a file - this is a worktree
-	repo.IsWorktree = true
+	// Linked worktrees share the config of their main repository
+	if optedOut(path, layout.CommonDir) {
+		return nil, ErrOptedOut
+	}
 
-	content, err := os.ReadFile(gitPath)
+	return repo, nil
+}
+
+// FindRepo returns the repository enclosing path, walking up towards the
+// filesystem root. It returns nil when path is not inside a repository
+// or the enclosing repository opted out of gv.
+func FindRepo(path string) (*model.Repository, error) {
+	dir, err := filepath.Abs(path)
 	if err != nil {
 		return nil, err
 	}
-
-	// Parse "gitdir: /path/to/main/.git/worktrees/name"
-	line := strings.TrimSpace(string(content))
-	if strings.HasPrefix(line, "gitdir:") {
-		gitdir := strings.TrimSpace(strings.TrimPrefix(line, "gitdir:"))
-		// Extract main repo path from .git/worktrees/name
-		if idx := strings.Index(gitdir, "/.git/worktrees/"); idx != -1 {
-			repo.MainWorktree = gitdir[:idx]
+	for {
+		repo, err := detectGitDir(dir)
+		if errors.Is(err, ErrOptedOut) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if repo != nil {
+			return repo, nil
 		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
 	}
-
-	return repo, nil
 }
 
-// discoverWorktrees finds linked worktrees registered in .git/worktrees/.
-// Each entry contains a "gitdir" file pointing to the worktree's working directory.
-func discoverWorktrees(repoPath string) []model.Repository {
-	wtDir := filepath.Join(repoPath, ".git", "worktrees")
+// discoverWorktrees finds linked worktrees registered in the worktrees
+// directory of repo's git dir. Each entry contains a "gitdir" file pointing
+// to the worktree's .git file.
+func discoverWorktrees(repo *model.Repository) []model.Repository {
+	gitDir := repo.GitDir
+	if gitDir == "" {
+		gitDir = filepath.Join(repo.Path, ".git")
+	}
+	wtDir := filepath.Join(gitDir, "worktrees")
 	entries, err := os.ReadDir(wtDir)
 	if err != nil {
 		return nil
@@ -71,17 +98,100 @@ func discoverWorktrees(repoPath string) []model.Repository {
 			continue
 		}
 		wtPath := strings.TrimSpace(string(content))
+		if !filepath.IsAbs(wtPath) {
+			wtPath = filepath.Join(wtDir, e.Name(), wtPath)
+		}
 		// gitdir file points to the worktree's .git file location;
 		// the working directory is its parent
-		wtPath = filepath.Dir(wtPath)
+		wtPath = filepath.Dir(filepath.Clean(wtPath))
 		if info, err := os.Stat(wtPath); err != nil || !info.IsDir() {
 			continue
 		}
+		if optedOut(wtPath, "") {
+			continue
+		}
 		repos = append(repos, model.Repository{
 			Path:         wtPath,
+			Kind:         model.KindWorktree,
+			GitDir:       filepath.Join(wtDir, e.Name()),
 			IsWorktree:   true,
-			MainWorktree: repoPath,
+			MainWorktree: repo.Path,
 		})
 	}
 	return repos
 }
+
+// discoverSubmodules finds the initialized submodules listed in repo's
+// .gitmodules, recursing into nested submodules. Submodules that were never
+// checked out have no .git entry and are left out.
+func discoverSubmodules(repo *model.Repository) []model.Repository {
+	var repos []model.Repository
+	for _, rel := range parseGitmodules(filepath.Join(repo.Path, ".gitmodules")) {
+		sub, err := detectGitDir(filepath.Join(repo.Path, rel))
+		if err != nil || sub == nil || sub.IsWorktree || sub.IsBare() {
+			continue
+		}
+		// Submodules with an embedded .git directory (cloned before git
+		// absorbed them into modules/) are only known as such from here
+		sub.Kind = model.KindSubmodule
+		sub.Superproject = repo.Path
+		repos = append(repos, *sub)
+		repos = append(repos, discoverSubmodules(sub)...)
+	}
+	return repos
+}
+
+// parseGitmodules returns the submodule paths recorded in a .gitmodules
+// file, in file order.
+func parseGitmodules(file string) []string {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = f.Close() }()
+
+	var paths []string
+	inSubmodule := false
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' {
+			inSubmodule = strings.HasPrefix(line, "[submodule ")
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if !inSubmodule || !ok || strings.TrimSpace(k) != "path" {
+			continue
+		}
+		p := strings.Trim(strings.TrimSpace(v), `"`)
+		// Paths are relative to the superproject; refuse anything escaping it
+		if !filepath.IsLocal(p) {
+			continue
+		}
+		paths = append(paths, filepath.FromSlash(p))
+	}
+	return paths
+}
+
+// linkWorktrees points worktrees at the path of their main repo when they
+// were detected through a separated git dir (git init --separate-git-dir),
+// whose owner can only be known once the main repo has been found.
+func linkWorktrees(repos []model.Repository) {
+	byGitDir := make(map[string]string)
+	for _, r := range repos {
+		if !r.IsWorktree && r.GitDir != "" {
+			byGitDir[filepath.Clean(r.GitDir)] = r.Path
+		}
+	}
+	for i := range repos {
+		if !repos[i].IsWorktree {
+			continue
+		}
+		if main, ok := byGitDir[filepath.Clean(repos[i].MainWorktree)]; ok {
+			repos[i].MainWorktree = main
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/jackchuka/gv/internal/model"
 )
 
 func TestDetectGitDir_NormalRepo(t *testing.T) {
@@ -93,7 +95,7 @@ func TestDiscoverWorktrees(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	repos := discoverWorktrees(mainRepo)
+	repos := discoverWorktrees(&model.Repository{Path: mainRepo})
 	if len(repos) != 1 {
 		t.Fatalf("got %d worktrees, want 1", len(repos))
 	}
@@ -113,7 +115,7 @@ func TestDiscoverWorktrees_NoWorktrees(t *testing.T) {
 	if err := os.MkdirAll(filepath.Join(tmpDir, ".git"), 0755); err != nil {
 		t.Fatal(err)
 	}
-	repos := discoverWorktrees(tmpDir)
+	repos := discoverWorktrees(&model.Repository{Path: tmpDir})
 	if len(repos) != 0 {
 		t.Errorf("got %d worktrees, want 0", len(repos))
 	}
@@ -131,3 +133,66 @@ func TestDetectGitDir_NotARepo(t *testing.T) {
 		t.Error("detectGitDir() should return nil for non-repo")
 	}
 }
+
+func TestFindRepo(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo := filepath.Join(tmpDir, "project")
+	nested := filepath.Join(repo, "src", "pkg")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("from nested directory", func(t *testing.T) {
+		got, err := FindRepo(nested)
+		if err != nil {
+			t.Fatalf("FindRepo() error = %v", err)
+		}
+		if got == nil || got.Path != repo {
+			t.Errorf("FindRepo() = %+v, want repo at %q", got, repo)
+		}
+	})
+
+	t.Run("outside any repo", func(t *testing.T) {
+		got, err := FindRepo(tmpDir)
+		if err != nil {
+			t.Fatalf("FindRepo() error = %v", err)
+		}
+		if got != nil && got.Path == repo {
+			t.Errorf("FindRepo(%q) should not find the nested repo", tmpDir)
+		}
+	})
+}
+
+func TestParseGitmodules(t *testing.T) {
+	file := filepath.Join(t.TempDir(), ".gitmodules")
+	mustWrite(t, file, `[submodule "core"]
+	path = libs/core
+	url = https://example.com/core.git
+# comment
+[submodule "docs"]
+	url = https://example.com/docs.git
+	path = "docs"
+[remote "origin"]
+	path = not-a-submodule
+[submodule "evil"]
+	path = ../outside
+`)
+
+	got := parseGitmodules(file)
+	want := []string{filepath.Join("libs", "core"), "docs"}
+	if len(got) != len(want) {
+		t.Fatalf("parseGitmodules() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("parseGitmodules()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	if got := parseGitmodules(filepath.Join(t.TempDir(), ".gitmodules")); got != nil {
+		t.Errorf("missing file: got %v, want nil", got)
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package scanner
+
+import "io/fs"
+
+// identify falls back to the canonical path where device and inode numbers
+// are not available. Every directory then reports device 0, so filesystem
+// boundaries are not detected.
+func identify(path string, _ fs.FileInfo) dirID {
+	return dirID{path: canonical(path)}
+}
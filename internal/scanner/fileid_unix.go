@@ -0,0 +1,17 @@
+//go:build unix
+
+package scanner
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// identify returns the device and inode of the directory described by info.
+func identify(path string, info fs.FileInfo) dirID {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		// Dev and Ino widths vary by platform
+		return dirID{dev: uint64(st.Dev), ino: uint64(st.Ino)}
+	}
+	return dirID{path: canonical(path)}
+}
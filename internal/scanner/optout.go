@@ -0,0 +1,49 @@
+// internal/scanner/optout.go
+package scanner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackchuka/gv/internal/gitdir"
+	"github.com/jackchuka/gv/internal/ignore"
+)
+
+const (
+	// IgnoreFile lists ignore patterns scoped to the directory holding it.
+	IgnoreFile = ".gvignore"
+	// SkipMarker in a repo's working tree hides the repo from gv.
+	SkipMarker = ".gvskip"
+)
+
+// ErrOptedOut is returned by detectGitDir for repos that opted out with a
+// SkipMarker file or gv.ignore=true in their git config.
+var ErrOptedOut = errors.New("repo opted out of gv")
+
+// optedOut reports whether the repo at path, whose git config lives in
+// configDir, asked to be left out of scans.
+func optedOut(path, configDir string) bool {
+	if _, err := os.Stat(filepath.Join(path, SkipMarker)); err == nil {
+		return true
+	}
+	return configDir != "" && gitdir.ConfigBool(filepath.Join(configDir, "config"), "gv", "ignore")
+}
+
+// scopedIgnore is a .gvignore matcher and the directory it applies to.
+type scopedIgnore struct {
+	dir     string
+	matcher *ignore.Matcher
+}
+
+// loadIgnoreFile reads dir/.gvignore. It returns nil if there is none.
+func loadIgnoreFile(dir string) *scopedIgnore {
+	data, err := os.ReadFile(filepath.Join(dir, IgnoreFile))
+	if err != nil {
+		return nil
+	}
+	// Invalid lines are skipped; the rest of the file still applies
+	m, _ := ignore.New(strings.Split(string(data), "\n"))
+	return &scopedIgnore{dir: dir, matcher: m}
+}
@@ -0,0 +1,100 @@
+// internal/scanner/optout_test.go
+package scanner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackchuka/gv/internal/config"
+)
+
+func TestDetectGitDir_OptedOut(t *testing.T) {
+	t.Run("marker file", func(t *testing.T) {
+		dir := t.TempDir()
+		mustMkdir(t, filepath.Join(dir, ".git"))
+		mustWrite(t, filepath.Join(dir, SkipMarker), "")
+
+		if _, err := detectGitDir(dir); !errors.Is(err, ErrOptedOut) {
+			t.Errorf("detectGitDir() error = %v, want ErrOptedOut", err)
+		}
+	})
+
+	t.Run("git config", func(t *testing.T) {
+		dir := t.TempDir()
+		mustMkdir(t, filepath.Join(dir, ".git"))
+		mustWrite(t, filepath.Join(dir, ".git", "config"), "[gv]\n\tignore = true\n")
+
+		if _, err := detectGitDir(dir); !errors.Is(err, ErrOptedOut) {
+			t.Errorf("detectGitDir() error = %v, want ErrOptedOut", err)
+		}
+		if repo, err := FindRepo(dir); repo != nil || err != nil {
+			t.Errorf("FindRepo() = %v, %v; want nil, nil", repo, err)
+		}
+	})
+
+	t.Run("worktree of opted-out repo", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		mainRepo := filepath.Join(tmpDir, "main")
+		mustMkdir(t, filepath.Join(mainRepo, ".git", "worktrees", "wt"))
+		mustWrite(t, filepath.Join(mainRepo, ".git", "config"), "[gv]\n\tignore = true\n")
+		wt := filepath.Join(tmpDir, "wt")
+		mustMkdir(t, wt)
+		mustWrite(t, filepath.Join(wt, ".git"), "gitdir: "+filepath.Join(mainRepo, ".git", "worktrees", "wt")+"\n")
+
+		if _, err := detectGitDir(wt); !errors.Is(err, ErrOptedOut) {
+			t.Errorf("detectGitDir() error = %v, want ErrOptedOut", err)
+		}
+	})
+}
+
+func TestWalker_HonorsOptOutAndGvignore(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, p := range []string{
+		"team/svc-a", "team/svc-b", "team/legacy/old", "team/generated-x",
+		"other/legacy", "skipped", "configured",
+	} {
+		mustMkdir(t, filepath.Join(tmpDir, p, ".git"))
+	}
+	// Scoped to team/, so other/legacy is unaffected
+	mustWrite(t, filepath.Join(tmpDir, "team", IgnoreFile), "# shipped with the monorepo\nlegacy/\ngenerated-*\n")
+	mustWrite(t, filepath.Join(tmpDir, "skipped", SkipMarker), "")
+	mustWrite(t, filepath.Join(tmpDir, "configured", ".git", "config"), "[gv]\n\tignore = true\n")
+
+	repos, err := NewWalker(config.NewConfig()).ScanPath(context.Background(), tmpDir, 10)
+	if err != nil {
+		t.Fatalf("ScanPath() error = %v", err)
+	}
+
+	got := make(map[string]bool)
+	for _, r := range repos {
+		rel, _ := filepath.Rel(tmpDir, r.Path)
+		got[filepath.ToSlash(rel)] = true
+	}
+	want := []string{"team/svc-a", "team/svc-b", "other/legacy"}
+	if len(got) != len(want) {
+		t.Errorf("found %v, want %v", got, want)
+	}
+	for _, w := range want {
+		if !got[w] {
+			t.Errorf("repo %s not found", w)
+		}
+	}
+}
+
+func mustMkdir(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustWrite(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
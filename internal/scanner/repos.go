@@ -0,0 +1,67 @@
+// internal/scanner/repos.go
+package scanner
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackchuka/gv/internal/config"
+	"github.com/jackchuka/gv/internal/model"
+)
+
+// repoConfigs indexes the repos section of the config by canonical path.
+func repoConfigs(cfg *config.Config) map[string]config.RepoConfig {
+	byPath := make(map[string]config.RepoConfig, len(cfg.Repos))
+	for _, rc := range cfg.Repos {
+		byPath[canonical(rc.Path)] = rc
+	}
+	return byPath
+}
+
+// applyRepoConfig copies the settings of rc onto r.
+func applyRepoConfig(r *model.Repository, rc config.RepoConfig) {
+	if rc.Name != "" {
+		r.Name = rc.Name
+	}
+	r.Group = rc.Group
+	r.Tags = rc.Tags
+	r.Pinned = rc.Pinned
+	r.PollInterval = rc.PollInterval
+	r.FetchDisabled = rc.FetchDisabled
+	r.DefaultRemote = rc.Remote
+}
+
+// scanListed emits the repos listed in the config, with the worktrees and
+// submodules they host, whether or not they lie under a scan path.
+func (w *Walker) scanListed(res *ScanResult, emit func(model.Repository)) {
+	for _, rc := range w.cfg.Repos {
+		repo, err := detectGitDir(rc.Path)
+		switch {
+		case errors.Is(err, ErrOptedOut):
+			res.Skipped = append(res.Skipped, SkippedPath{Path: rc.Path, Reason: SkipOptOut})
+			continue
+		case err != nil:
+			res.Errors = append(res.Errors, newScanError(rc.Path, err))
+			continue
+		case repo == nil:
+			res.Errors = append(res.Errors, ScanError{
+				Path:    rc.Path,
+				Kind:    ErrNotRepo,
+				Message: fmt.Sprintf("%s is not a git repository", rc.Path),
+			})
+			continue
+		}
+
+		emit(*repo)
+		if !repo.IsWorktree {
+			for _, wt := range discoverWorktrees(repo) {
+				emit(wt)
+			}
+		}
+		if w.cfg.Submodules && !repo.IsBare() {
+			for _, sub := range discoverSubmodules(repo) {
+				emit(sub)
+			}
+		}
+	}
+}
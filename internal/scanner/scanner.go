@@ -3,6 +3,9 @@ package scanner
 
 import (
 	"context"
+	"errors"
+	"io/fs"
+	"time"
 
 	"github.com/jackchuka/gv/internal/model"
 )
@@ -10,15 +13,79 @@ import (
 type Scanner interface {
 	Scan(ctx context.Context) ([]model.Repository, error)
 	ScanPath(ctx context.Context, path string, maxDepth int) ([]model.Repository, error)
+
+	// ScanStream sends each repo to found as soon as it is discovered and
+	// returns the complete result once the scan is over. found may be nil;
+	// it is not closed. Repos in the result may differ from the streamed
+	// ones where discovery needed the whole picture (worktree owners).
+	ScanStream(ctx context.Context, found chan<- model.Repository) (*ScanResult, error)
 }
 
+// ScanResult reports what a scan found and what it could not look at.
 type ScanResult struct {
-	Repos    []model.Repository
-	Errors   []ScanError
-	Duration int64 // milliseconds
+	Repos    []model.Repository `json:"repos,omitempty"`
+	Dirs     int                `json:"dirs"`    // directories visited
+	Errors   []ScanError        `json:"errors"`  // directories that could not be read
+	Skipped  []SkippedPath      `json:"skipped"` // directories deliberately not descended into
+	Duration time.Duration      `json:"duration"`
 }
 
+// ErrorKind classifies a ScanError.
+type ErrorKind string
+
+const (
+	ErrPermission ErrorKind = "permission" // directory not readable
+	ErrNotFound   ErrorKind = "not_found"  // scan path missing
+	ErrTimeout    ErrorKind = "timeout"    // scan cancelled before the walk finished
+	ErrNotRepo    ErrorKind = "not_repo"   // listed in repos but not a repository
+	ErrOther      ErrorKind = "error"
+)
+
 type ScanError struct {
-	Path  string
-	Error error
+	Path    string    `json:"path"`
+	Kind    ErrorKind `json:"kind"`
+	Message string    `json:"message"`
+}
+
+func newScanError(path string, err error) ScanError {
+	kind := ErrOther
+	switch {
+	case errors.Is(err, fs.ErrPermission):
+		kind = ErrPermission
+	case errors.Is(err, fs.ErrNotExist):
+		kind = ErrNotFound
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		kind = ErrTimeout
+	}
+	return ScanError{Path: path, Kind: kind, Message: err.Error()}
+}
+
+// SkipReason says why a directory was not descended into.
+type SkipReason string
+
+const (
+	SkipIgnored  SkipReason = "ignored"   // matched ignore_patterns
+	SkipGvignore SkipReason = "gvignore"  // matched a .gvignore file
+	SkipOptOut   SkipReason = "opted_out" // repo with .gvskip or gv.ignore=true
+	SkipDepth    SkipReason = "max_depth" // deeper than max_depth
+	SkipSymlink  SkipReason = "symlink"   // symlinked directory while follow_symlinks is off
+	SkipLoop     SkipReason = "loop"      // directory already scanned through another path
+	SkipMount    SkipReason = "mount"     // other filesystem while one_filesystem is on
+)
+
+// SkipReasons lists every SkipReason in display order.
+var SkipReasons = []SkipReason{SkipIgnored, SkipGvignore, SkipOptOut, SkipDepth, SkipSymlink, SkipLoop, SkipMount}
+
+type SkippedPath struct {
+	Path   string     `json:"path"`
+	Reason SkipReason `json:"reason"`
+}
+
+// SkipCounts tallies Skipped by reason.
+func (r *ScanResult) SkipCounts() map[SkipReason]int {
+	counts := make(map[SkipReason]int)
+	for _, s := range r.Skipped {
+		counts[s.Reason]++
+	}
+	return counts
 }
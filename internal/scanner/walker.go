@@ -3,11 +3,15 @@ package scanner
 
 import (
 	"context"
+	"errors"
 	"io/fs"
+	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/jackchuka/gv/internal/config"
+	"github.com/jackchuka/gv/internal/ignore"
 	"github.com/jackchuka/gv/internal/model"
 )
 
@@ -20,98 +24,320 @@ func NewWalker(cfg *config.Config) *Walker {
 }
 
 func (w *Walker) Scan(ctx context.Context) ([]model.Repository, error) {
+	res, err := w.ScanStream(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return res.Repos, nil
+}
+
+func (w *Walker) ScanStream(ctx context.Context, found chan<- model.Repository) (*ScanResult, error) {
 	var (
-		mu   sync.Mutex
-		wg   sync.WaitGroup
-		seen = make(map[string]model.Repository)
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		seen  = make(map[string]model.Repository) // by canonical path
+		res   = &ScanResult{}
+		start = time.Now()
 	)
 
+	overrides := repoConfigs(w.cfg)
+
+	// Deduplicate by canonical path - scan paths may overlap, and symlinks
+	// can lead to the same repo twice
+	emit := func(r model.Repository) {
+		key := canonical(r.Path)
+		if rc, ok := overrides[key]; ok {
+			applyRepoConfig(&r, rc)
+		}
+		mu.Lock()
+		prev, exists := seen[key]
+		if !exists || preferPath(key, r.Path, prev.Path) {
+			seen[key] = r
+		}
+		mu.Unlock()
+		if exists || found == nil {
+			return
+		}
+		select {
+		case found <- r:
+		case <-ctx.Done():
+		}
+	}
+
 	for _, scanPath := range w.cfg.ScanPaths {
 		wg.Add(1)
 		go func(path string) {
 			defer wg.Done()
 
-			found, err := w.ScanPath(ctx, path, w.cfg.MaxDepth)
-			if err != nil {
-				return
+			var part ScanResult
+			if err := w.walk(ctx, path, w.cfg.MaxDepth, &part, emit); err != nil {
+				part.Errors = append(part.Errors, newScanError(path, err))
 			}
 
 			mu.Lock()
-			for _, r := range found {
-				// Deduplicate by path - scan paths may overlap
-				if _, exists := seen[r.Path]; !exists {
-					seen[r.Path] = r
-				}
-			}
+			res.Dirs += part.Dirs
+			res.Errors = append(res.Errors, part.Errors...)
+			res.Skipped = append(res.Skipped, part.Skipped...)
 			mu.Unlock()
 		}(scanPath)
 	}
 
+	if len(w.cfg.Repos) > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var part ScanResult
+			w.scanListed(&part, emit)
+
+			mu.Lock()
+			res.Errors = append(res.Errors, part.Errors...)
+			res.Skipped = append(res.Skipped, part.Skipped...)
+			mu.Unlock()
+		}()
+	}
+
 	wg.Wait()
 
-	repos := make([]model.Repository, 0, len(seen))
+	res.Repos = make([]model.Repository, 0, len(seen))
 	for _, r := range seen {
-		repos = append(repos, r)
+		res.Repos = append(res.Repos, r)
 	}
-	return repos, nil
+	linkWorktrees(res.Repos)
+	relinkParents(res.Repos, seen)
+	res.Duration = time.Since(start)
+	return res, nil
 }
 
-func (w *Walker) ScanPath(ctx context.Context, root string, maxDepth int) ([]model.Repository, error) {
-	var repos []model.Repository
+// canonical returns path with symlinks resolved, or path itself when it
+// cannot be resolved.
+func canonical(path string) string {
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		return real
+	}
+	return filepath.Clean(path)
+}
 
-	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return nil // Skip directories we can't read
-		}
+// preferPath reports whether candidate is a better path than current for
+// the repo at canonical: the real path first, then the shortest, then the
+// lexically smallest, so overlapping scans agree whatever their order.
+func preferPath(canonical, candidate, current string) bool {
+	if (candidate == canonical) != (current == canonical) {
+		return candidate == canonical
+	}
+	if len(candidate) != len(current) {
+		return len(candidate) < len(current)
+	}
+	return candidate < current
+}
 
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
+// relinkParents points MainWorktree and Superproject at the path chosen
+// for the parent repo, which may differ from the one git recorded when
+// the parent was reached through a symlink.
+func relinkParents(repos []model.Repository, byCanonical map[string]model.Repository) {
+	resolve := func(p string) string {
+		if p == "" {
+			return p
 		}
+		if r, ok := byCanonical[canonical(p)]; ok {
+			return r.Path
+		}
+		return p
+	}
+	for i := range repos {
+		repos[i].MainWorktree = resolve(repos[i].MainWorktree)
+		repos[i].Superproject = resolve(repos[i].Superproject)
+	}
+}
+
+func (w *Walker) ScanPath(ctx context.Context, root string, maxDepth int) ([]model.Repository, error) {
+	var (
+		res   ScanResult
+		repos []model.Repository
+	)
+	err := w.walk(ctx, root, maxDepth, &res, func(r model.Repository) {
+		repos = append(repos, r)
+	})
+	return repos, err
+}
+
+// walk finds the repos below root, passing each to emit, and records
+// visited, unreadable and skipped directories in res. It returns an error
+// only when the walk had to stop early.
+func (w *Walker) walk(ctx context.Context, root string, maxDepth int, res *ScanResult, emit func(model.Repository)) error {
+	// The root is followed even when it is a symlink: it was named explicitly
+	info, err := os.Stat(root)
+	if err != nil {
+		res.Errors = append(res.Errors, newScanError(root, err))
+		return nil
+	}
+	if !info.IsDir() {
+		return nil
+	}
+
+	wk := &walk{
+		ctx:      ctx,
+		cfg:      w.cfg,
+		root:     root,
+		maxDepth: maxDepth,
+		ignored:  w.cfg.IgnoreMatcher(),
+		res:      res,
+		emit:     emit,
+	}
+	if w.cfg.FollowSymlinks || w.cfg.OneFilesystem {
+		wk.visited = make(map[dirID]bool)
+		wk.onPath = make(map[dirID]bool)
+		wk.rootDev = identify(root, info).dev
+	}
+	return wk.dir(root, info, 0, false, nil)
+}
+
+// dirID identifies a directory independently of the path it was reached
+// through.
+type dirID struct {
+	dev, ino uint64
+	path     string // canonical path where device and inode are unavailable
+}
+
+// walk is the state of a walk below one scan root.
+type walk struct {
+	ctx      context.Context
+	cfg      *config.Config
+	root     string
+	maxDepth int
+	ignored  *ignore.Matcher
+	res      *ScanResult
+	emit     func(model.Repository)
+
+	// Directories entered so far and those between the root and the
+	// current one, tracked when following symlinks or staying on one
+	// filesystem; nil otherwise to spare a stat per entry.
+	visited map[dirID]bool
+	onPath  map[dirID]bool
+	rootDev uint64
+}
 
-		if !d.IsDir() {
+func (wk *walk) skip(path string, reason SkipReason) {
+	wk.res.Skipped = append(wk.res.Skipped, SkippedPath{Path: path, Reason: reason})
+}
+
+// dir visits path, depth levels below the root, reached through a symlink
+// if viaLink. scoped holds the .gvignore files of its ancestors.
+func (wk *walk) dir(path string, info fs.FileInfo, depth int, viaLink bool, scoped []*scopedIgnore) error {
+	select {
+	case <-wk.ctx.Done():
+		return wk.ctx.Err()
+	default:
+	}
+
+	if path != wk.root {
+		if filepath.Base(path) == ".git" {
 			return nil
 		}
-
-		relPath, _ := filepath.Rel(root, path)
-		depth := 0
-		if relPath != "." {
-			for _, c := range relPath {
-				if c == filepath.Separator {
-					depth++
-				}
+		if depth > wk.maxDepth {
+			wk.skip(path, SkipDepth)
+			return nil
+		}
+		if wk.ignored.Match(wk.root, path) {
+			wk.skip(path, SkipIgnored)
+			return nil
+		}
+		for _, s := range scoped {
+			if s.matcher.Match(s.dir, path) {
+				wk.skip(path, SkipGvignore)
+				return nil
 			}
-			depth++ // Add 1 for the final component
 		}
+	}
 
-		if depth > maxDepth {
-			return fs.SkipDir
+	if wk.visited != nil {
+		id := identify(path, info)
+		if wk.cfg.OneFilesystem && id.dev != wk.rootDev {
+			wk.skip(path, SkipMount)
+			return nil
 		}
-
-		if d.Name() == ".git" {
-			return fs.SkipDir
+		// A link back to an ancestor would recurse forever; one to a
+		// directory seen before only repeats work. Real directories are
+		// always entered so repos keep their real paths when possible.
+		if wk.onPath[id] || (viaLink && wk.visited[id]) {
+			wk.skip(path, SkipLoop)
+			return nil
 		}
+		wk.visited[id] = true
+		wk.onPath[id] = true
+		defer delete(wk.onPath, id)
+	}
 
-		if w.cfg.ShouldIgnore(path) && path != root {
-			return fs.SkipDir
+	wk.res.Dirs++
+	repo, err := detectGitDir(path)
+	if errors.Is(err, ErrOptedOut) {
+		wk.skip(path, SkipOptOut)
+		return nil
+	}
+	if err != nil {
+		wk.res.Errors = append(wk.res.Errors, newScanError(path, err))
+		if errors.Is(err, fs.ErrPermission) {
+			return nil // Reading its entries would fail the same way
 		}
+		// Continue on other errors
+	}
 
-		repo, err := detectGitDir(path)
-		if err != nil {
-			return nil // Continue on errors
+	if repo != nil {
+		wk.emit(*repo)
+		if !repo.IsWorktree {
+			for _, wt := range discoverWorktrees(repo) {
+				wk.emit(wt)
+			}
 		}
-
-		if repo != nil {
-			repos = append(repos, *repo)
-			if !repo.IsWorktree {
-				repos = append(repos, discoverWorktrees(path)...)
+		if wk.cfg.Submodules && !repo.IsBare() {
+			for _, sub := range discoverSubmodules(repo) {
+				if wk.ignored.Match(wk.root, sub.Path) {
+					wk.skip(sub.Path, SkipIgnored)
+					continue
+				}
+				wk.emit(sub)
 			}
-			return fs.SkipDir // Don't descend into git repos
 		}
+		return nil // Don't descend into git repos
+	}
 
-		return nil
-	})
+	if s := loadIgnoreFile(path); s != nil {
+		scoped = append(scoped[:len(scoped):len(scoped)], s)
+	}
 
-	return repos, err
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		// Skip directories we can't read, but say so
+		wk.res.Errors = append(wk.res.Errors, newScanError(path, err))
+	}
+	for _, e := range entries {
+		child := filepath.Join(path, e.Name())
+		var (
+			ci   fs.FileInfo
+			link bool
+		)
+		switch {
+		case e.IsDir():
+			if wk.visited != nil {
+				if ci, err = e.Info(); err != nil {
+					continue // Removed since it was listed
+				}
+			}
+		case e.Type()&fs.ModeSymlink != 0:
+			if ci, err = os.Stat(child); err != nil || !ci.IsDir() {
+				continue // Dangling or not a directory
+			}
+			if !wk.cfg.FollowSymlinks {
+				wk.skip(child, SkipSymlink)
+				continue
+			}
+			link = true
+		default:
+			continue
+		}
+		if err := wk.dir(child, ci, depth+1, link, scoped); err != nil {
+			return err
+		}
+	}
+	return nil
 }
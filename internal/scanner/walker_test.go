@@ -4,10 +4,13 @@ package scanner
 import (
 	"context"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/jackchuka/gv/internal/config"
+	"github.com/jackchuka/gv/internal/model"
 )
 
 func TestWalker_FindsRepos(t *testing.T) {
@@ -93,3 +96,413 @@ func TestWalker_SkipsIgnoredDirs(t *testing.T) {
 		t.Errorf("Found repo at %q, want %q", repos[0].Path, normal)
 	}
 }
+
+func TestWalker_IgnorePatternsRelativeToRoot(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, p := range []string{"archive/old", "archive/keep", "active/archive/new", "tmp-1/x", "live"} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, p, ".git"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.NewConfig()
+	cfg.ScanPaths = []string{tmpDir}
+	cfg.IgnorePatterns = []string{
+		"archive/**", // anchored to the scan root
+		"!archive/keep",
+		"**/tmp-*/**",
+	}
+
+	repos, err := NewWalker(cfg).Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	got := make(map[string]bool)
+	for _, r := range repos {
+		rel, _ := filepath.Rel(tmpDir, r.Path)
+		got[filepath.ToSlash(rel)] = true
+	}
+	want := []string{"archive/keep", "active/archive/new", "live"}
+	if len(got) != len(want) {
+		t.Errorf("found %v, want %v", got, want)
+	}
+	for _, w := range want {
+		if !got[w] {
+			t.Errorf("repo %s not found", w)
+		}
+	}
+}
+
+func TestWalker_BareAndSeparateGitDir(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+	tmpDir := t.TempDir()
+	root := filepath.Join(tmpDir, "code")
+	if err := os.MkdirAll(filepath.Join(tmpDir, "store"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Separate git dir outside the scan root, with a worktree inside it
+	app := filepath.Join(root, "app")
+	gitCmd(t, tmpDir, "init", "-q", "--separate-git-dir", filepath.Join(tmpDir, "store", "app.git"), app)
+	gitCmd(t, app, "commit", "-q", "--allow-empty", "-m", "initial")
+	gitCmd(t, app, "worktree", "add", "-q", filepath.Join(root, "app-feature"))
+
+	// Bare repo whose worktree lives elsewhere in the scan root
+	bare := filepath.Join(root, "lib.git")
+	gitCmd(t, tmpDir, "clone", "-q", "--bare", app, bare)
+	gitCmd(t, bare, "worktree", "add", "-q", filepath.Join(root, "work", "lib-main"), "HEAD")
+
+	cfg := config.NewConfig()
+	cfg.ScanPaths = []string{root}
+	repos, err := NewWalker(cfg).Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	byName := make(map[string]model.Repository)
+	for _, r := range repos {
+		byName[filepath.Base(r.Path)] = r
+	}
+	if len(byName) != 4 {
+		t.Fatalf("found %v, want app, app-feature, lib.git and lib-main", byName)
+	}
+
+	tests := []struct {
+		name string
+		kind model.RepoKind
+		main string
+	}{
+		{"app", model.KindNormal, ""},
+		{"app-feature", model.KindWorktree, app},
+		{"lib.git", model.KindBare, ""},
+		{"lib-main", model.KindWorktree, bare},
+	}
+	for _, tt := range tests {
+		r := byName[tt.name]
+		if r.Kind != tt.kind {
+			t.Errorf("%s: Kind = %q, want %q", tt.name, r.Kind, tt.kind)
+		}
+		if r.IsWorktree != (tt.kind == model.KindWorktree) {
+			t.Errorf("%s: IsWorktree = %v", tt.name, r.IsWorktree)
+		}
+		if r.MainWorktree != tt.main {
+			t.Errorf("%s: MainWorktree = %q, want %q", tt.name, r.MainWorktree, tt.main)
+		}
+	}
+}
+
+func TestWalker_Submodules(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+	tmpDir := t.TempDir()
+	root := filepath.Join(tmpDir, "code")
+
+	// Upstreams live outside the scan root
+	for _, name := range []string{"core", "util", "docs"} {
+		gitCmd(t, tmpDir, "init", "-q", filepath.Join(tmpDir, "up", name))
+		gitCmd(t, filepath.Join(tmpDir, "up", name), "commit", "-q", "--allow-empty", "-m", "initial")
+	}
+	core := filepath.Join(tmpDir, "up", "core")
+	gitCmd(t, core, "-c", "protocol.file.allow=always", "submodule", "add", "-q", filepath.Join(tmpDir, "up", "util"), "util")
+	gitCmd(t, core, "commit", "-q", "-m", "add util")
+
+	// app has core (with a nested util) checked out and docs uninitialized
+	app := filepath.Join(root, "app")
+	gitCmd(t, tmpDir, "init", "-q", app)
+	gitCmd(t, app, "commit", "-q", "--allow-empty", "-m", "initial")
+	gitCmd(t, app, "-c", "protocol.file.allow=always", "submodule", "add", "-q", core, "libs/core")
+	gitCmd(t, app, "-c", "protocol.file.allow=always", "submodule", "update", "-q", "--init", "--recursive")
+	gitCmd(t, app, "-c", "protocol.file.allow=always", "submodule", "add", "-q", filepath.Join(tmpDir, "up", "docs"), "docs")
+	gitCmd(t, app, "commit", "-q", "-m", "add submodules")
+	gitCmd(t, app, "submodule", "deinit", "-q", "docs")
+
+	cfg := config.NewConfig()
+	cfg.ScanPaths = []string{root}
+	repos, err := NewWalker(cfg).Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	byPath := make(map[string]model.Repository)
+	for _, r := range repos {
+		rel, _ := filepath.Rel(root, r.Path)
+		byPath[filepath.ToSlash(rel)] = r
+	}
+	if len(byPath) != 3 {
+		t.Fatalf("found %v, want app, app/libs/core and app/libs/core/util", byPath)
+	}
+
+	tests := []struct {
+		path  string
+		kind  model.RepoKind
+		super string
+	}{
+		{"app", model.KindNormal, ""},
+		{"app/libs/core", model.KindSubmodule, app},
+		{"app/libs/core/util", model.KindSubmodule, filepath.Join(app, "libs", "core")},
+	}
+	for _, tt := range tests {
+		r := byPath[tt.path]
+		if r.Kind != tt.kind {
+			t.Errorf("%s: Kind = %q, want %q", tt.path, r.Kind, tt.kind)
+		}
+		if r.Superproject != tt.super {
+			t.Errorf("%s: Superproject = %q, want %q", tt.path, r.Superproject, tt.super)
+		}
+	}
+
+	// Found directly, a submodule still knows its superproject
+	sub, err := detectGitDir(filepath.Join(app, "libs", "core"))
+	if err != nil || sub == nil {
+		t.Fatalf("detectGitDir() = %v, %v", sub, err)
+	}
+	if sub.Kind != model.KindSubmodule || sub.Superproject != app {
+		t.Errorf("detectGitDir() = %+v, want submodule of %s", sub, app)
+	}
+
+	cfg.Submodules = false
+	repos, err = NewWalker(cfg).Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(repos) != 1 {
+		t.Errorf("with submodules disabled found %d repos, want 1", len(repos))
+	}
+}
+
+func gitCmd(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@test.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@test.com")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+func TestWalker_ScanStream(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, dir := range []string{"app/.git", "lib/.git", "deep/a/b/.git", "web/node_modules/pkg/.git", "old/.git"} {
+		mustMkdir(t, filepath.Join(tmpDir, dir))
+	}
+	mustWrite(t, filepath.Join(tmpDir, "old", SkipMarker), "")
+
+	cfg := config.NewConfig()
+	cfg.MaxDepth = 2
+	// Overlapping scan paths must not stream a repo twice
+	cfg.ScanPaths = []string{tmpDir, filepath.Join(tmpDir, "app"), filepath.Join(tmpDir, "missing")}
+
+	found := make(chan model.Repository, 10)
+	res, err := NewWalker(cfg).ScanStream(context.Background(), found)
+	if err != nil {
+		t.Fatalf("ScanStream() error = %v", err)
+	}
+	close(found)
+
+	streamed := make(map[string]int)
+	for r := range found {
+		rel, _ := filepath.Rel(tmpDir, r.Path)
+		streamed[rel]++
+	}
+	if len(streamed) != 2 || streamed["app"] != 1 || streamed["lib"] != 1 {
+		t.Errorf("streamed %v, want app and lib once each", streamed)
+	}
+	if len(res.Repos) != 2 {
+		t.Errorf("Repos = %d, want 2", len(res.Repos))
+	}
+	if res.Dirs == 0 || res.Duration <= 0 {
+		t.Errorf("Dirs = %d, Duration = %v, want both counted", res.Dirs, res.Duration)
+	}
+
+	skipped := res.SkipCounts()
+	for _, reason := range []SkipReason{SkipDepth, SkipIgnored, SkipOptOut} {
+		if skipped[reason] == 0 {
+			t.Errorf("no %s skips recorded in %+v", reason, res.Skipped)
+		}
+	}
+
+	if len(res.Errors) != 1 || res.Errors[0].Kind != ErrNotFound {
+		t.Errorf("Errors = %+v, want one not_found error", res.Errors)
+	}
+}
+
+func TestWalker_ScanStreamReportsTimeoutAndPermission(t *testing.T) {
+	tmpDir := t.TempDir()
+	mustMkdir(t, filepath.Join(tmpDir, "app", ".git"))
+
+	cfg := config.NewConfig()
+	cfg.ScanPaths = []string{tmpDir}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res, err := NewWalker(cfg).ScanStream(ctx, nil)
+	if err != nil {
+		t.Fatalf("ScanStream() error = %v", err)
+	}
+	if len(res.Errors) != 1 || res.Errors[0].Kind != ErrTimeout {
+		t.Errorf("Errors = %+v, want one timeout", res.Errors)
+	}
+
+	if os.Geteuid() == 0 {
+		t.Skip("permissions are not enforced for root")
+	}
+	locked := filepath.Join(tmpDir, "locked")
+	mustMkdir(t, locked)
+	if err := os.Chmod(locked, 0); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(locked, 0755) })
+
+	res, err = NewWalker(cfg).ScanStream(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ScanStream() error = %v", err)
+	}
+	if len(res.Repos) != 1 {
+		t.Errorf("Repos = %d, want 1", len(res.Repos))
+	}
+	if len(res.Errors) != 1 || res.Errors[0].Kind != ErrPermission || res.Errors[0].Path != locked {
+		t.Errorf("Errors = %+v, want a permission error for %s", res.Errors, locked)
+	}
+}
+
+func TestWalker_Symlinks(t *testing.T) {
+	tmpDir := t.TempDir()
+	root := filepath.Join(tmpDir, "code")
+	data := filepath.Join(tmpDir, "data")
+	mustMkdir(t, filepath.Join(root, "local", ".git"))
+	mustMkdir(t, filepath.Join(data, "proj", ".git"))
+	mustMkdir(t, filepath.Join(root, "nested", "deep"))
+	for link, target := range map[string]string{
+		filepath.Join(root, "active"):               data,                         // repo reachable only through a link
+		filepath.Join(root, "nested", "deep", "up"): root,                         // loop back to the root
+		filepath.Join(root, "alias"):                filepath.Join(root, "local"), // second path to a scanned repo
+		filepath.Join(root, "nested", "dangling"):   filepath.Join(tmpDir, "gone"),
+	} {
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	cfg := config.NewConfig()
+	cfg.ScanPaths = []string{root}
+
+	res, err := NewWalker(cfg).ScanStream(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ScanStream() error = %v", err)
+	}
+	if len(res.Repos) != 1 {
+		t.Errorf("without follow_symlinks found %d repos, want only local", len(res.Repos))
+	}
+	if n := res.SkipCounts()[SkipSymlink]; n != 3 {
+		t.Errorf("symlink skips = %d, want 3 in %+v", n, res.Skipped)
+	}
+
+	cfg.FollowSymlinks = true
+	res, err = NewWalker(cfg).ScanStream(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ScanStream() error = %v", err)
+	}
+	got := make(map[string]bool)
+	for _, r := range res.Repos {
+		rel, _ := filepath.Rel(root, r.Path)
+		got[filepath.ToSlash(rel)] = true
+	}
+	// alias resolves to local, which wins as the real path
+	if len(got) != 2 || !got["local"] || !got["active/proj"] {
+		t.Errorf("with follow_symlinks found %v, want local and active/proj", got)
+	}
+	if res.SkipCounts()[SkipLoop] == 0 {
+		t.Errorf("loop back to the root not reported in %+v", res.Skipped)
+	}
+	if len(res.Errors) != 0 {
+		t.Errorf("Errors = %+v, want none", res.Errors)
+	}
+
+	// Staying on one filesystem changes nothing within a temp dir
+	cfg.OneFilesystem = true
+	res, err = NewWalker(cfg).ScanStream(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ScanStream() error = %v", err)
+	}
+	if len(res.Repos) != 2 || res.SkipCounts()[SkipMount] != 0 {
+		t.Errorf("one_filesystem: %d repos, skipped %+v", len(res.Repos), res.Skipped)
+	}
+}
+
+func TestWalker_DedupsOverlappingRootsByCanonicalPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	real := filepath.Join(tmpDir, "real")
+	mustMkdir(t, filepath.Join(real, "app", ".git"))
+	link := filepath.Join(tmpDir, "link")
+	if err := os.Symlink(real, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	cfg := config.NewConfig()
+	cfg.ScanPaths = []string{link, real}
+	repos, err := NewWalker(cfg).Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	want := filepath.Join(canonical(real), "app")
+	if len(repos) != 1 || repos[0].Path != want {
+		t.Errorf("Scan() = %+v, want only %s", repos, want)
+	}
+}
+
+func TestWalker_ListedRepos(t *testing.T) {
+	tmpDir := t.TempDir()
+	root := filepath.Join(tmpDir, "code")
+	mustMkdir(t, filepath.Join(root, "found", ".git"))
+	elsewhere := filepath.Join(tmpDir, "elsewhere", "tool")
+	mustMkdir(t, filepath.Join(elsewhere, ".git"))
+	notRepo := filepath.Join(tmpDir, "plain")
+	mustMkdir(t, notRepo)
+
+	cfg := config.NewConfig()
+	cfg.ScanPaths = []string{root}
+	cfg.Repos = []config.RepoConfig{
+		// Outside the scan path
+		{Path: elsewhere, Name: "my-tool", Tags: []string{"cli"}, PollInterval: time.Minute},
+		// Also discovered, by another spelling of its path
+		{Path: filepath.Join(root, ".", "found"), Group: "work", Pinned: true, FetchDisabled: true, Remote: "upstream"},
+		{Path: notRepo},
+	}
+
+	res, err := NewWalker(cfg).ScanStream(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ScanStream() error = %v", err)
+	}
+	if len(res.Repos) != 2 {
+		t.Fatalf("ScanStream() found %d repos, want 2: %+v", len(res.Repos), res.Repos)
+	}
+
+	byPath := make(map[string]model.Repository)
+	for _, r := range res.Repos {
+		byPath[canonical(r.Path)] = r
+	}
+
+	tool := byPath[canonical(elsewhere)]
+	if tool.DisplayName() != "my-tool" || len(tool.Tags) != 1 || tool.PollInterval != time.Minute {
+		t.Errorf("listed repo = %+v, want the settings from its entry", tool)
+	}
+
+	found := byPath[canonical(filepath.Join(root, "found"))]
+	if found.Group != "work" || !found.Pinned || !found.FetchDisabled || found.DefaultRemote != "upstream" {
+		t.Errorf("discovered repo = %+v, want the settings from its entry", found)
+	}
+	if found.DisplayName() != "found" {
+		t.Errorf("discovered repo DisplayName() = %q, want the directory name when none is set", found.DisplayName())
+	}
+
+	if len(res.Errors) != 1 || res.Errors[0].Kind != ErrNotRepo || res.Errors[0].Path != notRepo {
+		t.Errorf("Errors = %+v, want %s reported as not a repo", res.Errors, notRepo)
+	}
+}
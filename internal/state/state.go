@@ -0,0 +1,172 @@
+// Package state persists what the user changes from the TUI, such as
+// pinned and tagged repos and past filter queries, between sessions.
+// Settings meant to be edited by hand belong in the config instead.
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// State is everything gv remembers between sessions.
+type State struct {
+	Pinned  []string             `json:"pinned,omitempty"`  // repo paths, in the order they were pinned
+	Tags    map[string][]string  `json:"tags,omitempty"`    // repo path -> tags
+	History []string             `json:"history,omitempty"` // filter queries, oldest first
+	Used    map[string]time.Time `json:"used,omitempty"`    // repo path -> last jumped to or opened
+	Recent  []string             `json:"recent,omitempty"`  // command palette entries, oldest first
+}
+
+// MaxHistory bounds History.
+const MaxHistory = 100
+
+// MaxRecent bounds Recent.
+const MaxRecent = 20
+
+// MaxUsed bounds Used; the least recently used repos are forgotten first.
+const MaxUsed = 200
+
+func DefaultPath() string {
+	if xdg := os.Getenv("XDG_STATE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "gv", "state.json")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "state", "gv", "state.json")
+}
+
+// Load reads state from path. A missing file yields an empty state.
+func Load(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &State{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Save writes s to path atomically, so a second gv instance never reads
+// a partial file.
+func (s *State) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	// Queries use < and >; keep them readable in the file
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(s); err != nil {
+		return err
+	}
+	data := buf.Bytes()
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".state-*.json")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *State) IsPinned(path string) bool {
+	return slices.Contains(s.Pinned, path)
+}
+
+// TogglePin pins path if it is not pinned and unpins it otherwise,
+// reporting whether it is pinned now.
+func (s *State) TogglePin(path string) bool {
+	if i := slices.Index(s.Pinned, path); i >= 0 {
+		s.Pinned = slices.Delete(s.Pinned, i, i+1)
+		return false
+	}
+	s.Pinned = append(s.Pinned, path)
+	return true
+}
+
+// TagsOf returns the tags of the repo at path.
+func (s *State) TagsOf(path string) []string {
+	return s.Tags[path]
+}
+
+// SetTags replaces the tags of the repo at path. Tags are trimmed of
+// whitespace and a leading #, and duplicates are dropped ignoring case.
+func (s *State) SetTags(path string, tags []string) {
+	var clean []string
+	for _, t := range tags {
+		t = strings.TrimPrefix(strings.TrimSpace(t), "#")
+		if t == "" || slices.ContainsFunc(clean, func(c string) bool { return strings.EqualFold(c, t) }) {
+			continue
+		}
+		clean = append(clean, t)
+	}
+	if len(clean) == 0 {
+		delete(s.Tags, path)
+		return
+	}
+	if s.Tags == nil {
+		s.Tags = make(map[string][]string)
+	}
+	s.Tags[path] = clean
+}
+
+// AddHistory records query as the most recent filter query, moving it to
+// the end if it was used before.
+func (s *State) AddHistory(query string) {
+	if query == "" {
+		return
+	}
+	s.History = slices.DeleteFunc(s.History, func(q string) bool { return q == query })
+	s.History = append(s.History, query)
+	if len(s.History) > MaxHistory {
+		s.History = s.History[len(s.History)-MaxHistory:]
+	}
+}
+
+// Touch records that the repo at path was used now.
+func (s *State) Touch(path string, now time.Time) {
+	if s.Used == nil {
+		s.Used = make(map[string]time.Time)
+	}
+	s.Used[path] = now
+	for len(s.Used) > MaxUsed {
+		oldest := ""
+		for p, t := range s.Used {
+			if oldest == "" || t.Before(s.Used[oldest]) {
+				oldest = p
+			}
+		}
+		delete(s.Used, oldest)
+	}
+}
+
+// AddRecent records id as the most recently run palette entry, moving it
+// to the end if it was run before.
+func (s *State) AddRecent(id string) {
+	s.Recent = slices.DeleteFunc(s.Recent, func(r string) bool { return r == id })
+	s.Recent = append(s.Recent, id)
+	if len(s.Recent) > MaxRecent {
+		s.Recent = s.Recent[len(s.Recent)-MaxRecent:]
+	}
+}
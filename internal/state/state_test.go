@@ -0,0 +1,114 @@
+package state
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad_MissingFileIsEmpty(t *testing.T) {
+	s, err := Load(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(s.Pinned) != 0 {
+		t.Errorf("Pinned = %v, want none", s.Pinned)
+	}
+}
+
+func TestSaveAndLoad_Roundtrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "state.json")
+
+	s := &State{}
+	if !s.TogglePin("/code/a") || !s.TogglePin("/code/b") {
+		t.Fatal("TogglePin() should pin unpinned repos")
+	}
+	if s.TogglePin("/code/a") {
+		t.Fatal("TogglePin() should unpin a pinned repo")
+	}
+	if err := s.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got.IsPinned("/code/a") || !got.IsPinned("/code/b") {
+		t.Errorf("Pinned = %v, want only /code/b", got.Pinned)
+	}
+}
+
+func TestSetTags(t *testing.T) {
+	s := &State{}
+	s.SetTags("/code/a", []string{" go", "#backend", "Go", "", "team-x"})
+	if got, want := s.TagsOf("/code/a"), []string{"go", "backend", "team-x"}; !slices.Equal(got, want) {
+		t.Errorf("TagsOf() = %v, want %v", got, want)
+	}
+
+	s.SetTags("/code/a", nil)
+	if _, ok := s.Tags["/code/a"]; ok {
+		t.Error("SetTags() with no tags should drop the entry")
+	}
+}
+
+func TestAddHistory(t *testing.T) {
+	s := &State{}
+	for _, q := range []string{"dirty", "ahead>0", "", "dirty"} {
+		s.AddHistory(q)
+	}
+	if want := []string{"ahead>0", "dirty"}; !slices.Equal(s.History, want) {
+		t.Errorf("History = %v, want %v", s.History, want)
+	}
+
+	for i := range MaxHistory + 5 {
+		s.AddHistory(strings.Repeat("x", i+1))
+	}
+	if len(s.History) != MaxHistory || s.History[len(s.History)-1] != strings.Repeat("x", MaxHistory+5) {
+		t.Errorf("History kept %d entries ending %q", len(s.History), s.History[len(s.History)-1])
+	}
+}
+
+func TestAddRecent(t *testing.T) {
+	s := &State{}
+	for _, id := range []string{"action:fetch", "view:work", "action:fetch"} {
+		s.AddRecent(id)
+	}
+	if want := []string{"view:work", "action:fetch"}; !slices.Equal(s.Recent, want) {
+		t.Errorf("Recent = %v, want %v", s.Recent, want)
+	}
+
+	for i := range MaxRecent + 2 {
+		s.AddRecent(fmt.Sprintf("command:%d", i))
+	}
+	if len(s.Recent) != MaxRecent || s.Recent[0] != "command:2" {
+		t.Errorf("Recent = %v, want the last %d", s.Recent, MaxRecent)
+	}
+}
+
+func TestTouch(t *testing.T) {
+	s := &State{}
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := range MaxUsed + 3 {
+		s.Touch(fmt.Sprintf("/code/%d", i), start.Add(time.Duration(i)*time.Minute))
+	}
+	if len(s.Used) != MaxUsed {
+		t.Fatalf("Used kept %d entries, want %d", len(s.Used), MaxUsed)
+	}
+	if _, ok := s.Used["/code/0"]; ok {
+		t.Error("least recently used repo was kept")
+	}
+	if _, ok := s.Used[fmt.Sprintf("/code/%d", MaxUsed+2)]; !ok {
+		t.Error("most recently used repo was dropped")
+	}
+}
+
+func TestDefaultPath_XDGStateHome(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/xdg/state")
+	if got, want := DefaultPath(), filepath.Join("/xdg/state", "gv", "state.json"); got != want {
+		t.Errorf("DefaultPath() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,186 @@
+// internal/status/activity.go
+package status
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackchuka/gv/internal/model"
+)
+
+// ActivityOptions selects the history covered by GetActivity.
+type ActivityOptions struct {
+	Since  time.Time
+	Author string // Passed to git log --author; empty means everyone
+}
+
+// commitMarker starts each commit in the log output read by GetActivity
+// (written by git for --format=%x1e).
+const commitMarker = "\x1e"
+
+// GetActivity reads commits, line counts and file churn since opts.Since,
+// plus local branches with their tracking state and the stash list.
+// The three git commands run in parallel; only a failing log is an error.
+func (r *GitReader) GetActivity(ctx context.Context, repoPath string, opts ActivityOptions) (*model.Activity, error) {
+	a := &model.Activity{Since: opts.Since, FileChurn: make(map[string]int)}
+
+	var (
+		wg     sync.WaitGroup
+		logErr error
+	)
+	wg.Add(3)
+
+	go func() {
+		defer wg.Done()
+		cmdCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		args := []string{"log", "--no-merges", "--numstat", "--format=%x1e",
+			"--since=" + opts.Since.Format(time.RFC3339)}
+		if opts.Author != "" {
+			args = append(args, "--author="+opts.Author)
+		}
+		out, err := r.runGit(cmdCtx, repoPath, args...)
+		if err != nil {
+			logErr = err
+			return
+		}
+		a.Commits, a.Added, a.Deleted, a.FileChurn = parseLogNumstat(out)
+	}()
+
+	go func() {
+		defer wg.Done()
+		cmdCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		out, err := r.runGit(cmdCtx, repoPath, "for-each-ref",
+			"--format=%(refname:short)%00%(upstream:short)%00%(upstream:track)%00%(committerdate:iso-strict)",
+			"refs/heads")
+		if err == nil {
+			a.Branches = parseBranches(out)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		cmdCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		out, err := r.runGit(cmdCtx, repoPath, "stash", "list", "--format=%gd%x00%cI%x00%gs")
+		if err == nil {
+			a.Stashes = parseStashList(out)
+		}
+	}()
+
+	wg.Wait()
+
+	if logErr != nil {
+		return nil, logErr
+	}
+	return a, nil
+}
+
+func (r *GitReader) GetActivityBatch(ctx context.Context, paths []string, opts ActivityOptions) map[string]*model.Activity {
+	results := make(map[string]*model.Activity)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	sem := make(chan struct{}, r.concurrency)
+
+	for _, path := range paths {
+		wg.Add(1)
+		go func(p string) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			a, err := r.GetActivity(ctx, p, opts)
+			if err != nil {
+				return
+			}
+
+			mu.Lock()
+			results[p] = a
+			mu.Unlock()
+		}(path)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// parseLogNumstat parses `git log --numstat --format=%x1e` output.
+// Binary files count towards churn but not towards line totals.
+func parseLogNumstat(output string) (commits, added, deleted int, churn map[string]int) {
+	churn = make(map[string]int)
+
+	for line := range strings.SplitSeq(output, "\n") {
+		if line == commitMarker {
+			commits++
+			continue
+		}
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		churn[parts[2]]++
+		if a, err := strconv.Atoi(parts[0]); err == nil {
+			added += a
+		}
+		if d, err := strconv.Atoi(parts[1]); err == nil {
+			deleted += d
+		}
+	}
+
+	return commits, added, deleted, churn
+}
+
+// parseBranches parses for-each-ref output with NUL-separated fields:
+// name, upstream, track ("[ahead 1, behind 2]" or "[gone]"), commit date.
+func parseBranches(output string) []model.BranchInfo {
+	var branches []model.BranchInfo
+
+	for line := range strings.SplitSeq(output, "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) < 4 || fields[0] == "" {
+			continue
+		}
+
+		b := model.BranchInfo{Name: fields[0], Upstream: fields[1]}
+		b.LastCommit, _ = time.Parse(time.RFC3339, fields[3])
+
+		track := strings.Trim(fields[2], "[]")
+		for part := range strings.SplitSeq(track, ", ") {
+			switch {
+			case part == "gone":
+				b.UpstreamGone = true
+			case strings.HasPrefix(part, "ahead "):
+				b.Ahead, _ = strconv.Atoi(strings.TrimPrefix(part, "ahead "))
+			case strings.HasPrefix(part, "behind "):
+				b.Behind, _ = strconv.Atoi(strings.TrimPrefix(part, "behind "))
+			}
+		}
+
+		branches = append(branches, b)
+	}
+
+	return branches
+}
+
+// parseStashList parses `git stash list --format=%gd%x00%cI%x00%gs` output.
+func parseStashList(output string) []model.StashEntry {
+	var stashes []model.StashEntry
+
+	for line := range strings.SplitSeq(output, "\n") {
+		fields := strings.SplitN(line, "\x00", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		e := model.StashEntry{Ref: fields[0], Message: fields[2]}
+		e.Time, _ = time.Parse(time.RFC3339, fields[1])
+		stashes = append(stashes, e)
+	}
+
+	return stashes
+}
@@ -0,0 +1,124 @@
+// internal/status/activity_test.go
+package status
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseLogNumstat(t *testing.T) {
+	output := "\x1e\n\n3\t1\tmain.go\n-\t-\tlogo.png\n\x1e\n\n2\t0\tmain.go\n"
+
+	commits, added, deleted, churn := parseLogNumstat(output)
+	if commits != 2 {
+		t.Errorf("commits = %d, want 2", commits)
+	}
+	if added != 5 || deleted != 1 {
+		t.Errorf("lines = +%d -%d, want +5 -1", added, deleted)
+	}
+	if churn["main.go"] != 2 || churn["logo.png"] != 1 {
+		t.Errorf("churn = %v", churn)
+	}
+}
+
+func TestParseBranches(t *testing.T) {
+	output := "main\x00origin/main\x00\x002026-01-02T03:04:05+00:00\n" +
+		"feature\x00origin/feature\x00[ahead 2, behind 1]\x002026-01-02T03:04:05+00:00\n" +
+		"old\x00origin/old\x00[gone]\x002026-01-02T03:04:05+00:00\n" +
+		"local\x00\x00\x002026-01-02T03:04:05+00:00\n"
+
+	branches := parseBranches(output)
+	if len(branches) != 4 {
+		t.Fatalf("got %d branches, want 4", len(branches))
+	}
+
+	tests := []struct {
+		name         string
+		upstream     string
+		ahead        int
+		behind       int
+		upstreamGone bool
+	}{
+		{"main", "origin/main", 0, 0, false},
+		{"feature", "origin/feature", 2, 1, false},
+		{"old", "origin/old", 0, 0, true},
+		{"local", "", 0, 0, false},
+	}
+	for i, tt := range tests {
+		b := branches[i]
+		if b.Name != tt.name || b.Upstream != tt.upstream || b.Ahead != tt.ahead ||
+			b.Behind != tt.behind || b.UpstreamGone != tt.upstreamGone {
+			t.Errorf("branch %d = %+v, want %+v", i, b, tt)
+		}
+		if b.LastCommit.IsZero() {
+			t.Errorf("branch %s: LastCommit not parsed", b.Name)
+		}
+	}
+}
+
+func TestParseStashList(t *testing.T) {
+	output := "stash@{0}\x002026-01-02T03:04:05+00:00\x00WIP on main: abc123 fix\n"
+
+	stashes := parseStashList(output)
+	if len(stashes) != 1 {
+		t.Fatalf("got %d stashes, want 1", len(stashes))
+	}
+	if stashes[0].Ref != "stash@{0}" || stashes[0].Message != "WIP on main: abc123 fix" || stashes[0].Time.IsZero() {
+		t.Errorf("stash = %+v", stashes[0])
+	}
+}
+
+func TestGitReader_GetActivity(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	tmpDir := t.TempDir()
+	runGit(t, tmpDir, "init")
+	runGit(t, tmpDir, "config", "user.email", "test@test.com")
+	runGit(t, tmpDir, "config", "user.name", "Test")
+
+	testFile := filepath.Join(tmpDir, "test.txt")
+	for i, content := range []string{"a\n", "a\nb\n"} {
+		if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, tmpDir, "add", "test.txt")
+		runGit(t, tmpDir, "commit", "-m", "commit "+string(rune('1'+i)))
+	}
+	if err := os.WriteFile(testFile, []byte("a\nb\nc\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, tmpDir, "stash")
+
+	reader := NewGitReader()
+	a, err := reader.GetActivity(context.Background(), tmpDir, ActivityOptions{Since: time.Now().Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("GetActivity() error = %v", err)
+	}
+
+	if a.Commits != 2 || a.Added != 2 {
+		t.Errorf("activity = %d commits +%d, want 2 commits +2", a.Commits, a.Added)
+	}
+	if top := a.TopChurnFiles(1); len(top) != 1 || top[0].Path != "test.txt" || top[0].Count != 2 {
+		t.Errorf("TopChurnFiles(1) = %v", top)
+	}
+	if len(a.Branches) != 1 || a.Branches[0].Upstream != "" {
+		t.Errorf("Branches = %+v, want one branch without upstream", a.Branches)
+	}
+	if len(a.Stashes) != 1 {
+		t.Errorf("Stashes = %+v, want 1", a.Stashes)
+	}
+
+	none, err := reader.GetActivity(context.Background(), tmpDir, ActivityOptions{Since: time.Now().Add(-time.Hour), Author: "nobody"})
+	if err != nil {
+		t.Fatalf("GetActivity() error = %v", err)
+	}
+	if none.Commits != 0 {
+		t.Errorf("Commits with unmatched author = %d, want 0", none.Commits)
+	}
+}
@@ -0,0 +1,106 @@
+package status
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackchuka/gv/internal/model"
+)
+
+// maxDetailCommits bounds the recent commits GetDetail reads.
+const maxDetailCommits = 50
+
+// GetDetail reads the branches, stashes, remotes (compared with HEAD),
+// recent commits and untracked files of the repo at repoPath, running the
+// git commands in parallel. Like GetDiffStats it always returns a result:
+// whatever git fails to list (an unborn branch has no log, a bare repo no
+// untracked files) is left empty.
+func (r *GitReader) GetDetail(ctx context.Context, repoPath string) *model.RepoDetail {
+	d := &model.RepoDetail{CollectedAt: time.Now()}
+
+	run := func(args []string, parse func(string)) func() {
+		return func() {
+			cmdCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			if out, err := r.runGit(cmdCtx, repoPath, args...); err == nil {
+				parse(out)
+			}
+		}
+	}
+	jobs := []func(){
+		run([]string{"for-each-ref",
+			"--format=%(refname:short)%00%(upstream:short)%00%(upstream:track)%00%(committerdate:iso-strict)",
+			"refs/heads"},
+			func(out string) { d.Branches = parseBranches(out) }),
+		run([]string{"stash", "list", "--format=%gd%x00%cI%x00%gs"},
+			func(out string) { d.Stashes = parseStashList(out) }),
+		func() {
+			cmdCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			d.Remotes = r.GetRemotes(cmdCtx, repoPath)
+		},
+		run([]string{"log", "-n", strconv.Itoa(maxDetailCommits), "--format=%h%x00%an%x00%cI%x00%s"},
+			func(out string) { d.Commits = parseCommits(out) }),
+		run([]string{"ls-files", "-z", "--others", "--exclude-standard", "--directory"},
+			func(out string) { d.Untracked = strings.FieldsFunc(out, func(r rune) bool { return r == 0 }) }),
+	}
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			job()
+		}()
+	}
+	wg.Wait()
+	return d
+}
+
+// parseRemotes parses `git remote -v` output, one line per remote and
+// direction: "origin\thttps://... (fetch)".
+func parseRemotes(output string) []model.Remote {
+	var remotes []model.Remote
+	index := make(map[string]int)
+
+	for line := range strings.SplitSeq(output, "\n") {
+		name, rest, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		url, dir, _ := strings.Cut(rest, " ")
+		i, seen := index[name]
+		if !seen {
+			i = len(remotes)
+			index[name] = i
+			remotes = append(remotes, model.Remote{Name: name})
+		}
+		if dir == "(push)" {
+			remotes[i].PushURL = url
+		} else {
+			remotes[i].FetchURL = url
+		}
+	}
+
+	return remotes
+}
+
+// parseCommits parses `git log --format=%h%x00%an%x00%cI%x00%s` output.
+func parseCommits(output string) []model.Commit {
+	var commits []model.Commit
+
+	for line := range strings.SplitSeq(output, "\n") {
+		fields := strings.SplitN(line, "\x00", 4)
+		if len(fields) < 4 {
+			continue
+		}
+		c := model.Commit{Hash: fields[0], Author: fields[1], Subject: fields[3]}
+		c.Time, _ = time.Parse(time.RFC3339, fields[2])
+		commits = append(commits, c)
+	}
+
+	return commits
+}
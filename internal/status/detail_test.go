@@ -0,0 +1,86 @@
+package status
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestParseRemotes(t *testing.T) {
+	output := "origin\thttps://github.com/a/b.git (fetch)\n" +
+		"origin\tgit@github.com:a/b.git (push)\n" +
+		"upstream\thttps://github.com/c/b.git (fetch)\n" +
+		"upstream\thttps://github.com/c/b.git (push)\n"
+
+	remotes := parseRemotes(output)
+	if len(remotes) != 2 {
+		t.Fatalf("got %d remotes, want 2", len(remotes))
+	}
+	if r := remotes[0]; r.Name != "origin" || r.FetchURL != "https://github.com/a/b.git" || r.PushURL != "git@github.com:a/b.git" {
+		t.Errorf("remote 0 = %+v", r)
+	}
+	if r := remotes[1]; r.Name != "upstream" || r.PushURL != r.FetchURL {
+		t.Errorf("remote 1 = %+v", r)
+	}
+}
+
+func TestParseCommits(t *testing.T) {
+	output := "abc1234\x00Ann\x002026-01-02T03:04:05+00:00\x00Fix: a\x00b\n"
+
+	commits := parseCommits(output)
+	if len(commits) != 1 {
+		t.Fatalf("got %d commits, want 1", len(commits))
+	}
+	if c := commits[0]; c.Hash != "abc1234" || c.Author != "Ann" || c.Subject != "Fix: a\x00b" || c.Time.IsZero() {
+		t.Errorf("commit = %+v", c)
+	}
+}
+
+func TestGitReader_GetDetail(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	tmpDir := t.TempDir()
+	runGit(t, tmpDir, "init")
+	runGit(t, tmpDir, "config", "user.email", "test@test.com")
+	runGit(t, tmpDir, "config", "user.name", "Test")
+
+	// An unborn branch has no log; the rest is still read
+	reader := NewGitReader()
+	if d := reader.GetDetail(context.Background(), tmpDir); d == nil || len(d.Commits) != 0 {
+		t.Fatalf("GetDetail() on an unborn branch = %+v", d)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "a.txt"), []byte("a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, tmpDir, "add", "a.txt")
+	runGit(t, tmpDir, "commit", "-m", "first")
+	runGit(t, tmpDir, "remote", "add", "origin", "https://example.com/a.git")
+	if err := os.MkdirAll(filepath.Join(tmpDir, "new dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"b.txt", "new dir/c.txt"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, f), []byte("b\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d := reader.GetDetail(context.Background(), tmpDir)
+	if len(d.Commits) != 1 || d.Commits[0].Subject != "first" {
+		t.Errorf("Commits = %+v", d.Commits)
+	}
+	if len(d.Branches) != 1 {
+		t.Errorf("Branches = %+v", d.Branches)
+	}
+	if len(d.Remotes) != 1 || d.Remotes[0].FetchURL != "https://example.com/a.git" {
+		t.Errorf("Remotes = %+v", d.Remotes)
+	}
+	if want := []string{"b.txt", "new dir/"}; !slices.Equal(d.Untracked, want) {
+		t.Errorf("Untracked = %q, want %q", d.Untracked, want)
+	}
+}
@@ -12,17 +12,50 @@ import (
 	"sync"
 	"time"
 
+	"github.com/jackchuka/gv/internal/gitdir"
+	"github.com/jackchuka/gv/internal/giturl"
 	"github.com/jackchuka/gv/internal/model"
 )
 
 type GitReader struct {
 	concurrency int
+
+	mu      sync.RWMutex
+	remotes map[string]string // repo path -> remote used instead of origin
 }
 
 func NewGitReader() *GitReader {
 	return &GitReader{
 		concurrency: 8,
+		remotes:     make(map[string]string),
+	}
+}
+
+// SetRemote makes gv fetch from remote and derive the owner from it for the
+// repo at repoPath. An empty remote restores the default.
+func (r *GitReader) SetRemote(repoPath, remote string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if remote == "" {
+		delete(r.remotes, repoPath)
+		return
 	}
+	r.remotes[repoPath] = remote
+}
+
+// remote returns the remote configured for repoPath, or "" for the default.
+func (r *GitReader) remote(repoPath string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.remotes[repoPath]
+}
+
+// ownerRemote returns the remote whose URL the owner is derived from.
+func (r *GitReader) ownerRemote(repoPath string) string {
+	if remote := r.remote(repoPath); remote != "" {
+		return remote
+	}
+	return "origin"
 }
 
 func (r *GitReader) GetStatus(ctx context.Context, repoPath string) (*model.RepoStatus, error) {
@@ -31,14 +64,49 @@ func (r *GitReader) GetStatus(ctx context.Context, repoPath string) (*model.Repo
 
 	output, err := r.runGit(cmdCtx, repoPath, "status", "--porcelain=v2", "--branch")
 	if err != nil {
+		// git status needs a working tree; bare repos get a reduced status
+		if l, _ := gitdir.Resolve(repoPath); l != nil && l.Bare {
+			return r.getBareStatus(ctx, repoPath)
+		}
 		return nil, err
 	}
 
 	return r.GetStatusFromOutput(ctx, repoPath, output)
 }
 
+// getBareStatus reads what a bare repo has: the branch HEAD points at, the
+// commit it resolves to and the owner of origin. Working tree counts stay
+// zero and there is no upstream tracking.
+func (r *GitReader) getBareStatus(ctx context.Context, repoPath string) (*model.RepoStatus, error) {
+	cmdCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	status := &model.RepoStatus{}
+	if branch, err := r.runGit(cmdCtx, repoPath, "symbolic-ref", "--quiet", "--short", "HEAD"); err == nil {
+		status.Branch = strings.TrimSpace(branch)
+	} else {
+		status.DetachedHead = true
+	}
+
+	// Fails on an unborn branch, which leaves hash and time empty
+	if out, err := r.runGit(cmdCtx, repoPath, "log", "-1", "--format=%h %ct"); err == nil {
+		if hash, ts, ok := strings.Cut(strings.TrimSpace(out), " "); ok {
+			status.CommitHash = hash
+			if sec, err := strconv.ParseInt(ts, 10, 64); err == nil {
+				status.LastCommit = time.Unix(sec, 0)
+			}
+		}
+	}
+
+	if remoteURL, err := r.runGit(cmdCtx, repoPath, "remote", "get-url", r.ownerRemote(repoPath)); err == nil {
+		status.Owner = parseOwnerFromURL(strings.TrimSpace(remoteURL))
+	}
+
+	return status, nil
+}
+
 // GetStatusFromOutput builds a full RepoStatus from pre-fetched porcelain output,
-// running supplementary commands (stash, log, remote) in parallel.
+// running supplementary commands (stash, log, remote, fork) in parallel.
 func (r *GitReader) GetStatusFromOutput(ctx context.Context, repoPath string, porcelainOutput string) (*model.RepoStatus, error) {
 	status, err := parsePorcelainV2(porcelainOutput)
 	if err != nil {
@@ -75,9 +143,21 @@ func (r *GitReader) GetStatusFromOutput(ctx context.Context, repoPath string, po
 		defer wg.Done()
 		cmdCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 		defer cancel()
-		remoteURL, err := r.runGit(cmdCtx, repoPath, "remote", "get-url", "origin")
-		if err == nil {
-			status.Owner = parseOwnerFromURL(strings.TrimSpace(remoteURL))
+		out, err := r.runGit(cmdCtx, repoPath, "remote", "-v") // applies insteadOf rewrites
+		if err != nil {
+			return
+		}
+		remotes := parseRemotes(out)
+		owner := r.ownerRemote(repoPath)
+		fork := false
+		for _, rm := range remotes {
+			if rm.Name == owner {
+				status.Owner = parseOwnerFromURL(rm.FetchURL)
+			}
+			fork = fork || rm.Name == forkRemote
+		}
+		if fork {
+			r.readFork(cmdCtx, repoPath, status)
 		}
 	}()
 
@@ -143,16 +223,8 @@ func (r *GitReader) runGit(ctx context.Context, repoPath string, args ...string)
 
 func (r *GitReader) checkSpecialStates(repoPath string, status *model.RepoStatus) {
 	gitDir := filepath.Join(repoPath, ".git")
-
-	info, err := os.Stat(gitDir)
-	if err == nil && !info.IsDir() {
-		content, err := os.ReadFile(gitDir)
-		if err == nil {
-			line := strings.TrimSpace(string(content))
-			if strings.HasPrefix(line, "gitdir:") {
-				gitDir = strings.TrimSpace(strings.TrimPrefix(line, "gitdir:"))
-			}
-		}
+	if l, err := gitdir.Resolve(repoPath); err == nil && l != nil {
+		gitDir = l.GitDir
 	}
 
 	checks := []struct {
@@ -192,36 +264,11 @@ func countLines(s string) int {
 	return strings.Count(s, "\n") + 1
 }
 
-// parseOwnerFromURL extracts the owner/org from a git remote URL
-// Handles:
-//   - SSH colon format: git@github.com:owner/repo.git
-//   - SSH URL format: ssh://git@github.com/owner/repo.git
-//   - HTTPS format: https://github.com/owner/repo.git
+// parseOwnerFromURL extracts the owner/org from a git remote URL: the
+// top-level group for GitLab subgroups, the org for Azure DevOps.
 func parseOwnerFromURL(url string) string {
-	if strings.Contains(url, "@") && strings.Contains(url, ":") && !strings.HasPrefix(url, "ssh://") {
-		parts := strings.SplitN(url, ":", 2)
-		if len(parts) == 2 && !strings.Contains(parts[0], "/") {
-			pathParts := strings.Split(parts[1], "/")
-			if len(pathParts) >= 1 {
-				return pathParts[0]
-			}
-		}
-	}
-
-	url = strings.TrimPrefix(url, "ssh://")
-	url = strings.TrimPrefix(url, "https://")
-	url = strings.TrimPrefix(url, "http://")
-
-	if idx := strings.Index(url, "@"); idx != -1 {
-		url = url[idx+1:]
-	}
-
-	parts := strings.Split(url, "/")
-	if len(parts) >= 2 {
-		return parts[1]
-	}
-
-	return ""
+	u, _ := giturl.Parse(url)
+	return u.TopOwner()
 }
 
 // Fetch runs git fetch and returns updated status.
@@ -231,7 +278,11 @@ func (r *GitReader) Fetch(ctx context.Context, repoPath string) (*model.RepoStat
 	fetchCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	_, fetchErr := r.runGit(fetchCtx, repoPath, "fetch", "--quiet")
+	args := []string{"fetch", "--quiet"}
+	if remote := r.remote(repoPath); remote != "" {
+		args = append(args, remote)
+	}
+	_, fetchErr := r.runGit(fetchCtx, repoPath, args...)
 
 	// Get updated status — use parent ctx so cancellation propagates
 	status, statusErr := r.GetStatus(ctx, repoPath)
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/jackchuka/gv/internal/model"
@@ -289,3 +290,90 @@ func TestCheckSpecialStates(t *testing.T) {
 		}
 	})
 }
+
+func TestGitReader_GetStatus_Bare(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	tmpDir := t.TempDir()
+	src := filepath.Join(tmpDir, "src")
+	if err := os.Mkdir(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, src, "init")
+	runGit(t, src, "config", "user.email", "test@test.com")
+	runGit(t, src, "config", "user.name", "Test")
+	runGit(t, src, "commit", "--allow-empty", "-m", "initial")
+
+	bare := filepath.Join(tmpDir, "bare.git")
+	runGit(t, tmpDir, "clone", "--bare", src, bare)
+
+	status, err := NewGitReader().GetStatus(context.Background(), bare)
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	if status.Branch != "master" && status.Branch != "main" {
+		t.Errorf("Branch = %q, want master or main", status.Branch)
+	}
+	if status.CommitHash == "" || status.LastCommit.IsZero() {
+		t.Errorf("CommitHash = %q, LastCommit = %v; want HEAD's", status.CommitHash, status.LastCommit)
+	}
+	if status.IsDirty() {
+		t.Error("a bare repo is never dirty")
+	}
+}
+
+func TestGitReader_SetRemote(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	tmpDir := t.TempDir()
+	upstream := filepath.Join(tmpDir, "upstream")
+	repo := filepath.Join(tmpDir, "repo")
+	for _, dir := range []string{upstream, repo} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, dir, "init")
+		runGit(t, dir, "config", "user.email", "test@test.com")
+		runGit(t, dir, "config", "user.name", "Test")
+		runGit(t, dir, "commit", "--allow-empty", "-m", "initial")
+	}
+	runGit(t, repo, "remote", "add", "origin", "https://github.com/alice/repo.git")
+	runGit(t, repo, "remote", "add", "upstream", upstream)
+
+	r := NewGitReader()
+	ctx := context.Background()
+
+	status, err := r.GetStatus(ctx, repo)
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	if status.Owner != "alice" {
+		t.Errorf("Owner = %q, want alice from origin", status.Owner)
+	}
+
+	r.SetRemote(repo, "upstream")
+	if _, err := r.Fetch(ctx, repo); err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if out, _ := exec.Command("git", "-C", repo, "branch", "-r").Output(); !strings.Contains(string(out), "upstream/") {
+		t.Errorf("git branch -r = %q, want branches fetched from upstream", out)
+	}
+
+	// Owner now comes from the upstream URL, a local path without one
+	status, err = r.GetStatus(ctx, repo)
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	if status.Owner == "alice" {
+		t.Error("Owner still derived from origin after SetRemote")
+	}
+
+	r.SetRemote(repo, "")
+	if status, _ := r.GetStatus(ctx, repo); status.Owner != "alice" {
+		t.Errorf("Owner after reset = %q, want alice", status.Owner)
+	}
+}
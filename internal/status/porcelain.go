@@ -13,6 +13,7 @@ func parsePorcelainV2(output string) (*model.RepoStatus, error) {
 		Aliases: make(map[string]string),
 	}
 
+	hasAB := false
 	lines := strings.Split(output, "\n")
 	for _, line := range lines {
 		if line == "" {
@@ -21,6 +22,9 @@ func parsePorcelainV2(output string) (*model.RepoStatus, error) {
 
 		// Header lines start with #
 		if strings.HasPrefix(line, "# ") {
+			if strings.HasPrefix(line, "# branch.ab ") {
+				hasAB = true
+			}
 			parseHeaderLine(line, status)
 			continue
 		}
@@ -45,10 +49,14 @@ func parsePorcelainV2(output string) (*model.RepoStatus, error) {
 		// Unmerged files
 		if strings.HasPrefix(line, "u ") {
 			status.Modified++ // Count as modified
+			status.Conflicts++
 			continue
 		}
 	}
 
+	// git omits branch.ab when the configured upstream no longer exists
+	status.UpstreamGone = status.Remote != "" && !hasAB
+
 	return status, nil
 }
 
@@ -88,12 +96,15 @@ func parseHeaderLine(line string, status *model.RepoStatus) {
 }
 
 func parseChangeLine(line string, status *model.RepoStatus) {
-	// Format: "1 XY ..."
+	// Format: "1 XY sub mH mI mW hH hI path" or
+	//         "2 XY sub mH mI mW hH hI Xscore path\torigPath"
 	// X = index status, Y = worktree status
 	if len(line) < 4 {
 		return
 	}
 
+	parseSubmoduleField(line, status)
+
 	xy := line[2:4]
 	indexStatus := xy[0]
 	worktreeStatus := xy[1]
@@ -108,3 +119,27 @@ func parseChangeLine(line string, status *model.RepoStatus) {
 		status.Modified++
 	}
 }
+
+// parseSubmoduleField records the state of a changed submodule from the
+// sub field, "S<c><m><u>" ("N..." for ordinary files).
+func parseSubmoduleField(line string, status *model.RepoStatus) {
+	n := 9 // fields, the path being the last
+	if line[0] == '2' {
+		n = 10
+	}
+	fields := strings.SplitN(line, " ", n)
+	if len(fields) < n || len(fields[2]) != 4 || fields[2][0] != 'S' {
+		return
+	}
+
+	path, _, _ := strings.Cut(fields[n-1], "\t")
+	sub := fields[2]
+	if status.Submodules == nil {
+		status.Submodules = make(map[string]model.SubmoduleState)
+	}
+	status.Submodules[path] = model.SubmoduleState{
+		NewCommits: sub[1] == 'C',
+		Modified:   sub[2] == 'M',
+		Untracked:  sub[3] == 'U',
+	}
+}
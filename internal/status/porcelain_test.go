@@ -3,6 +3,8 @@ package status
 
 import (
 	"testing"
+
+	"github.com/jackchuka/gv/internal/model"
 )
 
 func TestParsePorcelainV2(t *testing.T) {
@@ -82,3 +84,70 @@ func TestParsePorcelainV2_NoUpstream(t *testing.T) {
 		t.Errorf("Remote = %q, want empty", status.Remote)
 	}
 }
+
+func TestParsePorcelainV2_UpstreamGone(t *testing.T) {
+	output := `# branch.oid abc123def456
+# branch.head feature
+# branch.upstream origin/feature
+`
+
+	status, err := parsePorcelainV2(output)
+	if err != nil {
+		t.Fatalf("parsePorcelainV2() error = %v", err)
+	}
+
+	if !status.UpstreamGone {
+		t.Error("UpstreamGone should be true when branch.ab is missing")
+	}
+}
+
+func TestParsePorcelainV2_Conflicts(t *testing.T) {
+	output := `# branch.oid abc123def456
+# branch.head main
+# branch.upstream origin/main
+# branch.ab +0 -0
+u UU N... 100644 100644 100644 100644 abc123 def456 789abc conflict.go
+`
+
+	status, err := parsePorcelainV2(output)
+	if err != nil {
+		t.Fatalf("parsePorcelainV2() error = %v", err)
+	}
+
+	if status.Conflicts != 1 {
+		t.Errorf("Conflicts = %d, want 1", status.Conflicts)
+	}
+	if status.UpstreamGone {
+		t.Error("UpstreamGone should be false when branch.ab is present")
+	}
+	if !status.HasConflict() {
+		t.Error("HasConflict() should be true")
+	}
+}
+
+func TestParsePorcelainV2_Submodules(t *testing.T) {
+	output := `# branch.oid abc123def456
+# branch.head main
+1 .M SC.. 160000 160000 160000 abc123 abc123 libs/core
+1 .M S.MU 160000 160000 160000 abc123 abc123 vendor/ui kit
+1 .M N... 100644 100644 100644 abc123 abc123 main.go
+`
+
+	status, err := parsePorcelainV2(output)
+	if err != nil {
+		t.Fatalf("parsePorcelainV2() error = %v", err)
+	}
+
+	if len(status.Submodules) != 2 {
+		t.Fatalf("Submodules = %v, want 2 entries", status.Submodules)
+	}
+	if got := status.Submodules["libs/core"]; got != (model.SubmoduleState{NewCommits: true}) {
+		t.Errorf("libs/core = %+v, want new commits only", got)
+	}
+	if got := status.Submodules["vendor/ui kit"]; got != (model.SubmoduleState{Modified: true, Untracked: true}) {
+		t.Errorf("vendor/ui kit = %+v, want modified and untracked", got)
+	}
+	if status.Modified != 3 {
+		t.Errorf("Modified = %d, want 3", status.Modified)
+	}
+}
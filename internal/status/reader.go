@@ -21,5 +21,22 @@ type Reader interface {
 	GetDiffStats(ctx context.Context, repoPath string) *model.DiffStats
 	GetDiffStatsBatch(ctx context.Context, paths []string) map[string]*model.DiffStats
 
+	// GetDetail always returns a result (possibly partial) — never nil.
+	GetDetail(ctx context.Context, repoPath string) *model.RepoDetail
+	// GetRemotes is the Remotes part of GetDetail, read on its own.
+	GetRemotes(ctx context.Context, repoPath string) []model.Remote
+
 	RunAlias(ctx context.Context, repoPath string, cmd string) (string, error)
 }
+
+// ConfigureRemotes passes each repo's default remote to reader when it
+// supports per-repo remotes.
+func ConfigureRemotes(reader Reader, repos []model.Repository) {
+	rs, ok := reader.(interface{ SetRemote(repoPath, remote string) })
+	if !ok {
+		return
+	}
+	for _, repo := range repos {
+		rs.SetRemote(repo.Path, repo.DefaultRemote)
+	}
+}
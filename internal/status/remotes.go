@@ -0,0 +1,144 @@
+package status
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/jackchuka/gv/internal/giturl"
+	"github.com/jackchuka/gv/internal/model"
+)
+
+// forkRemote is the remote a fork's original repository is conventionally
+// added as.
+const forkRemote = "upstream"
+
+// remoteRefs are the remote-tracking branches of a remote gv looks at.
+type remoteRefs struct {
+	match         string // compared with HEAD, e.g. "upstream/main"
+	defaultBranch string // e.g. "main"; empty when unknown
+}
+
+// matchingRefs returns the refs of each of remotes: the default branch is
+// the one the remote's HEAD points at, else main or master, and HEAD is
+// compared with the branch named branch, else the default branch. Remotes
+// with neither are absent.
+func (r *GitReader) matchingRefs(ctx context.Context, repoPath, branch string, remotes []string) map[string]remoteRefs {
+	args := []string{"for-each-ref", "--format=%(refname)%00%(symref)"}
+	for _, name := range remotes {
+		for _, b := range []string{branch, "HEAD", "main", "master"} {
+			if b != "" {
+				args = append(args, "refs/remotes/"+name+"/"+b)
+			}
+		}
+	}
+	out, err := r.runGit(ctx, repoPath, args...)
+	if err != nil {
+		return nil
+	}
+	return parseMatchingRefs(out, branch, remotes)
+}
+
+// parseMatchingRefs picks each remote's refs from for-each-ref output of
+// "refname NUL symref" lines.
+func parseMatchingRefs(output, branch string, remotes []string) map[string]remoteRefs {
+	heads := make(map[string]string) // remote -> default branch
+	found := make(map[string]bool)   // short names, e.g. origin/main
+	for line := range strings.SplitSeq(output, "\n") {
+		ref, target, _ := strings.Cut(line, "\x00")
+		short, ok := strings.CutPrefix(ref, "refs/remotes/")
+		if !ok {
+			continue
+		}
+		if remote, ok := strings.CutSuffix(short, "/HEAD"); ok {
+			if t, ok := strings.CutPrefix(target, "refs/remotes/"+remote+"/"); ok {
+				heads[remote] = t
+			}
+			continue
+		}
+		found[short] = true
+	}
+
+	refs := make(map[string]remoteRefs)
+	for _, name := range remotes {
+		var rr remoteRefs
+		if h, ok := heads[name]; ok {
+			rr.defaultBranch = h
+		} else if found[name+"/main"] {
+			rr.defaultBranch = "main"
+		} else if found[name+"/master"] {
+			rr.defaultBranch = "master"
+		}
+		if branch != "" && found[name+"/"+branch] {
+			rr.match = name + "/" + branch
+		} else if rr.defaultBranch != "" {
+			rr.match = name + "/" + rr.defaultBranch
+		}
+		if rr.match != "" {
+			refs[name] = rr
+		}
+	}
+	return refs
+}
+
+// aheadBehind counts the commits HEAD has that ref lacks, and the reverse.
+func (r *GitReader) aheadBehind(ctx context.Context, repoPath, ref string) (ahead, behind int, err error) {
+	out, err := r.runGit(ctx, repoPath, "rev-list", "--left-right", "--count", "HEAD..."+ref)
+	if err != nil {
+		return 0, 0, err
+	}
+	left, right, _ := strings.Cut(strings.TrimSpace(out), "\t")
+	ahead, _ = strconv.Atoi(left)
+	behind, _ = strconv.Atoi(right)
+	return ahead, behind, nil
+}
+
+// readFork fills in how far HEAD is behind the upstream remote. Callers
+// check the repo has one first.
+func (r *GitReader) readFork(ctx context.Context, repoPath string, status *model.RepoStatus) {
+	ref := r.matchingRefs(ctx, repoPath, status.Branch, []string{forkRemote})[forkRemote].match
+	if ref == "" {
+		return
+	}
+	if _, behind, err := r.aheadBehind(ctx, repoPath, ref); err == nil {
+		status.ForkBranch, status.ForkBehind = ref, behind
+	}
+}
+
+// GetRemotes lists the remotes with their URLs parsed, each compared with
+// HEAD.
+func (r *GitReader) GetRemotes(ctx context.Context, repoPath string) []model.Remote {
+	out, err := r.runGit(ctx, repoPath, "remote", "-v") // applies insteadOf rewrites
+	if err != nil {
+		return nil
+	}
+	remotes := parseRemotes(out)
+	if len(remotes) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(remotes))
+	for i := range remotes {
+		names[i] = remotes[i].Name
+		if u, ok := giturl.Parse(remotes[i].FetchURL); ok {
+			remotes[i].Host, remotes[i].Owner, remotes[i].Repo = u.Host, u.Owner, u.Repo
+		}
+	}
+
+	branch := ""
+	if out, err := r.runGit(ctx, repoPath, "symbolic-ref", "--quiet", "--short", "HEAD"); err == nil {
+		branch = strings.TrimSpace(out)
+	}
+	refs := r.matchingRefs(ctx, repoPath, branch, names)
+	for i := range remotes {
+		rr, ok := refs[remotes[i].Name]
+		if !ok {
+			continue
+		}
+		remotes[i].DefaultBranch = rr.defaultBranch
+		if ahead, behind, err := r.aheadBehind(ctx, repoPath, rr.match); err == nil {
+			remotes[i].Branch, remotes[i].Ahead, remotes[i].Behind = rr.match, ahead, behind
+		}
+	}
+	return remotes
+}
@@ -0,0 +1,118 @@
+package status
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseMatchingRefs(t *testing.T) {
+	output := "refs/remotes/origin/feature\x00\n" +
+		"refs/remotes/origin/HEAD\x00refs/remotes/origin/main\n" +
+		"refs/remotes/upstream/HEAD\x00refs/remotes/upstream/trunk\n" +
+		"refs/remotes/mirror/master\x00\n"
+
+	got := parseMatchingRefs(output, "feature", []string{"origin", "upstream", "mirror", "backup"})
+	want := map[string]remoteRefs{
+		"origin":   {match: "origin/feature", defaultBranch: "main"},
+		"upstream": {match: "upstream/trunk", defaultBranch: "trunk"},
+		"mirror":   {match: "mirror/master", defaultBranch: "master"},
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("parseMatchingRefs() = %v, want %v", got, want)
+	}
+
+	// Detached: only default branches
+	got = parseMatchingRefs("refs/remotes/origin/HEAD\x00refs/remotes/origin/main\n", "", []string{"origin"})
+	if want := map[string]remoteRefs{"origin": {match: "origin/main", defaultBranch: "main"}}; !maps.Equal(got, want) {
+		t.Errorf("parseMatchingRefs() detached = %v, want %v", got, want)
+	}
+}
+
+func TestGitReader_RemotesAndFork(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	// The original repo, cloned as a fork that adds it as upstream through
+	// an insteadOf alias
+	orig := t.TempDir()
+	runGit(t, orig, "init", "-b", "main")
+	runGit(t, orig, "config", "user.email", "test@test.com")
+	runGit(t, orig, "config", "user.name", "Test")
+	commit := func(dir, name string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, dir, "add", name)
+		runGit(t, dir, "commit", "-m", name)
+	}
+	commit(orig, "a.txt")
+
+	fork := filepath.Join(t.TempDir(), "fork")
+	runGit(t, orig, "clone", "--quiet", orig, fork)
+	runGit(t, fork, "config", "user.email", "test@test.com")
+	runGit(t, fork, "config", "user.name", "Test")
+	runGit(t, fork, "config", "url."+orig+".insteadOf", "up:")
+	runGit(t, fork, "remote", "add", "upstream", "up:")
+	commit(orig, "b.txt")
+	commit(orig, "c.txt")
+	commit(fork, "d.txt")
+	runGit(t, fork, "fetch", "--quiet", "upstream")
+
+	reader := NewGitReader()
+	status, err := reader.GetStatus(context.Background(), fork)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.ForkBranch != "upstream/main" || status.ForkBehind != 2 {
+		t.Errorf("fork = %q behind %d, want upstream/main behind 2", status.ForkBranch, status.ForkBehind)
+	}
+
+	d := reader.GetDetail(context.Background(), fork)
+	if len(d.Remotes) != 2 {
+		t.Fatalf("Remotes = %+v", d.Remotes)
+	}
+	origin, upstream := d.Remotes[0], d.Remotes[1]
+	if origin.Name != "origin" || origin.Branch != "origin/main" || origin.Ahead != 1 || origin.Behind != 0 || origin.DefaultBranch != "main" {
+		t.Errorf("origin = %+v, want origin/main ahead 1", origin)
+	}
+	if upstream.FetchURL != orig || upstream.Branch != "upstream/main" || upstream.Ahead != 1 || upstream.Behind != 2 {
+		t.Errorf("upstream = %+v, want %s at upstream/main ahead 1 behind 2", upstream, orig)
+	}
+	if upstream.Host != "" {
+		t.Errorf("upstream.Host = %q for a local path", upstream.Host)
+	}
+}
+
+func TestGitReader_NoForkLookupWithoutUpstream(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-b", "main")
+	runGit(t, repo, "remote", "add", "origin", "git@github.com:alice/app.git")
+
+	// Every git command the reader runs is traced here
+	trace := filepath.Join(t.TempDir(), "trace")
+	t.Setenv("GIT_TRACE", trace)
+
+	status, err := NewGitReader().GetStatus(context.Background(), repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Owner != "alice" || status.ForkBranch != "" {
+		t.Errorf("Owner = %q, ForkBranch = %q; want alice and no fork", status.Owner, status.ForkBranch)
+	}
+	out, err := os.ReadFile(trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(out), "for-each-ref") || strings.Contains(string(out), "rev-list") {
+		t.Errorf("fork refs read without an upstream remote:\n%s", out)
+	}
+}
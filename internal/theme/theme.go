@@ -0,0 +1,209 @@
+// Package theme defines the color palettes of the TUI: built-in themes,
+// themes from the config, and overrides of single colors. Colors are named
+// by what they mean (ahead, conflict, ...) rather than what they look like,
+// so a theme can change them without the UI code knowing.
+package theme
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Role is a use of color in the UI.
+type Role string
+
+const (
+	Clean       Role = "clean"        // clean repos
+	Dirty       Role = "dirty"        // uncommitted changes, stashes
+	Ahead       Role = "ahead"        // commits to push
+	Behind      Role = "behind"       // commits to pull, gone upstreams
+	Conflict    Role = "conflict"     // conflicts and merge/rebase in progress
+	Danger      Role = "danger"       // errors
+	Added       Role = "added"        // added lines
+	Deleted     Role = "deleted"      // deleted lines
+	Accent      Role = "accent"       // branches, keys, borders
+	Highlight   Role = "highlight"    // fuzzy match positions, success
+	Title       Role = "title"        // app title
+	Tag         Role = "tag"          // tag chips, net delta
+	Churn       Role = "churn"        // activity
+	Fg          Role = "fg"           // regular text
+	Dim         Role = "dim"          // secondary text
+	Header      Role = "header"       // table header
+	SelectionBg Role = "selection_bg" // selected row
+	SelectionFg Role = "selection_fg"
+	RowAlt      Role = "row_alt"   // every other row
+	BarEmpty    Role = "bar_empty" // empty bar segments
+	Glow        Role = "glow"      // row border flashed on change
+	GlowFade    Role = "glow_fade" // then faded out through these
+	GlowFade2   Role = "glow_fade2"
+	GlowFade3   Role = "glow_fade3"
+	GlowOff     Role = "glow_off"
+)
+
+// Roles lists every role.
+var Roles = []Role{
+	Clean, Dirty, Ahead, Behind, Conflict, Danger, Added, Deleted,
+	Accent, Highlight, Title, Tag, Churn, Fg, Dim, Header,
+	SelectionBg, SelectionFg, RowAlt, BarEmpty, Glow, GlowFade, GlowFade2, GlowFade3, GlowOff,
+}
+
+// Theme maps each role to a color: an ANSI 256 color index such as "71"
+// or a hex color such as "#5fafaf". An empty color leaves the terminal's.
+type Theme map[Role]string
+
+// Def is a theme from the config: a base theme and the colors it changes.
+type Def struct {
+	Base   string            `yaml:"base,omitempty"` // built-in theme to start from; default dark
+	Colors map[string]string `yaml:"colors"`         // role -> color
+}
+
+// Auto picks dark or light from the terminal background.
+const Auto = "auto"
+
+// None is used when NO_COLOR is set. It has no colors at all.
+const None = "none"
+
+var builtins = map[string]Theme{
+	"dark": {
+		Clean: "71", Dirty: "179", Ahead: "179", Behind: "167", Conflict: "196", Danger: "167",
+		Added: "71", Deleted: "167", Accent: "73", Highlight: "220", Title: "44", Tag: "69", Churn: "208",
+		Fg: "253", Dim: "242", Header: "245", SelectionBg: "238", SelectionFg: "255", RowAlt: "234",
+		BarEmpty: "238", Glow: "46", GlowFade: "34", GlowFade2: "28", GlowFade3: "23", GlowOff: "236",
+	},
+	"light": {
+		Clean: "28", Dirty: "130", Ahead: "130", Behind: "124", Conflict: "160", Danger: "124",
+		Added: "28", Deleted: "124", Accent: "30", Highlight: "166", Title: "31", Tag: "25", Churn: "166",
+		Fg: "235", Dim: "244", Header: "240", SelectionBg: "252", SelectionFg: "232", RowAlt: "255",
+		BarEmpty: "250", Glow: "34", GlowFade: "71", GlowFade2: "114", GlowFade3: "151", GlowOff: "254",
+	},
+	// Bright colors on the terminal background, no alternating rows
+	"high-contrast": {
+		Clean: "46", Dirty: "226", Ahead: "226", Behind: "196", Conflict: "201", Danger: "196",
+		Added: "46", Deleted: "196", Accent: "51", Highlight: "226", Title: "51", Tag: "117", Churn: "214",
+		Fg: "231", Dim: "250", Header: "231", SelectionBg: "21", SelectionFg: "231", RowAlt: "",
+		BarEmpty: "240", Glow: "46", GlowFade: "40", GlowFade2: "34", GlowFade3: "28", GlowOff: "",
+	},
+	// Okabe-Ito palette: no red/green pairs to tell apart
+	"colorblind": {
+		Clean: "#56B4E9", Dirty: "#E69F00", Ahead: "#E69F00", Behind: "#D55E00", Conflict: "#CC79A7", Danger: "#D55E00",
+		Added: "#56B4E9", Deleted: "#D55E00", Accent: "#009E73", Highlight: "#F0E442", Title: "#56B4E9", Tag: "111", Churn: "#E69F00",
+		Fg: "253", Dim: "242", Header: "245", SelectionBg: "238", SelectionFg: "255", RowAlt: "234",
+		BarEmpty: "238", Glow: "#F0E442", GlowFade: "178", GlowFade2: "136", GlowFade3: "94", GlowOff: "236",
+	},
+	None: {},
+}
+
+// Builtin returns a copy of the built-in theme called name.
+func Builtin(name string) (Theme, bool) {
+	t, ok := builtins[name]
+	return maps.Clone(t), ok
+}
+
+// Names lists the built-in themes, for messages.
+func Names() []string {
+	return slices.Sorted(maps.Keys(builtins))
+}
+
+var hexColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// ValidColor reports whether c is an ANSI 256 color index or a hex color.
+func ValidColor(c string) bool {
+	if hexColor.MatchString(c) {
+		return true
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// With returns t with colors replaced, checking roles and colors.
+func (t Theme) With(colors map[string]string) (Theme, error) {
+	out := maps.Clone(t)
+	if out == nil {
+		out = make(Theme)
+	}
+	for role, c := range colors {
+		if !slices.Contains(Roles, Role(role)) {
+			return nil, fmt.Errorf("unknown color role %q", role)
+		}
+		if c != "" && !ValidColor(c) {
+			return nil, fmt.Errorf("%s: invalid color %q (want 0-255 or #rrggbb)", role, c)
+		}
+		out[Role(role)] = c
+	}
+	return out, nil
+}
+
+// Resolve returns the theme called name, from the built-ins or custom,
+// with overrides applied. An empty name means Auto, which asks dark
+// whether the terminal background is dark. NO_COLOR in the environment
+// selects None whatever the name.
+func Resolve(name string, custom map[string]Def, overrides map[string]string, dark func() bool) (Theme, error) {
+	if os.Getenv("NO_COLOR") != "" {
+		return Theme{}, nil
+	}
+	return resolve(name, custom, overrides, dark)
+}
+
+// Check reports what Resolve would fail on, whatever the environment.
+func Check(name string, custom map[string]Def, overrides map[string]string) error {
+	for n := range custom {
+		if _, err := resolve(n, custom, nil, func() bool { return true }); err != nil {
+			return err
+		}
+	}
+	_, err := resolve(name, custom, overrides, func() bool { return true })
+	return err
+}
+
+func resolve(name string, custom map[string]Def, overrides map[string]string, dark func() bool) (Theme, error) {
+	if name == "" || name == Auto {
+		name = "light"
+		if dark() {
+			name = "dark"
+		}
+	}
+
+	t, ok := Builtin(name)
+	if def, isCustom := custom[name]; isCustom {
+		base := def.Base
+		if base == "" {
+			base = "dark"
+		}
+		b, ok := Builtin(base)
+		if !ok {
+			return nil, fmt.Errorf("theme %s: unknown base theme %q", name, base)
+		}
+		var err error
+		if t, err = b.With(def.Colors); err != nil {
+			return nil, fmt.Errorf("theme %s: %w", name, err)
+		}
+	} else if !ok {
+		return nil, fmt.Errorf("unknown theme %q (want %s, auto, or one from themes)", name, strings.Join(Names(), ", "))
+	}
+
+	t, err := t.With(overrides)
+	if err != nil {
+		return nil, fmt.Errorf("colors: %w", err)
+	}
+	return t, nil
+}
+
+// DarkFromEnv reads the background from COLORFGBG ("fg;bg"), which some
+// terminals set. ok is false when it is missing or unclear.
+func DarkFromEnv() (dark, ok bool) {
+	v := os.Getenv("COLORFGBG")
+	if v == "" {
+		return false, false
+	}
+	bg, err := strconv.Atoi(v[strings.LastIndex(v, ";")+1:])
+	if err != nil {
+		return false, false
+	}
+	// 0-6 and 8 are the dark ANSI colors
+	return bg <= 6 || bg == 8, true
+}
@@ -0,0 +1,110 @@
+package theme
+
+import (
+	"testing"
+)
+
+func TestBuiltinsCoverEveryRole(t *testing.T) {
+	for _, name := range Names() {
+		if name == None {
+			continue
+		}
+		th, _ := Builtin(name)
+		for _, r := range Roles {
+			c, ok := th[r]
+			if !ok {
+				t.Errorf("%s: no color for %s", name, r)
+			}
+			if c != "" && !ValidColor(c) {
+				t.Errorf("%s: invalid color %q for %s", name, c, r)
+			}
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	dark := func() bool { return true }
+	light := func() bool { return false }
+	custom := map[string]Def{
+		"mine": {Base: "light", Colors: map[string]string{"ahead": "#112233"}},
+		"bad":  {Base: "sepia"},
+	}
+
+	tests := []struct {
+		name      string
+		overrides map[string]string
+		dark      func() bool
+		role      Role
+		want      string
+		wantErr   bool
+	}{
+		{name: "", dark: dark, role: Clean, want: "71"},
+		{name: "auto", dark: light, role: Clean, want: "28"},
+		{name: "light", dark: dark, role: Clean, want: "28"},
+		{name: "mine", dark: dark, role: Ahead, want: "#112233"},
+		{name: "mine", dark: dark, role: Clean, want: "28"},
+		{name: "dark", overrides: map[string]string{"conflict": "201"}, dark: dark, role: Conflict, want: "201"},
+		{name: "dark", overrides: map[string]string{"row_alt": ""}, dark: dark, role: RowAlt, want: ""},
+		{name: "solarized", dark: dark, wantErr: true},
+		{name: "bad", dark: dark, wantErr: true},
+		{name: "dark", overrides: map[string]string{"ahaed": "1"}, dark: dark, wantErr: true},
+		{name: "dark", overrides: map[string]string{"ahead": "orange"}, dark: dark, wantErr: true},
+		{name: "dark", overrides: map[string]string{"ahead": "256"}, dark: dark, wantErr: true},
+	}
+	for _, tt := range tests {
+		th, err := Resolve(tt.name, custom, tt.overrides, tt.dark)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Resolve(%q, %v) error = %v, wantErr %v", tt.name, tt.overrides, err, tt.wantErr)
+			continue
+		}
+		if err == nil && th[tt.role] != tt.want {
+			t.Errorf("Resolve(%q, %v)[%s] = %q, want %q", tt.name, tt.overrides, tt.role, th[tt.role], tt.want)
+		}
+	}
+}
+
+func TestResolve_NoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	th, err := Resolve("dark", nil, map[string]string{"ahead": "1"}, func() bool { return true })
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if len(th) != 0 {
+		t.Errorf("Resolve() with NO_COLOR = %v, want no colors", th)
+	}
+}
+
+func TestCheck_IgnoresNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	if err := Check("solarized", nil, nil); err == nil {
+		t.Error("Check() should reject an unknown theme even with NO_COLOR")
+	}
+	if err := Check("", map[string]Def{"x": {Colors: map[string]string{"nope": "1"}}}, nil); err == nil {
+		t.Error("Check() should reject an unused custom theme with an unknown role")
+	}
+	if err := Check("colorblind", nil, map[string]string{"added": "#0072B2"}); err != nil {
+		t.Errorf("Check() error = %v", err)
+	}
+}
+
+func TestDarkFromEnv(t *testing.T) {
+	tests := []struct {
+		val      string
+		dark, ok bool
+	}{
+		{"15;0", true, true},
+		{"0;15", false, true},
+		{"12;default;8", true, true},
+		{"0;7", false, true},
+		{"", false, false},
+		{"15;default", false, false},
+	}
+	for _, tt := range tests {
+		t.Setenv("COLORFGBG", tt.val)
+		dark, ok := DarkFromEnv()
+		if dark != tt.dark || ok != tt.ok {
+			t.Errorf("DarkFromEnv() with %q = %v, %v; want %v, %v", tt.val, dark, ok, tt.dark, tt.ok)
+		}
+	}
+}
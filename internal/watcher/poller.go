@@ -5,9 +5,9 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"maps"
 	"os/exec"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -18,8 +18,12 @@ type Poller struct {
 	events   chan Event
 	repos    map[string]string // path -> hash of last git status output
 	mu       sync.RWMutex
-}
+	lastPoll atomic.Int64 // duration of the last poll cycle, in nanoseconds
 
+	// Per-repo interval overrides and when each repo was last polled
+	intervals map[string]time.Duration
+	polledAt  map[string]time.Time
+}
 type Event struct {
 	RepoPath     string
 	Time         time.Time
@@ -34,6 +38,9 @@ func NewPoller(interval time.Duration) *Poller {
 		interval: interval,
 		events:   make(chan Event, 100),
 		repos:    make(map[string]string),
+
+		intervals: make(map[string]time.Duration),
+		polledAt:  make(map[string]time.Time),
 	}
 }
 
@@ -68,10 +75,40 @@ func (p *Poller) Unwatch(repoPath string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	delete(p.repos, repoPath)
+	delete(p.intervals, repoPath)
+	delete(p.polledAt, repoPath)
+}
+
+// SetInterval polls repoPath every d instead of the global interval. A
+// zero d restores the global interval.
+func (p *Poller) SetInterval(repoPath string, d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch {
+	case d <= 0:
+		delete(p.intervals, repoPath)
+	case d < time.Second:
+		p.intervals[repoPath] = time.Second
+	default:
+		p.intervals[repoPath] = d
+	}
+}
+
+// tick returns how often the poll loop must wake up: the shortest of the
+// global interval and the overrides.
+func (p *Poller) tick() time.Duration {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	d := p.interval
+	for _, iv := range p.intervals {
+		d = min(d, iv)
+	}
+	return d
 }
 
 func (p *Poller) Run(ctx context.Context) {
-	ticker := time.NewTicker(p.interval)
+	tick := p.tick()
+	ticker := time.NewTicker(tick)
 	defer ticker.Stop()
 
 	for {
@@ -79,17 +116,44 @@ func (p *Poller) Run(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			p.poll(ctx)
+			p.poll(ctx, tick)
+			// Overrides may have changed since the last cycle
+			if t := p.tick(); t != tick {
+				tick = t
+				ticker.Reset(tick)
+			}
 		}
 	}
 }
 
-func (p *Poller) poll(ctx context.Context) {
-	// Snapshot current repos and hashes under the lock
-	p.mu.RLock()
+// LastPollDuration reports how long the most recent poll cycle took.
+func (p *Poller) LastPollDuration() time.Duration {
+	return time.Duration(p.lastPoll.Load())
+}
+
+// poll checks the repos that are due, tick being the time since the
+// previous cycle.
+func (p *Poller) poll(ctx context.Context, tick time.Duration) {
+	start := time.Now()
+	defer func() { p.lastPoll.Store(int64(time.Since(start))) }()
+
+	// Snapshot the due repos and their hashes under the lock. A repo is due
+	// once its interval has nearly elapsed, so ticker jitter does not push
+	// it back a whole cycle.
+	p.mu.Lock()
 	snapshot := make(map[string]string, len(p.repos))
-	maps.Copy(snapshot, p.repos)
-	p.mu.RUnlock()
+	for path, hash := range p.repos {
+		iv, ok := p.intervals[path]
+		if !ok {
+			iv = p.interval
+		}
+		if start.Sub(p.polledAt[path]) < iv-tick/2 {
+			continue
+		}
+		p.polledAt[path] = start
+		snapshot[path] = hash
+	}
+	p.mu.Unlock()
 
 	// Poll repos concurrently with a semaphore
 	type change struct {
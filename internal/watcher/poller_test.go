@@ -2,6 +2,7 @@ package watcher
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -142,7 +143,7 @@ func TestPoller_DetectsChanges(t *testing.T) {
 
 	// Trigger a poll manually
 	ctx := context.Background()
-	p.poll(ctx)
+	p.poll(ctx, p.interval)
 
 	select {
 	case ev := <-p.events:
@@ -172,7 +173,7 @@ func TestPoller_NoEventWhenUnchanged(t *testing.T) {
 
 	// Poll without making changes
 	ctx := context.Background()
-	p.poll(ctx)
+	p.poll(ctx, p.interval)
 
 	select {
 	case ev := <-p.events:
@@ -182,6 +183,66 @@ func TestPoller_NoEventWhenUnchanged(t *testing.T) {
 	}
 }
 
+func TestPoller_SetInterval(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	fast, slow := t.TempDir(), t.TempDir()
+	initGitRepo(t, fast)
+	initGitRepo(t, slow)
+
+	p := NewPoller(time.Minute)
+	for _, dir := range []string{fast, slow} {
+		if err := p.Watch(dir); err != nil {
+			t.Fatalf("Watch() error = %v", err)
+		}
+	}
+	p.SetInterval(fast, 10*time.Second)
+	p.SetInterval(slow, time.Hour)
+	if got := p.tick(); got != 10*time.Second {
+		t.Errorf("tick() = %v, want 10s", got)
+	}
+
+	changed := func() map[string]bool {
+		for _, dir := range []string{fast, slow} {
+			name := filepath.Join(dir, fmt.Sprintf("f%d", time.Now().UnixNano()))
+			if err := os.WriteFile(name, nil, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		p.poll(context.Background(), p.tick())
+		got := make(map[string]bool)
+		for {
+			select {
+			case ev := <-p.events:
+				got[ev.RepoPath] = true
+			default:
+				return got
+			}
+		}
+	}
+
+	// Every repo is due on the first poll
+	if got := changed(); !got[fast] || !got[slow] {
+		t.Fatalf("first poll events = %v, want both repos", got)
+	}
+
+	// Once its interval has passed only the fast repo is polled again
+	p.mu.Lock()
+	p.polledAt[fast] = p.polledAt[fast].Add(-10 * time.Second)
+	p.mu.Unlock()
+	if got := changed(); !got[fast] || got[slow] {
+		t.Errorf("second poll events = %v, want only %s", got, fast)
+	}
+
+	p.SetInterval(fast, 0)
+	p.SetInterval(slow, 0)
+	if got := p.tick(); got != time.Minute {
+		t.Errorf("tick() after reset = %v, want 1m", got)
+	}
+}
+
 func TestPoller_Close(t *testing.T) {
 	p := NewPoller(time.Second)
 	if err := p.Close(); err != nil {
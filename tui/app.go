@@ -5,17 +5,26 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/jackchuka/gv/internal/cache"
 	"github.com/jackchuka/gv/internal/config"
+	"github.com/jackchuka/gv/internal/daemon"
+	"github.com/jackchuka/gv/internal/forge"
 	"github.com/jackchuka/gv/internal/model"
+	"github.com/jackchuka/gv/internal/notify"
+	"github.com/jackchuka/gv/internal/query"
 	"github.com/jackchuka/gv/internal/scanner"
+	"github.com/jackchuka/gv/internal/state"
 	"github.com/jackchuka/gv/internal/status"
 	"github.com/jackchuka/gv/internal/watcher"
 )
@@ -36,6 +45,7 @@ const (
 	ViewDirty
 	ViewUnpushed
 	ViewConflicts
+	ViewPinned
 )
 
 type SortMode int
@@ -44,6 +54,7 @@ const (
 	SortAlpha SortMode = iota
 	SortDiff
 	SortChurn
+	SortColumn // by sortColumn, clicked in the table header
 )
 
 type ToastLevel int
@@ -62,7 +73,8 @@ type Toast struct {
 }
 
 type TableRow struct {
-	Repo *model.Repository
+	Repo  *model.Repository
+	Match []int // positions in the display name matched by the quick-jump finder
 }
 
 type AnimState struct {
@@ -78,31 +90,14 @@ func newAnimState() AnimState {
 	}
 }
 
-type SummaryData struct {
-	TotalRepos    int
-	DirtyRepos    int
-	AheadRepos    int
-	BehindRepos   int
-	InSyncRepos   int
-	ConflictRepos int
-
-	TotalStaged    int
-	TotalModified  int
-	TotalUntracked int
-
-	TotalAdded   int
-	TotalDeleted int
-	TotalNet     int
-
-	DailyCommits [7]int
-}
-
 type Model struct {
 	cfg    *config.Config
 	repos  []model.Repository
 	rows   []TableRow
 	cursor int
 
+	repoIndex map[string]int // path -> index into repos, rebuilt with the rows
+
 	width, height int
 	scrollOffset  int
 
@@ -110,49 +105,141 @@ type Model struct {
 	filterMode  bool
 	filterInput textinput.Model
 	filterText  string
-	viewFilter  ViewFilter
-	sortMode    SortMode
-	showHelp    bool
-	showDetail  bool
-	cdPath      string
-
-	summary SummaryData
-	anim    AnimState
-	toasts  []Toast
+	filterQuery *query.Query // parsed filterText; the last valid one while filterErr is set
+	filterErr   error
+	historyPos  int // index into state.History while browsing it; len(History) for the input
+	views       []savedView
+	activeView  int // index into views, -1 for none
+
+	viewConflicts []string // saved view keys that clash with other bindings
+	tagMode       bool     // editing the tags of tagTarget
+	tagInput      textinput.Model
+	tagTarget     string
+	showTags      bool // tag picker open
+	tagCursor     int
+	jumpMode      bool // quick-jump prompt open
+	jumpInput     textinput.Model
+	jumpReturn    string // repo selected before jumping, restored on escape
+	paletteMode   bool   // command palette open
+	paletteInput  textinput.Model
+	paletteCursor int
+	paletteRows   []paletteMatch
+	viewFilter    ViewFilter
+	sortMode      SortMode
+	sortColumn    string // column sorted by in SortColumn
+	sortReverse   bool   // against the column's natural order
+	showHelp      bool
+	showScan      bool
+	showDetail    bool
+	cdPath        string
+	presets       []config.ColumnPreset
+	preset        int // index into presets
+
+	summary    model.Summary
+	scanResult *scanner.ScanResult // last completed scan
+	scanID     int                 // identifies the scan in flight; older results are dropped
+	scanFound  int                 // repos streamed by the scan in flight
+	anim       AnimState
+	toasts     []Toast
 
 	scanner     scanner.Scanner
 	reader      status.Reader
 	watcher     watcher.RepoWatcher
 	watchCancel context.CancelFunc
-
-	keys        keyMap
-	fetchTarget string
-	diffLoading bool
-	nextToastID int
-	animRunning bool
+	notifier    *notify.Notifier
+
+	state          *state.State
+	statePath      string              // where state is saved; empty to keep it in memory
+	pinnedByConfig map[string]bool     // repos pinned in the config, which the TUI cannot unpin
+	configTags     map[string][]string // tags from the config, which the TUI cannot remove
+
+	keys          keyMap
+	keySeq        []string // keys of an unfinished sequence, e.g. the first g of gg
+	layout        layout   // where View last drew things, for the mouse
+	lastClick     click
+	detailScroll  int
+	detailPath    string // repo detailScroll belongs to
+	detailTab     detailTab
+	detailFocus   bool // navigation keys scroll the detail panel
+	detailPct     int  // detail panel share of the width
+	details       map[string]*model.RepoDetail
+	detailLoading map[string]bool
+	detailGen     map[string]int        // bumped when a repo's detail goes stale
+	pulls         map[string]pullResult // pull request and checks by repo path
+	pullsLoading  map[string]bool
+	pullsTicking  bool // a pullsStaleMsg is on its way
+	pullCache     *forge.Cache
+	fetchTarget   string
+	diffLoading   bool
+	nextToastID   int
+	animRunning   bool
 }
 
 func NewModel(cfg *config.Config) *Model {
 	ti := textinput.New()
-	ti.Placeholder = "filter repos..."
+	ti.Placeholder = "filter repos, #tag..."
 	ti.CharLimit = 50
 
+	tags := textinput.New()
+	tags.Prompt = "tags: "
+	tags.Placeholder = "space-separated tags..."
+	tags.CharLimit = 200
+
+	jump := textinput.New()
+	jump.Prompt = "jump: "
+	jump.Placeholder = "fuzzy name, path, branch..."
+	jump.CharLimit = 50
+
+	palette := textinput.New()
+	palette.Prompt = "> "
+	palette.Placeholder = "run a command..."
+	palette.CharLimit = 50
+
+	keys := newKeyMap(cfg.Keys)
+
 	var w watcher.RepoWatcher
 	if cfg.AutoRefresh {
 		w = watcher.NewPoller(cfg.PollInterval)
 	}
 
+	views, conflicts := newSavedViews(cfg.Views, keys)
+
+	presets := cfg.Columns
+	if len(presets) == 0 {
+		presets = builtinPresets
+	}
+
 	return &Model{
-		cfg:         cfg,
-		keys:        newKeyMap(),
-		scanner:     scanner.NewWalker(cfg),
-		reader:      status.NewGitReader(),
-		filterInput: ti,
-		viewFilter:  ViewAll,
-		sortMode:    SortAlpha,
-		showDetail:  true,
-		watcher:     w,
-		anim:        newAnimState(),
+		cfg:           cfg,
+		keys:          keys,
+		repoIndex:     make(map[string]int),
+		scanner:       scanner.NewWalker(cfg),
+		reader:        status.NewGitReader(),
+		filterInput:   ti,
+		tagInput:      tags,
+		jumpInput:     jump,
+		paletteInput:  palette,
+		viewFilter:    ViewAll,
+		sortMode:      SortAlpha,
+		showDetail:    true,
+		detailPct:     defaultDetailPct,
+		details:       make(map[string]*model.RepoDetail),
+		detailLoading: make(map[string]bool),
+		detailGen:     make(map[string]int),
+		pulls:         make(map[string]pullResult),
+		pullsLoading:  make(map[string]bool),
+		pullCache:     forge.NewCache(pullTTL),
+		presets:       presets,
+		watcher:       w,
+		anim:          newAnimState(),
+
+		state:          &state.State{},
+		pinnedByConfig: make(map[string]bool),
+		configTags:     make(map[string][]string),
+
+		views:         views,
+		activeView:    -1,
+		viewConflicts: conflicts,
 	}
 }
 
@@ -167,6 +254,10 @@ func (m *Model) Init() tea.Cmd {
 		func() tea.Msg { return animTickMsg{} },
 	}
 
+	for _, c := range m.viewConflicts {
+		cmds = append(cmds, m.addToast(c, ToastError))
+	}
+
 	if m.watcher != nil {
 		cmds = append(cmds, m.startWatcher())
 	}
@@ -174,7 +265,16 @@ func (m *Model) Init() tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
-type reposLoadedMsg struct{ repos []model.Repository }
+type repoFoundMsg struct {
+	scanID int
+	repo   model.Repository
+	next   tea.Cmd // waits for the next repo or the end of the scan
+}
+type scanDoneMsg struct {
+	scanID int
+	result *scanner.ScanResult
+	err    error
+}
 type statusUpdatedMsg struct{ statuses map[string]*model.RepoStatus }
 type diffStatsLoadedMsg struct{ stats map[string]*model.DiffStats }
 type fetchCompletedMsg struct {
@@ -195,18 +295,30 @@ type animTickMsg struct{}
 type toastExpiredMsg struct{ id int }
 
 func (m *Model) buildRows() {
+	clear(m.repoIndex)
+	for i, r := range m.repos {
+		m.repoIndex[r.Path] = i
+	}
+
+	if m.jumpMode {
+		m.buildJumpRows()
+		return
+	}
+
 	filtered := filterRepos(m.repos, m.viewFilter)
 
-	// Text filter
-	if m.filterText != "" {
-		var textFiltered []model.Repository
+	// Saved view and filter query
+	for _, q := range []*query.Query{m.activeQuery(), m.filterQuery} {
+		if q.Empty() {
+			continue
+		}
+		var matched []model.Repository
 		for _, r := range filtered {
-			if containsIgnoreCase(r.DisplayName(), m.filterText) ||
-				containsIgnoreCase(r.Path, m.filterText) {
-				textFiltered = append(textFiltered, r)
+			if q.Match(&r) {
+				matched = append(matched, r)
 			}
 		}
-		filtered = textFiltered
+		filtered = matched
 	}
 
 	// Sort
@@ -229,9 +341,33 @@ func (m *Model) buildRows() {
 			}
 			return filtered[i].DisplayName() < filtered[j].DisplayName()
 		})
+	case SortColumn:
+		sort.SliceStable(filtered, func(i, j int) bool {
+			a, b := &filtered[i], &filtered[j]
+			switch {
+			case a.Status == nil && b.Status == nil:
+				return a.DisplayName() < b.DisplayName()
+			case a.Status == nil || b.Status == nil:
+				return b.Status == nil // still loading: last either way
+			}
+			c := m.columnOrder(m.sortColumn, a, b)
+			if m.sortReverse {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+			return a.DisplayName() < b.DisplayName()
+		})
 	default:
 		sortRepos(filtered)
 	}
+	if m.sortMode != SortAlpha {
+		// sortRepos puts pinned repos first on its own
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return filtered[i].Pinned && !filtered[j].Pinned
+		})
+	}
 
 	// Build flat row list
 	rows := make([]TableRow, len(filtered))
@@ -250,51 +386,146 @@ func (m *Model) buildRows() {
 	}
 }
 
+// buildJumpRows lists every repo matching the quick-jump pattern, ignoring
+// views and filters, best match first.
+func (m *Model) buildJumpRows() {
+	repos := slices.Clone(m.repos)
+	sortRepos(repos)
+
+	matches := rankRepos(repos, m.jumpInput.Value(), m.state.Used, time.Now())
+	m.rows = make([]TableRow, len(matches))
+	for i, jm := range matches {
+		m.rows[i] = TableRow{Repo: jm.repo, Match: jm.name}
+	}
+	m.cursor = min(m.cursor, max(len(m.rows)-1, 0))
+}
+
+// setStatus stores a new status for m.repos[i] and returns the state
+// transitions it caused, for dispatching to notification sinks.
+func (m *Model) setStatus(i int, s *model.RepoStatus) []notify.Event {
+	events := notify.Detect(&m.repos[i], m.repos[i].Status, s)
+	if old := m.repos[i].Status; old != nil && s != nil && len(s.Aliases) == 0 {
+		s.Aliases = old.Aliases // keep showing them until they are rerun
+	}
+	m.repos[i].Status = s
+	m.repos[i].LastScanned = time.Now()
+	return events
+}
+
+func (m *Model) dispatchNotifications(events []notify.Event) tea.Cmd {
+	if m.notifier == nil || len(events) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := m.notifier.Dispatch(ctx, events); err != nil {
+			return errMsg{fmt.Errorf("notify: %w", err)}
+		}
+		return nil
+	}
+}
+
 func (m *Model) refresh() {
 	m.computeSummary()
 	m.buildRows()
 }
 
-func (m *Model) computeSummary() {
-	s := SummaryData{}
+// applyState adds the pin and tags kept in the state to repo, remembering
+// which of them came from the config.
+func (m *Model) applyState(repo *model.Repository) {
+	if repo.Pinned {
+		m.pinnedByConfig[repo.Path] = true
+	} else {
+		repo.Pinned = m.state.IsPinned(repo.Path)
+	}
 
-	for _, r := range m.repos {
-		s.TotalRepos++
-		if r.Status == nil {
-			continue
-		}
-		if r.Status.IsDirty() {
-			s.DirtyRepos++
-		}
-		if r.Status.Ahead > 0 {
-			s.AheadRepos++
-		}
-		if r.Status.Behind > 0 {
-			s.BehindRepos++
-		}
-		if r.Status.Remote != "" && !r.Status.IsDirty() && r.Status.Ahead == 0 && r.Status.Behind == 0 {
-			s.InSyncRepos++
+	if len(repo.Tags) > 0 {
+		m.configTags[repo.Path] = repo.Tags
+	}
+	repo.Tags = m.mergedTags(repo.Path)
+}
+
+// mergedTags returns the config tags of the repo at path followed by those
+// from the state that differ from them.
+func (m *Model) mergedTags(path string) []string {
+	tags := slices.Clone(m.configTags[path])
+	for _, t := range m.state.TagsOf(path) {
+		if !slices.ContainsFunc(tags, func(c string) bool { return strings.EqualFold(c, t) }) {
+			tags = append(tags, t)
 		}
-		if r.Status.HasSpecialState() {
-			s.ConflictRepos++
+	}
+	return tags
+}
+
+// editTags starts editing the tags of the repo at path. Only tags from the
+// state are editable; config tags are shown in the placeholder.
+func (m *Model) editTags(path string) tea.Cmd {
+	m.tagMode = true
+	m.tagTarget = path
+	m.tagInput.SetValue(strings.Join(m.state.TagsOf(path), " "))
+	m.tagInput.Placeholder = "space-separated tags..."
+	if fixed := m.configTags[path]; len(fixed) > 0 {
+		m.tagInput.Placeholder = "config: " + strings.Join(fixed, " ")
+	}
+	m.tagInput.CursorEnd()
+	return m.tagInput.Focus()
+}
+
+// saveTags stores the edited tags of tagTarget and saves the state.
+func (m *Model) saveTags() tea.Cmd {
+	path := m.tagTarget
+	m.state.SetTags(path, strings.FieldsFunc(m.tagInput.Value(), func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	}))
+	tags := m.mergedTags(path)
+	for i := range m.repos {
+		if m.repos[i].Path == path {
+			m.repos[i].Tags = tags
 		}
+	}
+	m.buildRows()
+	m.selectPath(path)
 
-		s.TotalStaged += r.Status.Staged
-		s.TotalModified += r.Status.Modified
-		s.TotalUntracked += r.Status.Untracked
+	return m.saveState()
+}
 
-		if r.Diff != nil {
-			s.TotalAdded += r.Diff.TotalAdded
-			s.TotalDeleted += r.Diff.TotalDeleted
+// togglePin pins or unpins the repo at path and saves the state.
+func (m *Model) togglePin(path string) tea.Cmd {
+	if m.pinnedByConfig[path] {
+		return m.addToast("Pinned in config", ToastInfo)
+	}
+	pinned := m.state.TogglePin(path)
+	for i := range m.repos {
+		if m.repos[i].Path == path {
+			m.repos[i].Pinned = pinned
+		}
+	}
+	m.buildRows()
+	m.selectPath(path)
 
-			for i := 0; i < 7; i++ {
-				s.DailyCommits[i] += r.Diff.DailyCommits[i]
-			}
+	if cmd := m.saveState(); cmd != nil {
+		return cmd
+	}
+	if pinned {
+		return m.addToast("Pinned", ToastSuccess)
+	}
+	return m.addToast("Unpinned", ToastInfo)
+}
+
+// selectPath moves the cursor to the row of the repo at path, if shown.
+func (m *Model) selectPath(path string) {
+	for i, row := range m.rows {
+		if row.Repo != nil && row.Repo.Path == path {
+			m.cursor = i
+			return
 		}
 	}
+}
 
-	s.TotalNet = s.TotalAdded - s.TotalDeleted
-	m.summary = s
+func (m *Model) computeSummary() {
+	m.summary = model.Summarize(m.repos)
 }
 
 func (m *Model) selectedRepo() *model.Repository {
@@ -352,16 +583,35 @@ func (m *Model) ensureAnimTick() tea.Cmd {
 	return m.animTick()
 }
 
+// loadRepos starts a scan in the background. Repos are delivered one by
+// one as repoFoundMsg so rows appear while the scan runs, followed by a
+// scanDoneMsg with the complete result.
 func (m *Model) loadRepos() tea.Cmd {
-	return func() tea.Msg {
+	m.scanID++
+	m.scanFound = 0
+	id := m.scanID
+	found := make(chan model.Repository, 32)
+	done := make(chan scanDoneMsg, 1)
+	sc := m.scanner
+
+	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		repos, err := m.scanner.Scan(ctx)
-		if err != nil {
-			return errMsg{err}
+		res, err := sc.ScanStream(ctx, found)
+		close(found)
+		done <- scanDoneMsg{scanID: id, result: res, err: err}
+	}()
+
+	return nextScanEvent(id, found, done)
+}
+
+func nextScanEvent(id int, found <-chan model.Repository, done <-chan scanDoneMsg) tea.Cmd {
+	return func() tea.Msg {
+		if r, ok := <-found; ok {
+			return repoFoundMsg{scanID: id, repo: r, next: nextScanEvent(id, found, done)}
 		}
-		return reposLoadedMsg{repos}
+		return <-done
 	}
 }
 
@@ -446,9 +696,11 @@ func (m *Model) fetchAllRepos() tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 		defer cancel()
 
-		paths := make([]string, len(m.repos))
-		for i, r := range m.repos {
-			paths[i] = r.Path
+		var paths []string
+		for _, r := range m.repos {
+			if !r.FetchDisabled {
+				paths = append(paths, r.Path)
+			}
 		}
 
 		statuses, errors := m.reader.FetchBatch(ctx, paths)
@@ -497,20 +749,26 @@ func (m *Model) openEditor(path string) tea.Cmd {
 
 func (m *Model) openFinder(path string) tea.Cmd {
 	return func() tea.Msg {
-		var cmd *exec.Cmd
-		switch runtime.GOOS {
-		case "darwin":
-			cmd = exec.Command("open", path)
-		case "windows":
-			cmd = exec.Command("explorer", path)
-		default:
-			cmd = exec.Command("xdg-open", path)
-		}
-		_ = cmd.Run()
+		_ = openExternal(path)
 		return nil
 	}
 }
 
+// openExternal opens a directory or URL with the desktop's default
+// application.
+func openExternal(target string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", target)
+	case "windows":
+		cmd = exec.Command("explorer", target)
+	default:
+		cmd = exec.Command("xdg-open", target)
+	}
+	return cmd.Run()
+}
+
 func (m *Model) copyToClipboard(text string) tea.Cmd {
 	return func() tea.Msg {
 		var cmd *exec.Cmd
@@ -539,6 +797,12 @@ func filterRepos(repos []model.Repository, filter ViewFilter) []model.Repository
 	}
 	var filtered []model.Repository
 	for _, r := range repos {
+		if filter == ViewPinned {
+			if r.Pinned {
+				filtered = append(filtered, r)
+			}
+			continue
+		}
 		if r.Status == nil {
 			continue
 		}
@@ -560,27 +824,91 @@ func filterRepos(repos []model.Repository, filter ViewFilter) []model.Repository
 	return filtered
 }
 
+// sortRepos orders repos by path, pinned ones first, listing worktrees and
+// submodules right after the repo they belong to, sorted by name.
 func sortRepos(repos []model.Repository) {
-	sort.Slice(repos, func(i, j int) bool {
-		keyi := repos[i].Path
-		if repos[i].IsWorktree && repos[i].MainWorktree != "" {
-			keyi = repos[i].MainWorktree
+	byPath := make(map[string]*model.Repository, len(repos))
+	for i := range repos {
+		byPath[repos[i].Path] = &repos[i]
+	}
+	keys := make(map[string]string, len(repos))
+	var key func(r *model.Repository, depth int) string
+	key = func(r *model.Repository, depth int) string {
+		if k, ok := keys[r.Path]; ok {
+			return k
 		}
-		keyj := repos[j].Path
-		if repos[j].IsWorktree && repos[j].MainWorktree != "" {
-			keyj = repos[j].MainWorktree
+		// Pinned repos come first, their worktrees and submodules with them
+		k := "1" + r.Path
+		if r.Pinned {
+			k = "0" + r.Path
 		}
-		if keyi == keyj {
-			if !repos[i].IsWorktree && repos[j].IsWorktree {
-				return true
+		if parent := r.ParentPath(); parent != "" {
+			pk := parent
+			// depth guards against a repo that claims an ancestor as child
+			if p, ok := byPath[parent]; ok && depth < 16 {
+				pk = key(p, depth+1)
 			}
-			if repos[i].IsWorktree && !repos[j].IsWorktree {
-				return false
+			k = pk + "\x00" + r.DisplayName()
+		}
+		keys[r.Path] = k
+		return k
+	}
+	for i := range repos {
+		key(&repos[i], 0)
+	}
+	sort.SliceStable(repos, func(i, j int) bool {
+		return keys[repos[i].Path] < keys[repos[j].Path]
+	})
+}
+
+// allTags counts the repos carrying each tag, most used first.
+func allTags(repos []model.Repository) []tagCount {
+	counts := make(map[string]int)
+	var names []string
+	for _, r := range repos {
+		for _, t := range r.Tags {
+			key := strings.ToLower(t)
+			if counts[key] == 0 {
+				names = append(names, key)
 			}
-			return repos[i].DisplayName() < repos[j].DisplayName()
+			counts[key]++
 		}
-		return keyi < keyj
+	}
+	tags := make([]tagCount, len(names))
+	for i, name := range names {
+		tags[i] = tagCount{name, counts[name]}
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].count != tags[j].count {
+			return tags[i].count > tags[j].count
+		}
+		return tags[i].tag < tags[j].tag
 	})
+	return tags
+}
+
+type tagCount struct {
+	tag   string
+	count int
+}
+
+// submoduleState returns how the superproject of repo sees it. ok is false
+// when repo is not a submodule or the superproject's status is not loaded.
+func (m *Model) submoduleState(repo *model.Repository) (state model.SubmoduleState, ok bool) {
+	if !repo.IsSubmodule() {
+		return state, false
+	}
+	i, found := m.repoIndex[repo.Superproject]
+	if !found || i >= len(m.repos) || m.repos[i].Path != repo.Superproject || m.repos[i].Status == nil {
+		return state, false
+	}
+	super := &m.repos[i]
+	rel, err := filepath.Rel(super.Path, repo.Path)
+	if err != nil {
+		return state, false
+	}
+	// Absent entries are at the recorded commit and clean
+	return super.Status.Submodules[filepath.ToSlash(rel)], true
 }
 
 func diffVolume(r *model.Repository) int {
@@ -601,32 +929,47 @@ func churnTotal(r *model.Repository) int {
 	return total
 }
 
-func containsIgnoreCase(s, substr string) bool {
-	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
-}
-
-func specialStateLabel(s *model.RepoStatus) string {
-	if s.MergeHead {
-		return "MERGE"
-	}
-	if s.RebaseHead {
-		return "REBASE"
-	}
-	if s.CherryPick {
-		return "CHERRY-PICK"
-	}
-	if s.Reverting {
-		return "REVERT"
+func Run(cfg *config.Config) error {
+	notifier, err := notify.New(cfg.Notifications)
+	if err != nil {
+		return err
 	}
-	if s.Bisecting {
-		return "BISECT"
+
+	t, err := ResolveTheme(cfg)
+	if err != nil {
+		return err
 	}
-	return ""
-}
+	setTheme(t)
 
-func Run(cfg *config.Config) error {
 	m := NewModel(cfg)
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	m.notifier = notifier
+
+	// Pins and tags are a convenience: start without them rather than not at all
+	m.statePath = state.DefaultPath()
+	if st, err := state.Load(m.statePath); err == nil {
+		m.state = st
+	}
+
+	// Become a thin client when a daemon is running; it owns polling
+	// and notifications.
+	connectCtx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	client, err := daemon.Connect(connectCtx, cfg.SocketPath())
+	cancel()
+	if err == nil {
+		defer client.CloseIdleConnections()
+		m.scanner = client
+		m.reader = client
+		m.notifier = nil
+		if m.watcher != nil {
+			m.watcher = daemon.NewWatcher(client)
+		}
+	}
+
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if cfg.Mouse {
+		opts = append(opts, tea.WithMouseCellMotion())
+	}
+	p := tea.NewProgram(m, opts...)
 	result, err := p.Run()
 
 	// Cleanup
@@ -641,6 +984,11 @@ func Run(cfg *config.Config) error {
 		return err
 	}
 
+	// Leave a snapshot for gv prompt; a daemon keeps its own up to date
+	if client == nil && len(m.repos) > 0 {
+		_ = cache.Save(cache.DefaultPath(), m.repos)
+	}
+
 	// cd action
 	if mdl, ok := result.(*Model); ok && mdl.cdPath != "" {
 		fmt.Println(mdl.cdPath)
@@ -0,0 +1,30 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/jackchuka/gv/internal/config"
+	"github.com/jackchuka/gv/internal/model"
+)
+
+func TestBuildRows_SortColumnWithoutStatus(t *testing.T) {
+	for _, column := range []string{"branch", "age", "sync"} {
+		m := NewModel(config.NewConfig())
+		m.sortMode, m.sortColumn = SortColumn, column
+		m.repos = []model.Repository{
+			{Path: "/c", Name: "c"}, // not loaded yet
+			{Path: "/b", Name: "b", Status: &model.RepoStatus{Branch: "main"}},
+			{Path: "/a", Name: "a"}, // not loaded yet
+		}
+
+		m.buildRows()
+
+		var got []string
+		for _, r := range m.rows {
+			got = append(got, r.Repo.Name)
+		}
+		if len(got) != 3 || got[0] != "b" || got[1] != "a" || got[2] != "c" {
+			t.Errorf("sorted by %s: rows = %v, want [b a c]", column, got)
+		}
+	}
+}
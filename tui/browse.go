@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jackchuka/gv/internal/forge"
+	"github.com/jackchuka/gv/internal/model"
+)
+
+// browsedMsg reports a page opened in the web browser, or why it wasn't.
+type browsedMsg struct {
+	url string
+	err error
+}
+
+// browse opens page of the selected repo in the web browser, on the forge
+// of the remote its branch tracks. The remotes come from the detail panel,
+// or are read when it hasn't loaded them.
+func (m *Model) browse(page forge.Page) tea.Cmd {
+	repo := m.selectedRepo()
+	if repo == nil {
+		return nil
+	}
+	r := *repo
+	detail := m.details[r.Path]
+	reader, defs := m.reader, m.cfg.Forges
+	return tea.Batch(func() tea.Msg {
+		if detail == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			detail = reader.GetDetail(ctx, r.Path)
+		}
+		url, err := browseURL(page, r, detail.Remotes, defs)
+		if err == nil {
+			err = openExternal(url)
+		}
+		return browsedMsg{url: url, err: err}
+	}, m.touch(r.Path))
+}
+
+// browseURL returns the address of page for repo on the forge of its
+// remote.
+func browseURL(page forge.Page, repo model.Repository, remotes []model.Remote, defs map[string]forge.Def) (string, error) {
+	remote, branch := browseRemote(repo, remotes)
+	if remote == nil {
+		return "", errors.New("no remotes")
+	}
+	if remote.Host == "" {
+		return "", fmt.Errorf("remote %s is not hosted: %s", remote.Name, remote.FetchURL)
+	}
+	f, ok := forge.For(remote.Host, defs)
+	if !ok {
+		return "", fmt.Errorf("%s: unknown web UI; add the host under forges in the config", remote.Host)
+	}
+
+	commit := ""
+	if s := repo.Status; s != nil && !strings.HasPrefix(s.CommitHash, "(") { // "(initial)" before the first commit
+		commit = s.CommitHash
+	}
+	return f.URL(page, forge.Target{
+		Owner:   remote.Owner,
+		Repo:    remote.Repo,
+		Branch:  branch,
+		Default: remote.DefaultBranch,
+		Commit:  commit,
+	})
+}
+
+// browseRemote picks the remote the current branch tracks, else the repo's
+// configured remote, origin or the first one. It returns the branch's name
+// on that remote.
+func browseRemote(repo model.Repository, remotes []model.Remote) (*model.Remote, string) {
+	var branch, tracking string
+	if s := repo.Status; s != nil {
+		branch, tracking = s.Branch, s.Remote
+	}
+
+	var best *model.Remote
+	for i, r := range remotes {
+		// Remote names may contain slashes; the longest match wins
+		if rest, ok := strings.CutPrefix(tracking, r.Name+"/"); ok && (best == nil || len(r.Name) > len(best.Name)) {
+			best, branch = &remotes[i], rest
+		}
+	}
+	if best != nil {
+		return best, branch
+	}
+
+	for _, name := range []string{repo.DefaultRemote, "origin"} {
+		for i, r := range remotes {
+			if name != "" && r.Name == name {
+				return &remotes[i], branch
+			}
+		}
+	}
+	if len(remotes) > 0 {
+		return &remotes[0], branch
+	}
+	return nil, branch
+}
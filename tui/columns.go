@@ -0,0 +1,401 @@
+package tui
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jackchuka/gv/internal/config"
+	"github.com/jackchuka/gv/internal/model"
+)
+
+// builtinPresets are used when the config has no columns section.
+var builtinPresets = []config.ColumnPreset{
+	{Name: "default", Columns: columnList("repo", "branch", "sync", "changes", "diff")},
+	{Name: "narrow", Columns: columnList("repo", "branch", "sync")},
+	{Name: "wide", Columns: columnList("repo", "branch", "sync", "changes", "diff", "age", "owner", "head")},
+}
+
+func columnList(names ...string) []config.ColumnConfig {
+	cols := make([]config.ColumnConfig, len(names))
+	for i, n := range names {
+		cols[i] = config.ColumnConfig{Name: n}
+	}
+	return cols
+}
+
+// columnDefaults are each column's share of the table width and its
+// minimum width. The default preset's shares add up to 100.
+var columnDefaults = map[string]struct{ weight, min int }{
+	"repo":     {28, 10},
+	"branch":   {25, 8},
+	"sync":     {8, 8},
+	"changes":  {18, 12},
+	"diff":     {21, 14},
+	"age":      {6, 5},
+	"owner":    {12, 8},
+	"stashes":  {6, 6},
+	"remote":   {8, 7},
+	"upstream": {18, 10},
+	"fork":     {6, 5},
+	"pr":       {8, 6},
+	"head":     {9, 9},
+	"tag":      {10, 6},
+	"week":     {6, 5},
+}
+
+// aliasDefaults apply to alias:<name> columns.
+var aliasDefaults = struct{ weight, min int }{12, 6}
+
+// tagAlias runs for the tag column. The leading colon keeps it apart from
+// configured aliases.
+const (
+	tagAlias    = ":tag"
+	tagAliasCmd = "describe --tags --abbrev=0"
+)
+
+// column is a table column laid out at a width.
+type column struct {
+	name  string // one of config.ColumnNames, or alias:<name>
+	width int
+}
+
+func (c column) alias() (string, bool) {
+	return strings.CutPrefix(c.name, config.AliasColumnPrefix)
+}
+
+func (c column) title() string {
+	if a, ok := c.alias(); ok {
+		return strings.ToUpper(a)
+	}
+	if c.name == "stashes" {
+		return "STASH"
+	}
+	return strings.ToUpper(c.name)
+}
+
+// layoutColumns shares width between cols by weight, within each column's
+// bounds. Trailing columns are dropped when the minimums don't fit.
+func layoutColumns(cols []config.ColumnConfig, width int) []column {
+	usable := max(width-2, 40) // leading space + margin
+
+	type bounds struct{ weight, min, max int }
+	bs := make([]bounds, len(cols))
+	for i, c := range cols {
+		d, ok := columnDefaults[c.Name]
+		if !ok {
+			d = aliasDefaults
+		}
+		bs[i] = bounds{d.weight, d.min, c.Max}
+		if c.Min > 0 {
+			bs[i].min = c.Min
+		}
+	}
+
+	n := len(cols)
+	for n > 1 {
+		need := 0
+		for _, b := range bs[:n] {
+			need += b.min
+		}
+		if need <= usable {
+			break
+		}
+		n--
+	}
+
+	total := 0
+	for _, b := range bs[:n] {
+		total += b.weight
+	}
+
+	out := make([]column, n)
+	freed, grow := 0, -1
+	for i, b := range bs[:n] {
+		w := usable * b.weight / total
+		if b.max > 0 && w > b.max {
+			freed += w - b.max
+			w = b.max
+		}
+		w = max(w, b.min)
+		if b.max == 0 && grow < 0 {
+			grow = i
+		}
+		out[i] = column{name: cols[i].Name, width: w}
+	}
+	// Room given up by capped columns goes to the first uncapped one
+	if grow >= 0 {
+		out[grow].width += freed
+	}
+	// Columns raised to their minimum may overflow a narrow table; take the
+	// excess back from the others, last first
+	over := -usable
+	for _, c := range out {
+		over += c.width
+	}
+	for i := n - 1; i >= 0 && over > 0; i-- {
+		take := min(over, out[i].width-bs[i].min)
+		if take > 0 {
+			out[i].width -= take
+			over -= take
+		}
+	}
+	return out
+}
+
+// columns lays out the active preset for a table width.
+func (m *Model) columns(width int) []column {
+	return layoutColumns(m.presets[m.preset].Columns, width)
+}
+
+// cyclePreset switches to the next column preset.
+func (m *Model) cyclePreset() tea.Cmd {
+	return m.setPreset((m.preset + 1) % len(m.presets))
+}
+
+// setPreset switches to column preset i, loading the aliases and pull
+// requests it shows.
+func (m *Model) setPreset(i int) tea.Cmd {
+	m.preset = i
+	paths := make([]string, len(m.repos))
+	for i, r := range m.repos {
+		paths[i] = r.Path
+	}
+	return tea.Batch(
+		m.addToast("Columns: "+m.presets[m.preset].Name, ToastInfo),
+		m.loadAliases(paths),
+		m.loadPulls(paths),
+	)
+}
+
+// presetAliases returns the git arguments of the aliases the active
+// preset shows, by name.
+func (m *Model) presetAliases() map[string]string {
+	aliases := make(map[string]string)
+	for _, c := range m.presets[m.preset].Columns {
+		if c.Name == "tag" {
+			aliases[tagAlias] = tagAliasCmd
+		} else if a, ok := strings.CutPrefix(c.Name, config.AliasColumnPrefix); ok {
+			aliases[a] = m.cfg.Aliases[a]
+		}
+	}
+	return aliases
+}
+
+type aliasesLoadedMsg struct {
+	outputs map[string]map[string]string // repo path -> alias name -> first line of output
+}
+
+// loadAliases runs the aliases shown by the active preset in each repo at
+// paths. It returns nil when the preset shows none.
+func (m *Model) loadAliases(paths []string) tea.Cmd {
+	aliases := m.presetAliases()
+	if len(aliases) == 0 || len(paths) == 0 {
+		return nil
+	}
+	reader := m.reader
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		outputs := make(map[string]map[string]string, len(paths))
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, 8)
+		for _, path := range paths {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				out := make(map[string]string, len(aliases))
+				for name, args := range aliases {
+					cmdCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+					text, err := reader.RunAlias(cmdCtx, path, args)
+					cancel()
+					if err == nil {
+						text, _, _ = strings.Cut(strings.TrimSpace(text), "\n")
+					}
+					out[name] = text // empty on failure, e.g. no tags yet
+				}
+				mu.Lock()
+				outputs[path] = out
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		return aliasesLoadedMsg{outputs}
+	}
+}
+
+// setAliases merges alias outputs into the statuses of the repos.
+func (m *Model) setAliases(outputs map[string]map[string]string) {
+	for i := range m.repos {
+		out, ok := outputs[m.repos[i].Path]
+		s := m.repos[i].Status
+		if !ok || s == nil {
+			continue
+		}
+		merged := maps.Clone(s.Aliases)
+		if merged == nil {
+			merged = make(map[string]string, len(out))
+		}
+		maps.Copy(merged, out)
+		s.Aliases = merged
+	}
+}
+
+// cell renders the value of column c for repo.
+func (m *Model) cell(r rowRenderer, c column, row TableRow, selected, parentAbove bool) string {
+	repo, s := row.Repo, row.Repo.Status
+	switch c.name {
+	case "repo":
+		return r.repoCell(repo, row.Match, c.width, selected, parentAbove)
+	case "branch":
+		return r.branchCell(s, c.width)
+	case "sync":
+		sub, _ := m.submoduleState(repo)
+		return r.syncCell(s, sub.NewCommits, c.width)
+	case "changes":
+		return r.changesCell(s, c.width)
+	case "diff":
+		return r.diffCell(repo, c.width, m.diffLoading)
+	case "pr":
+		return r.prCell(m.pulls[repo.Path], m.pullsLoading[repo.Path], c.width)
+	}
+
+	if s == nil {
+		return r.bg(styleDim).Width(c.width).Render("...")
+	}
+	style, text := styleDim, "─"
+	switch c.name {
+	case "age":
+		if !s.LastCommit.IsZero() {
+			style, text = styleFg, shortAge(time.Since(s.LastCommit))
+		}
+	case "owner":
+		if s.Owner != "" {
+			style, text = styleFg, s.Owner
+		}
+	case "stashes":
+		if s.Stashes > 0 {
+			style, text = styleAmber, fmt.Sprintf("%d", s.Stashes)
+		}
+	case "remote":
+		if remote, _, ok := strings.Cut(s.Remote, "/"); ok {
+			style, text = styleFg, remote
+		}
+	case "upstream":
+		switch {
+		case s.UpstreamGone:
+			style, text = styleBehind, s.Remote+" (gone)"
+		case s.Remote != "":
+			style, text = styleBranch, s.Remote
+		}
+	case "fork":
+		if s.ForkBehind > 0 {
+			style, text = styleBehind, fmt.Sprintf("%s%d", iconBehind, s.ForkBehind)
+		}
+	case "head":
+		if s.CommitHash != "" && !strings.HasPrefix(s.CommitHash, "(") { // "(initial)" before the first commit
+			style, text = styleFg, s.CommitHash
+		}
+	case "tag":
+		if t := s.Aliases[tagAlias]; t != "" {
+			style, text = styleFg, t
+		}
+	case "week":
+		if repo.Diff != nil {
+			if n := weekCommits(repo); n > 0 {
+				style, text = styleChurn, fmt.Sprintf("%d", n)
+			} else {
+				text = "0"
+			}
+		}
+	default:
+		if a, ok := c.alias(); ok && s.Aliases[a] != "" {
+			style, text = styleFg, s.Aliases[a]
+		}
+	}
+	return r.bg(style).Width(c.width).Render(truncateWithEllipsis(text, c.width-1))
+}
+
+// weekCommits counts the repo's commits over the last 7 days.
+func weekCommits(r *model.Repository) int {
+	if r.Diff == nil {
+		return 0
+	}
+	n := 0
+	for _, c := range r.Diff.DailyCommits {
+		n += c
+	}
+	return n
+}
+
+// columnOrder compares two repos with a status by a column, in the
+// column's natural order: text A to Z, larger counts and newer commits
+// first.
+func (m *Model) columnOrder(name string, a, b *model.Repository) int {
+	sa, sb := a.Status, b.Status
+	text := func(x, y string) int { return strings.Compare(strings.ToLower(x), strings.ToLower(y)) }
+	count := func(x, y int) int { return cmp.Compare(y, x) }
+	switch name {
+	case "repo":
+		return text(a.DisplayName(), b.DisplayName())
+	case "branch":
+		return text(sa.Branch, sb.Branch)
+	case "sync":
+		return count(sa.Ahead+sa.Behind, sb.Ahead+sb.Behind)
+	case "changes":
+		return count(sa.Staged+sa.Modified+sa.Untracked+sa.Conflicts, sb.Staged+sb.Modified+sb.Untracked+sb.Conflicts)
+	case "diff":
+		return count(diffVolume(a), diffVolume(b))
+	case "age":
+		return sb.LastCommit.Compare(sa.LastCommit)
+	case "owner":
+		return text(sa.Owner, sb.Owner)
+	case "stashes":
+		return count(sa.Stashes, sb.Stashes)
+	case "remote", "upstream":
+		return text(sa.Remote, sb.Remote)
+	case "fork":
+		return count(sa.ForkBehind, sb.ForkBehind)
+	case "pr":
+		return count(m.pullNumber(a.Path), m.pullNumber(b.Path))
+	case "head":
+		return text(sa.CommitHash, sb.CommitHash)
+	case "tag":
+		return text(sa.Aliases[tagAlias], sb.Aliases[tagAlias])
+	case "week":
+		return count(weekCommits(a), weekCommits(b))
+	}
+	if alias, ok := strings.CutPrefix(name, config.AliasColumnPrefix); ok {
+		return text(sa.Aliases[alias], sb.Aliases[alias])
+	}
+	return 0
+}
+
+// shortAge formats d in its largest whole unit, e.g. 5m, 3h, 12d, 2mo.
+func shortAge(d time.Duration) string {
+	const day = 24 * time.Hour
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < day:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	case d < 30*day:
+		return fmt.Sprintf("%dd", int(d/day))
+	case d < 365*day:
+		return fmt.Sprintf("%dmo", int(d/(30*day)))
+	}
+	return fmt.Sprintf("%dy", int(d/(365*day)))
+}
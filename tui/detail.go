@@ -0,0 +1,404 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jackchuka/gv/internal/keymap"
+	"github.com/jackchuka/gv/internal/model"
+)
+
+// detailTab is a page of the detail panel.
+type detailTab int
+
+const (
+	tabOverview detailTab = iota
+	tabFiles
+	tabActivity
+	tabBranches
+	tabStashes
+	tabRemotes
+)
+
+// Tab labels, and shorter ones for a narrow panel
+var (
+	detailTabNames = []string{"Overview", "Files", "Activity", "Branches", "Stashes", "Remotes"}
+	detailTabShort = []string{"Info", "Files", "Log", "Br", "Stash", "Rem"}
+)
+
+// Bounds of the detail panel's share of the width, in percent
+const (
+	defaultDetailPct = 35
+	minDetailPct     = 20
+	maxDetailPct     = 70
+	detailPctStep    = 5
+)
+
+// maxHotFiles is the most churned files the Activity tab lists.
+const maxHotFiles = 10
+
+// detailLoadedMsg carries the refs, remotes and log of a repo.
+type detailLoadedMsg struct {
+	path   string
+	gen    int // detailGen of path when the load started
+	detail *model.RepoDetail
+}
+
+// loadDetail reads the detail of the selected repo unless it is cached or
+// already loading.
+func (m *Model) loadDetail() tea.Cmd {
+	repo := m.selectedRepo()
+	if !m.showDetail || repo == nil || m.details[repo.Path] != nil || m.detailLoading[repo.Path] {
+		return nil
+	}
+	path, gen := repo.Path, m.detailGen[repo.Path]
+	m.detailLoading[path] = true
+	reader := m.reader
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return detailLoadedMsg{path: path, gen: gen, detail: reader.GetDetail(ctx, path)}
+	}
+}
+
+// invalidateDetail drops the detail of the repo at path, and any load of
+// it still in flight, which read the repo before it changed.
+func (m *Model) invalidateDetail(path string) {
+	delete(m.details, path)
+	delete(m.detailLoading, path)
+	m.detailGen[path]++
+}
+
+// setDetailTab switches the detail panel to tab t, scrolled to the top.
+func (m *Model) setDetailTab(t detailTab) {
+	m.showDetail = true
+	m.detailTab = t
+	m.detailScroll = 0
+}
+
+// scrollDetail handles a navigation action while the detail panel has
+// focus, reporting whether action was one.
+func (m *Model) scrollDetail(action string) bool {
+	half := max(m.visibleRows()/2, 1)
+	switch action {
+	case keymap.Up:
+		m.detailScroll--
+	case keymap.Down:
+		m.detailScroll++
+	case keymap.HalfUp:
+		m.detailScroll -= half
+	case keymap.HalfDown:
+		m.detailScroll += half
+	case keymap.Top:
+		m.detailScroll = 0
+	case keymap.Bottom:
+		m.detailScroll = 1 << 30 // clamped by syncView
+	default:
+		return false
+	}
+	m.detailScroll = max(m.detailScroll, 0)
+	return true
+}
+
+// detailTabAt returns the tab whose label is at column x of a detail
+// panel width cells wide, or -1.
+func detailTabAt(x, width int) detailTab {
+	names, gap := detailTabLabels(width)
+	start := 1
+	for i, n := range names {
+		if x >= start && x < start+lipgloss.Width(n) {
+			return detailTab(i)
+		}
+		start += lipgloss.Width(n) + gap
+	}
+	return -1
+}
+
+// detailTabLabels picks the labels that fit in width, and the gap
+// between them.
+func detailTabLabels(width int) ([]string, int) {
+	w := 1
+	for _, n := range detailTabNames {
+		w += lipgloss.Width(n) + 2
+	}
+	if w <= width {
+		return detailTabNames, 2
+	}
+	return detailTabShort, 1
+}
+
+func (m *Model) renderDetailTabs(width int) string {
+	names, gap := detailTabLabels(width)
+	parts := make([]string, len(names))
+	for i, n := range names {
+		switch {
+		case detailTab(i) == m.detailTab && m.detailFocus:
+			parts[i] = styleActiveTab.Render(n)
+		case detailTab(i) == m.detailTab:
+			parts[i] = styleTableHdr.Underline(true).Render(n)
+		default:
+			parts[i] = styleDim.Render(n)
+		}
+	}
+	return " " + strings.Join(parts, strings.Repeat(" ", gap))
+}
+
+func (m *Model) renderDetailPanel(width, height int) string {
+	tabs := m.renderDetailTabs(width)
+	repo := m.selectedRepo()
+	if repo == nil {
+		return padLines(tabs+"\n"+styleDim.Render(" No selection"), width, height)
+	}
+
+	lines := m.detailLines(repo, width-2)
+	body := height - 1 // under the tabs
+	lines = lines[max(0, min(m.detailScroll, len(lines)-body)):]
+	if len(lines) > body && body > 1 {
+		more := len(lines) - body + 1
+		lines = append(lines[:body-1], styleDim.Render(fmt.Sprintf(" ↓ %d more", more)))
+	}
+
+	return padLines(tabs+"\n"+strings.Join(lines, "\n"), width, height)
+}
+
+// detailLines renders the active tab of the detail panel for repo.
+func (m *Model) detailLines(repo *model.Repository, innerW int) []string {
+	detail := m.details[repo.Path]
+	var lines []string
+	switch m.detailTab {
+	case tabOverview:
+		lines = m.renderDetailOverview(repo, detail, innerW)
+	case tabFiles:
+		lines = m.renderDetailFiles(repo, detail, innerW)
+	case tabActivity:
+		lines = renderDetailActivity(repo, detail, innerW)
+	case tabBranches:
+		lines = renderDetailBranches(repo, detail, innerW)
+	case tabStashes:
+		lines = renderDetailStashes(detail, innerW)
+	case tabRemotes:
+		lines = renderDetailRemotes(detail, innerW)
+	}
+	if m.detailTab != tabOverview {
+		lines = append([]string{styleRepoName.Render(" " + repo.DisplayName()), ""}, lines...)
+	}
+	return lines
+}
+
+func (m *Model) renderDetailOverview(repo *model.Repository, detail *model.RepoDetail, innerW int) []string {
+	var lines []string
+	lines = append(lines, styleRepoName.Render(" "+repo.DisplayName()))
+	lines = append(lines, styleDim.Render(" "+repo.Path))
+	if info := repoSettings(repo); info != "" {
+		lines = append(lines, styleDim.Render(" "+truncateWithEllipsis(info, innerW)))
+	}
+	lines = append(lines, "")
+
+	if sub, ok := m.submoduleState(repo); ok {
+		label := sub.Label()
+		if label == "" {
+			label = "at recorded commit"
+		}
+		lines = append(lines, styleDim.Render(" submodule of "+filepath.Base(repo.Superproject)+": ")+styleAmber.Render(label))
+		lines = append(lines, "")
+	}
+
+	if repo.Status != nil {
+		lines = append(lines, renderDetailStatus(repo.Status, innerW)...)
+		lines = append(lines, renderDetailSubmodules(repo.Status.Submodules, innerW)...)
+	}
+
+	lines = append(lines, m.renderDetailPull(repo.Path, innerW)...)
+
+	if detail != nil && len(detail.Commits) > 0 {
+		c := detail.Commits[0]
+		lines = append(lines, styleTableHdr.Render(" LAST COMMIT"))
+		lines = append(lines, "  "+styleDim.Render(c.Hash+" "+shortAge(time.Since(c.Time))+" ")+
+			truncateWithEllipsis(c.Subject, innerW-lipgloss.Width(c.Hash)-8))
+		lines = append(lines, "")
+	}
+
+	if repo.Diff != nil {
+		lines = append(lines, renderDetailDiff(repo.Diff, innerW)...)
+	} else if m.diffLoading {
+		lines = append(lines, styleDim.Render(" Loading diff stats..."))
+	}
+	return lines
+}
+
+func (m *Model) renderDetailFiles(repo *model.Repository, detail *model.RepoDetail, innerW int) []string {
+	var lines []string
+	if d := repo.Diff; d != nil {
+		lines = append(lines, renderDetailFileList("STAGED FILES", d.StagedFiles, innerW, styleDiffAdd)...)
+		lines = append(lines, renderDetailFileList("MODIFIED FILES", d.UnstagedFiles, innerW, styleAmber)...)
+	} else if m.diffLoading {
+		lines = append(lines, styleDim.Render(" Loading diff stats..."), "")
+	}
+
+	switch {
+	case detail == nil:
+		lines = append(lines, styleDim.Render(" Loading untracked files..."))
+	case len(detail.Untracked) > 0:
+		lines = append(lines, styleTableHdr.Render(fmt.Sprintf(" UNTRACKED (%d)", len(detail.Untracked))))
+		for _, f := range detail.Untracked {
+			lines = append(lines, "  "+styleDim.Render(truncateWithEllipsis(f, innerW-2)))
+		}
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, styleCleanTxt.Render(" "+iconClean+" working tree clean"))
+	}
+	return lines
+}
+
+func renderDetailActivity(repo *model.Repository, detail *model.RepoDetail, innerW int) []string {
+	var lines []string
+	if d := repo.Diff; d != nil {
+		lines = append(lines, styleTableHdr.Render(" ACTIVITY (7d)"))
+		lines = append(lines, " "+renderSparkline(d.DailyCommits[:], colorAccent))
+		lines = append(lines, styleDim.Render(fmt.Sprintf("  %d commits this week", weekCommits(repo))))
+		lines = append(lines, "")
+
+		if top := d.TopChurnFiles(maxHotFiles); len(top) > 0 {
+			lines = append(lines, styleTableHdr.Render(" HOT FILES"))
+			maxChurn := top[0].Count
+			for _, entry := range top {
+				fname := truncateWithEllipsis(entry.Path, innerW-12)
+				lines = append(lines, fmt.Sprintf("  %s %s %s",
+					renderChurnBar(entry.Count, maxChurn, 6),
+					styleChurn.Render(fmt.Sprintf("%d", entry.Count)),
+					styleDim.Render(fname)))
+			}
+			lines = append(lines, "")
+		}
+	}
+
+	switch {
+	case detail == nil:
+		lines = append(lines, styleDim.Render(" Loading commits..."))
+	case len(detail.Commits) == 0:
+		lines = append(lines, styleDim.Render(" No commits yet"))
+	default:
+		lines = append(lines, styleTableHdr.Render(" RECENT COMMITS"))
+		for _, c := range detail.Commits {
+			prefix := c.Hash + " " + padRight(shortAge(time.Since(c.Time)), 4) + " "
+			lines = append(lines, "  "+styleDim.Render(prefix)+
+				truncateWithEllipsis(c.Subject, innerW-lipgloss.Width(prefix)-2))
+		}
+	}
+	return lines
+}
+
+func renderDetailBranches(repo *model.Repository, detail *model.RepoDetail, innerW int) []string {
+	if detail == nil {
+		return []string{styleDim.Render(" Loading branches...")}
+	}
+	if len(detail.Branches) == 0 {
+		return []string{styleDim.Render(" No branches yet")}
+	}
+
+	current := ""
+	if repo.Status != nil {
+		current = repo.Status.Branch
+	}
+	lines := []string{styleTableHdr.Render(fmt.Sprintf(" BRANCHES (%d)", len(detail.Branches)))}
+	for _, b := range detail.Branches {
+		marker, name := "  ", styleFg
+		if b.Name == current {
+			marker, name = styleBranch.Render(iconBranch+" "), styleBranch.Bold(true)
+		}
+
+		var sync []string
+		switch {
+		case b.UpstreamGone:
+			sync = append(sync, styleBehind.Render("gone"))
+		case b.Upstream == "":
+			sync = append(sync, styleDim.Render("local"))
+		default:
+			if b.Ahead > 0 {
+				sync = append(sync, styleAhead.Render(fmt.Sprintf("%s%d", iconAhead, b.Ahead)))
+			}
+			if b.Behind > 0 {
+				sync = append(sync, styleBehind.Render(fmt.Sprintf("%s%d", iconBehind, b.Behind)))
+			}
+		}
+		if !b.LastCommit.IsZero() {
+			sync = append(sync, styleDim.Render(shortAge(time.Since(b.LastCommit))))
+		}
+		right := strings.Join(sync, " ")
+		nameW := max(innerW-lipgloss.Width(right)-4, 4)
+		lines = append(lines, " "+marker+padRight(name.Render(truncateWithEllipsis(b.Name, nameW)), nameW)+" "+right)
+	}
+	return lines
+}
+
+func renderDetailStashes(detail *model.RepoDetail, innerW int) []string {
+	if detail == nil {
+		return []string{styleDim.Render(" Loading stashes...")}
+	}
+	if len(detail.Stashes) == 0 {
+		return []string{styleDim.Render(" No stashes")}
+	}
+
+	lines := []string{styleTableHdr.Render(fmt.Sprintf(" STASHES (%d)", len(detail.Stashes)))}
+	for _, s := range detail.Stashes {
+		head := "  " + styleAmber.Render(s.Ref)
+		if !s.Time.IsZero() {
+			head += styleDim.Render(" · " + shortAge(time.Since(s.Time)))
+		}
+		lines = append(lines, head, "    "+truncateWithEllipsis(s.Message, innerW-4))
+	}
+	return lines
+}
+
+func renderDetailRemotes(detail *model.RepoDetail, innerW int) []string {
+	if detail == nil {
+		return []string{styleDim.Render(" Loading remotes...")}
+	}
+	if len(detail.Remotes) == 0 {
+		return []string{styleDim.Render(" No remotes")}
+	}
+
+	lines := []string{styleTableHdr.Render(fmt.Sprintf(" REMOTES (%d)", len(detail.Remotes)))}
+	for _, r := range detail.Remotes {
+		// HEAD against the remote's matching branch
+		var sync []string
+		switch {
+		case r.Branch == "":
+			sync = append(sync, styleDim.Render("no matching branch"))
+		case r.Ahead == 0 && r.Behind == 0:
+			sync = append(sync, styleDim.Render(r.Branch+" in sync"))
+		default:
+			sync = append(sync, styleDim.Render(r.Branch))
+			if r.Ahead > 0 {
+				sync = append(sync, styleAhead.Render(fmt.Sprintf("%s%d", iconAhead, r.Ahead)))
+			}
+			if r.Behind > 0 {
+				sync = append(sync, styleBehind.Render(fmt.Sprintf("%s%d", iconBehind, r.Behind)))
+			}
+		}
+		right := strings.Join(sync, " ")
+		nameW := max(innerW-lipgloss.Width(right)-2, 4)
+		lines = append(lines, " "+padRight(styleBranch.Render(truncateWithEllipsis(r.Name, nameW)), nameW)+" "+right)
+
+		if r.Host != "" {
+			slug := r.Repo
+			if r.Owner != "" {
+				slug = r.Owner + "/" + r.Repo
+			}
+			lines = append(lines, "  "+truncateWithEllipsis(r.Host+" · "+slug, innerW-2))
+		}
+		lines = append(lines, styleDim.Render("  fetch ")+truncateWithEllipsis(r.FetchURL, innerW-8))
+		if r.PushURL != "" && r.PushURL != r.FetchURL {
+			lines = append(lines, styleDim.Render("  push  ")+truncateWithEllipsis(r.PushURL, innerW-8))
+		}
+		lines = append(lines, "")
+	}
+	return lines
+}
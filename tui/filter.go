@@ -0,0 +1,131 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jackchuka/gv/internal/config"
+	"github.com/jackchuka/gv/internal/keymap"
+	"github.com/jackchuka/gv/internal/query"
+)
+
+// savedView is a named query from the views section of the config.
+type savedView struct {
+	name  string
+	query *query.Query
+	key   key.Binding // disabled when the view has no key
+}
+
+// newSavedViews compiles the configured views. A key already bound to an
+// action or an earlier view is dropped and reported in conflicts.
+func newSavedViews(cfgs []config.ViewConfig, keys keyMap) (views []savedView, conflicts []string) {
+	taken := make(map[string]bool)
+
+	for _, c := range cfgs {
+		q, err := query.Parse(c.Query)
+		if err != nil {
+			// Load rejects invalid queries; skip rather than trust that
+			conflicts = append(conflicts, fmt.Sprintf("View %s: %v", c.Name, err))
+			continue
+		}
+		v := savedView{name: c.Name, query: q, key: key.NewBinding(key.WithDisabled())}
+		switch {
+		case c.Key == "":
+		case keys.taken(c.Key), taken[c.Key], slices.Contains(keymap.Reserved, c.Key):
+			conflicts = append(conflicts, fmt.Sprintf("View %s: key %s is already bound", c.Name, c.Key))
+		default:
+			taken[c.Key] = true
+			v.key = key.NewBinding(key.WithKeys(c.Key), key.WithHelp(c.Key, c.Name))
+		}
+		views = append(views, v)
+	}
+	return views, conflicts
+}
+
+// activeQuery returns the query of the selected saved view, or nil.
+func (m *Model) activeQuery() *query.Query {
+	if m.activeView < 0 || m.activeView >= len(m.views) {
+		return nil
+	}
+	return m.views[m.activeView].query
+}
+
+// selectView switches to saved view i, replacing the built-in view.
+func (m *Model) selectView(i int) {
+	m.viewFilter = ViewAll
+	m.activeView = i
+	m.buildRows()
+}
+
+// setFilter parses text as the filter query. While text does not parse,
+// the last valid query stays in effect and filterErr says what is wrong.
+func (m *Model) setFilter(text string) {
+	m.filterText = text
+	q, err := query.Parse(text)
+	m.filterErr = err
+	if err == nil {
+		m.filterQuery = q
+	}
+	m.buildRows()
+}
+
+// handleFilterKey handles a key while the filter prompt is open.
+func (m *Model) handleFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Escape):
+		m.filterMode = false
+		m.filterInput.Reset()
+		m.setFilter("")
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		if m.filterErr != nil {
+			return m, nil // Keep the prompt open until the query parses
+		}
+		m.filterMode = false
+		m.state.AddHistory(m.filterText)
+		return m, m.saveState()
+
+	case msg.Type == tea.KeyUp, msg.Type == tea.KeyDown:
+		// Browse earlier queries, newest first
+		hist := m.state.History
+		if msg.Type == tea.KeyUp {
+			m.historyPos = max(m.historyPos-1, 0)
+		} else {
+			m.historyPos = min(m.historyPos+1, len(hist))
+		}
+		text := ""
+		if m.historyPos < len(hist) {
+			text = hist[m.historyPos]
+		}
+		m.filterInput.SetValue(text)
+		m.filterInput.CursorEnd()
+		m.setFilter(text)
+		return m, nil
+
+	default:
+		var cmd tea.Cmd
+		m.filterInput, cmd = m.filterInput.Update(msg)
+		m.setFilter(m.filterInput.Value())
+		return m, cmd
+	}
+}
+
+// saveState writes the state, reporting a failure as a toast.
+func (m *Model) saveState() tea.Cmd {
+	if m.statePath == "" {
+		return nil
+	}
+	if err := m.state.Save(m.statePath); err != nil {
+		return m.addToast("Saving state failed: "+err.Error(), ToastError)
+	}
+	return nil
+}
+
+// viewIndex returns the saved view bound to msg, or -1.
+func (m *Model) viewIndex(msg tea.KeyMsg) int {
+	return slices.IndexFunc(m.views, func(v savedView) bool { return key.Matches(msg, v.key) })
+}
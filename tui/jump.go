@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jackchuka/gv/internal/fuzzy"
+	"github.com/jackchuka/gv/internal/model"
+)
+
+// jumpMatch is a repo ranked by the quick-jump finder.
+type jumpMatch struct {
+	repo  *model.Repository
+	score int
+	name  []int // matched positions in the display name, if it matched
+}
+
+// rankRepos fuzzy-matches pattern against each repo's display name, path,
+// branch and owner, best first. A repo scores as its best field, plus a
+// bonus for recent use; with an empty pattern recency alone decides.
+func rankRepos(repos []model.Repository, pattern string, used map[string]time.Time, now time.Time) []jumpMatch {
+	var matches []jumpMatch
+	for i := range repos {
+		r := &repos[i]
+		best, ok := fuzzy.Match(pattern, r.DisplayName())
+		name := best.Positions
+		for _, field := range jumpFields(r) {
+			if res, fok := fuzzy.Match(pattern, field); fok && (!ok || res.Score > best.Score) {
+				best, ok = res, true
+			}
+		}
+		if !ok {
+			continue
+		}
+		matches = append(matches, jumpMatch{
+			repo:  r,
+			score: best.Score + recencyBonus(used[r.Path], now),
+			name:  name,
+		})
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	return matches
+}
+
+// jumpFields are the fields besides the display name the finder searches.
+func jumpFields(r *model.Repository) []string {
+	fields := []string{r.Path}
+	if s := r.Status; s != nil {
+		fields = append(fields, s.Branch, s.Owner)
+	}
+	return fields
+}
+
+// recencyBonus favors repos used lately, worth about one well-placed
+// character for the last hour and fading over a month.
+func recencyBonus(used, now time.Time) int {
+	if used.IsZero() {
+		return 0
+	}
+	switch age := now.Sub(used); {
+	case age < time.Hour:
+		return 24
+	case age < 24*time.Hour:
+		return 16
+	case age < 7*24*time.Hour:
+		return 8
+	case age < 30*24*time.Hour:
+		return 4
+	}
+	return 0
+}
+
+// startJump opens the quick-jump prompt, remembering the selected repo to
+// return to on escape.
+func (m *Model) startJump() tea.Cmd {
+	m.jumpMode = true
+	m.jumpReturn = ""
+	if repo := m.selectedRepo(); repo != nil {
+		m.jumpReturn = repo.Path
+	}
+	m.jumpInput.Reset()
+	m.cursor = 0
+	m.buildRows()
+	return m.jumpInput.Focus()
+}
+
+// endJump closes the prompt and selects the repo at path, widening the
+// view if the current filters hide it.
+func (m *Model) endJump(path string) {
+	m.jumpMode = false
+	m.jumpInput.Blur()
+	m.buildRows()
+	if path == "" {
+		return
+	}
+	if !m.showsPath(path) {
+		m.viewFilter = ViewAll
+		m.activeView = -1
+		m.filterInput.Reset()
+		m.setFilter("")
+	}
+	m.selectPath(path)
+}
+
+// handleJumpKey handles a key while the quick-jump prompt is open.
+func (m *Model) handleJumpKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Escape):
+		m.endJump(m.jumpReturn)
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		repo := m.selectedRepo()
+		if repo == nil {
+			return m, nil
+		}
+		path := repo.Path
+		m.endJump(path)
+		m.showDetail = true
+		return m, m.touch(path)
+
+	case msg.Type == tea.KeyUp, msg.Type == tea.KeyCtrlP:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		return m, nil
+
+	case msg.Type == tea.KeyDown, msg.Type == tea.KeyCtrlN:
+		if m.cursor < len(m.rows)-1 {
+			m.cursor++
+		}
+		return m, nil
+
+	default:
+		var cmd tea.Cmd
+		m.jumpInput, cmd = m.jumpInput.Update(msg)
+		m.cursor = 0 // the best match
+		m.buildRows()
+		return m, cmd
+	}
+}
+
+// touch records that the repo at path was used, for ranking jumps.
+func (m *Model) touch(path string) tea.Cmd {
+	m.state.Touch(path, time.Now())
+	return m.saveState()
+}
+
+// showsPath reports whether the repo at path has a row.
+func (m *Model) showsPath(path string) bool {
+	for _, row := range m.rows {
+		if row.Repo != nil && row.Repo.Path == path {
+			return true
+		}
+	}
+	return false
+}
@@ -1,187 +1,205 @@
 package tui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jackchuka/gv/internal/keymap"
+)
+
+// keyMap is the active keybindings: every action with its keys from the
+// config or the defaults, plus the fixed keys prompts and pickers use.
 type keyMap struct {
-	// Navigation
-	Up       key.Binding
-	Down     key.Binding
-	Top      key.Binding
-	Bottom   key.Binding
-	HalfDown key.Binding
-	HalfUp   key.Binding
-
-	// Filter & input
-	Filter key.Binding
+	bindings []keymap.Binding
+	actions  map[string]string // key sequence, space-separated -> action
+	prefixes map[string]bool   // sequences that start a longer one
+
+	// Prompts and pickers
+	Up     key.Binding
+	Down   key.Binding
 	Escape key.Binding
 	Enter  key.Binding
+}
+
+func newKeyMap(overrides map[string]keymap.Keys) keyMap {
+	bindings, err := keymap.Build(overrides)
+	if err != nil {
+		bindings = keymap.Defaults() // Load rejects invalid keys; don't trust that
+	}
 
-	// Actions
-	Reload   key.Binding
-	Fetch    key.Binding
-	FetchAll key.Binding
-	Open     key.Binding
-	Editor   key.Binding
-	Shell    key.Binding
-	CopyPath key.Binding
-
-	// Views
-	ViewAll       key.Binding
-	ViewDirty     key.Binding
-	ViewUnpushed  key.Binding
-	ViewConflicts key.Binding
-
-	// V3 sort modes
-	SortDiff  key.Binding
-	SortChurn key.Binding
-
-	// V3 detail toggle
-	Detail key.Binding
-
-	// Meta
-	Help key.Binding
-	Quit key.Binding
+	k := keyMap{
+		bindings: bindings,
+		actions:  make(map[string]string),
+		prefixes: make(map[string]bool),
+		Escape:   key.NewBinding(key.WithKeys("esc")),
+		Enter:    key.NewBinding(key.WithKeys("enter")),
+	}
+	for _, b := range bindings {
+		for _, spec := range b.Keys {
+			seq := pressed(spec)
+			k.actions[strings.Join(seq, " ")] = b.Action
+			for i := 1; i < len(seq); i++ {
+				k.prefixes[strings.Join(seq[:i], " ")] = true
+			}
+		}
+	}
+	k.Up = key.NewBinding(key.WithKeys(k.singleKeys(keymap.Up)...))
+	k.Down = key.NewBinding(key.WithKeys(k.singleKeys(keymap.Down)...))
+	return k
 }
 
-func newKeyMap() keyMap {
-	return keyMap{
-		Up: key.NewBinding(
-			key.WithKeys("up", "k"),
-			key.WithHelp("↑/k", "up"),
-		),
-		Down: key.NewBinding(
-			key.WithKeys("down", "j"),
-			key.WithHelp("↓/j", "down"),
-		),
-		Top: key.NewBinding(
-			key.WithKeys("home", "g"),
-			key.WithHelp("g/home", "top"),
-		),
-		Bottom: key.NewBinding(
-			key.WithKeys("end", "G"),
-			key.WithHelp("G/end", "bottom"),
-		),
-		HalfDown: key.NewBinding(
-			key.WithKeys("ctrl+d"),
-			key.WithHelp("C-d", "½ page down"),
-		),
-		HalfUp: key.NewBinding(
-			key.WithKeys("ctrl+u"),
-			key.WithHelp("C-u", "½ page up"),
-		),
-		Filter: key.NewBinding(
-			key.WithKeys("/"),
-			key.WithHelp("/", "filter"),
-		),
-		Escape: key.NewBinding(
-			key.WithKeys("esc"),
-			key.WithHelp("esc", "clear"),
-		),
-		Enter: key.NewBinding(
-			key.WithKeys("enter"),
-			key.WithHelp("enter", "select"),
-		),
-		Reload: key.NewBinding(
-			key.WithKeys("r"),
-			key.WithHelp("r", "reload"),
-		),
-		Fetch: key.NewBinding(
-			key.WithKeys("f"),
-			key.WithHelp("f", "fetch"),
-		),
-		FetchAll: key.NewBinding(
-			key.WithKeys("F"),
-			key.WithHelp("F", "fetch all"),
-		),
-		Open: key.NewBinding(
-			key.WithKeys("o"),
-			key.WithHelp("o", "open finder"),
-		),
-		Editor: key.NewBinding(
-			key.WithKeys("e"),
-			key.WithHelp("e", "editor"),
-		),
-		Shell: key.NewBinding(
-			key.WithKeys(":"),
-			key.WithHelp(":", "shell"),
-		),
-		CopyPath: key.NewBinding(
-			key.WithKeys("y"),
-			key.WithHelp("y", "copy path"),
-		),
-		ViewAll: key.NewBinding(
-			key.WithKeys("1"),
-			key.WithHelp("1", "all"),
-		),
-		ViewDirty: key.NewBinding(
-			key.WithKeys("2"),
-			key.WithHelp("2", "dirty"),
-		),
-		ViewUnpushed: key.NewBinding(
-			key.WithKeys("3"),
-			key.WithHelp("3", "ahead"),
-		),
-		ViewConflicts: key.NewBinding(
-			key.WithKeys("4"),
-			key.WithHelp("4", "conflict"),
-		),
-		SortDiff: key.NewBinding(
-			key.WithKeys("5"),
-			key.WithHelp("5", "sort:diff"),
-		),
-		SortChurn: key.NewBinding(
-			key.WithKeys("6"),
-			key.WithHelp("6", "sort:churn"),
-		),
-		Detail: key.NewBinding(
-			key.WithKeys("d"),
-			key.WithHelp("d", "detail"),
-		),
-		Help: key.NewBinding(
-			key.WithKeys("?"),
-			key.WithHelp("?", "help"),
-		),
-		Quit: key.NewBinding(
-			key.WithKeys("q", "ctrl+c"),
-			key.WithHelp("q", "quit"),
-		),
+// pressed splits a key from the config into key names as tea.KeyMsg
+// spells them.
+func pressed(spec string) []string {
+	seq := keymap.Split(spec)
+	for i, k := range seq {
+		if k == "space" {
+			seq[i] = " "
+		}
 	}
+	return seq
 }
 
-func (k keyMap) helpText() string {
-	format := func(b key.Binding) string {
-		h := b.Help()
-		return "  " + padRight(h.Key, 12) + h.Desc
-	}
-
-	return `Navigation
-` + format(k.Up) + `
-` + format(k.Down) + `
-` + format(k.Top) + `
-` + format(k.Bottom) + `
-` + format(k.HalfDown) + `
-` + format(k.HalfUp) + `
-` + format(k.Filter) + `
-` + format(k.Escape) + `
-
-Actions
-` + format(k.Reload) + `
-` + format(k.Fetch) + `
-` + format(k.FetchAll) + `
-` + format(k.Editor) + `
-` + format(k.Open) + `
-` + format(k.CopyPath) + `
-` + format(k.Shell) + `
-
-Views & Sort
-` + format(k.ViewAll) + `
-` + format(k.ViewDirty) + `
-` + format(k.ViewUnpushed) + `
-` + format(k.ViewConflicts) + `
-` + format(k.SortDiff) + `
-` + format(k.SortChurn) + `
-` + format(k.Detail) + `
-
-` + format(k.Help) + `
-` + format(k.Quit)
+// lookup resolves msg, pressed after the unfinished sequence pending. It
+// returns the action to run, or the new pending sequence when msg leaves
+// a sequence unfinished. A key that breaks a sequence counts on its own.
+func (k keyMap) lookup(pending []string, msg tea.KeyMsg) (action string, next []string) {
+	seq := append(pending[:len(pending):len(pending)], msg.String())
+	id := strings.Join(seq, " ")
+	if k.prefixes[id] {
+		return "", seq
+	}
+	if a, ok := k.actions[id]; ok {
+		return a, nil
+	}
+	if len(seq) > 1 {
+		return k.lookup(nil, msg)
+	}
+	return "", nil
+}
+
+// taken reports whether the single key s is bound or starts a sequence.
+func (k keyMap) taken(s string) bool {
+	return k.actions[s] != "" || k.prefixes[s]
+}
+
+// keys returns the keys bound to action, as shown to the user.
+func (k keyMap) keys(action string) []string {
+	for _, b := range k.bindings {
+		if b.Action == action {
+			out := make([]string, len(b.Keys))
+			for i, spec := range b.Keys {
+				out[i] = displayKey(spec)
+			}
+			return out
+		}
+	}
+	return nil
+}
+
+// short returns the first key bound to action, or "" when it is unbound.
+func (k keyMap) short(action string) string {
+	if keys := k.keys(action); len(keys) > 0 {
+		return keys[0]
+	}
+	return ""
+}
+
+// singleKeys returns the keys of action that are not sequences.
+func (k keyMap) singleKeys(action string) []string {
+	var out []string
+	for _, b := range k.bindings {
+		if b.Action != action {
+			continue
+		}
+		for _, spec := range b.Keys {
+			if seq := pressed(spec); len(seq) == 1 {
+				out = append(out, seq[0])
+			}
+		}
+	}
+	return out
+}
+
+func displayKey(spec string) string {
+	switch spec {
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	}
+	if rest, ok := strings.CutPrefix(spec, "ctrl+"); ok {
+		return "C-" + rest
+	}
+	return spec
+}
+
+// helpText lists the bound actions by group, followed by extra blocks,
+// in two columns.
+func (k keyMap) helpText(extra ...string) string {
+	var blocks []string
+	var b strings.Builder
+	group := "-"
+	for _, bd := range k.bindings {
+		if len(bd.Keys) == 0 {
+			continue
+		}
+		if bd.Group != group {
+			if group == "Navigation" {
+				b.WriteString("  " + padRight("esc", 12) + "clear\n")
+			}
+			if b.Len() > 0 {
+				blocks = append(blocks, strings.TrimSuffix(b.String(), "\n"))
+				b.Reset()
+			}
+			if bd.Group != "" {
+				b.WriteString(bd.Group + "\n")
+			}
+			group = bd.Group
+		}
+		b.WriteString("  " + padRight(strings.Join(k.keys(bd.Action), "/"), 12) + bd.Desc + "\n")
+	}
+	if b.Len() > 0 {
+		blocks = append(blocks, strings.TrimSuffix(b.String(), "\n"))
+	}
+	blocks = append(blocks, extra...)
+
+	// A block goes left while its middle is in the first half of the lines
+	total := 0
+	for _, bl := range blocks {
+		total += lipgloss.Height(bl) + 1
+	}
+	var left, right []string
+	n := 0
+	for _, bl := range blocks {
+		h := lipgloss.Height(bl) + 1
+		if n+h/2 < total/2 {
+			left = append(left, bl)
+		} else {
+			right = append(right, bl)
+		}
+		n += h
+	}
+	col := lipgloss.NewStyle().Width(helpColumnWidth)
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		col.Render(strings.Join(left, "\n\n")), col.Render(strings.Join(right, "\n\n")))
+}
+
+// helpColumnWidth is the width of each column of the help overlay.
+const helpColumnWidth = 38
+
+// hint returns " (<key> text)" naming the key of action, for messages, or
+// "" when action is unbound.
+func (k keyMap) hint(action, text string) string {
+	if s := k.short(action); s != "" {
+		return " (" + s + " " + text + ")"
+	}
+	return ""
 }
@@ -0,0 +1,161 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jackchuka/gv/internal/keymap"
+)
+
+// layout is where View last drew the clickable parts of the dashboard.
+type layout struct {
+	tableTop    int // row of the table header
+	tableWidth  int // the detail panel starts after it
+	detailWidth int // 0 when the panel is hidden
+	cols        []column
+	footerRow   int
+	footer      []footerZone
+}
+
+// footerZone is a footer hint, clicked to run its action or saved view.
+type footerZone struct {
+	start, end int // columns
+	action     string
+	view       int // saved view index, or -1
+}
+
+// doubleClick is the longest gap between two clicks on a row that opens
+// it in the editor.
+const doubleClick = 400 * time.Millisecond
+
+// wheelStep is the rows one wheel notch scrolls.
+const wheelStep = 3
+
+// click is the last click on a table row, to detect double clicks.
+type click struct {
+	row int
+	at  time.Time
+}
+
+// handleMouse handles clicks and the wheel on the dashboard. Overlays and
+// prompts take the keyboard only.
+func (m *Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.showHelp || m.showScan || m.showTags || m.tagMode || m.filterMode || m.jumpMode || m.paletteMode {
+		return m, nil
+	}
+	inDetail := m.layout.detailWidth > 0 && msg.X > m.layout.tableWidth
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp, tea.MouseButtonWheelDown:
+		step := wheelStep
+		if msg.Button == tea.MouseButtonWheelUp {
+			step = -step
+		}
+		if inDetail {
+			m.detailScroll = max(0, m.detailScroll+step)
+		} else {
+			m.scrollTable(step)
+		}
+		return m, nil
+
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+		if inDetail {
+			m.clickDetail(msg.X-m.layout.tableWidth-1, msg.Y)
+			return m, nil
+		}
+		m.detailFocus = false
+		return m.click(msg.X, msg.Y)
+	}
+	return m, nil
+}
+
+// click handles a left click at column x, row y.
+func (m *Model) click(x, y int) (tea.Model, tea.Cmd) {
+	switch {
+	case y == m.layout.tableTop:
+		m.sortBy(m.columnAt(x))
+
+	case y > m.layout.tableTop && y <= m.layout.tableTop+m.visibleRows():
+		i := m.scrollOffset + y - m.layout.tableTop - 1
+		if i >= len(m.rows) {
+			return m, nil
+		}
+		m.keySeq = nil
+		m.cursor = i
+		now := time.Now()
+		double := m.lastClick.row == i && now.Sub(m.lastClick.at) < doubleClick
+		m.lastClick = click{row: i, at: now}
+		if double {
+			m.lastClick = click{}
+			return m.dispatch(keymap.Editor)
+		}
+
+	case y == m.layout.footerRow:
+		for _, z := range m.layout.footer {
+			if x < z.start || x >= z.end {
+				continue
+			}
+			if z.view >= 0 {
+				m.selectView(z.view)
+				return m, nil
+			}
+			if z.action != "" && z.action != keymap.Quit { // too easy to hit by accident
+				return m.dispatch(z.action)
+			}
+			return m, nil
+		}
+	}
+	return m, nil
+}
+
+// clickDetail focuses the detail panel, switching tabs on a click at
+// column x of the tab bar.
+func (m *Model) clickDetail(x, y int) {
+	m.detailFocus = true
+	if y != m.layout.tableTop {
+		return
+	}
+	if t := detailTabAt(x, m.layout.detailWidth); t >= 0 {
+		m.setDetailTab(t)
+	}
+}
+
+// columnAt returns the name of the table column at x, or "".
+func (m *Model) columnAt(x int) string {
+	start := 1 // leading space
+	for _, c := range m.layout.cols {
+		if x >= start && x < start+c.width {
+			return c.name
+		}
+		start += c.width
+	}
+	return ""
+}
+
+// sortBy sorts the table by column name in its natural order, then in
+// reverse on the next click, then back to the default order.
+func (m *Model) sortBy(name string) {
+	if name == "" {
+		return
+	}
+	switch {
+	case m.sortMode != SortColumn || m.sortColumn != name:
+		m.sortMode, m.sortColumn, m.sortReverse = SortColumn, name, false
+	case !m.sortReverse:
+		m.sortReverse = true
+	default:
+		m.sortMode, m.sortColumn, m.sortReverse = SortAlpha, "", false
+	}
+	m.buildRows()
+}
+
+// scrollTable scrolls the table by n rows, keeping the cursor on screen.
+func (m *Model) scrollTable(n int) {
+	vis := m.visibleRows()
+	m.scrollOffset = max(0, min(m.scrollOffset+n, len(m.rows)-vis))
+	m.cursor = max(m.scrollOffset, min(m.cursor, m.scrollOffset+vis-1, len(m.rows)-1))
+}
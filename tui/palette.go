@@ -0,0 +1,333 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jackchuka/gv/internal/config"
+	"github.com/jackchuka/gv/internal/fuzzy"
+	"github.com/jackchuka/gv/internal/keymap"
+	"github.com/jackchuka/gv/internal/model"
+)
+
+// paletteEntry is something the command palette can run.
+type paletteEntry struct {
+	id    string // remembered in state, e.g. action:fetch or view:work
+	title string
+	group string
+	key   string // bound key, if any
+	run   func(m *Model) (tea.Model, tea.Cmd)
+}
+
+// paletteMatch is an entry ranked for the palette's query.
+type paletteMatch struct {
+	entry     paletteEntry
+	positions []int // matched positions in the title
+	recent    bool
+}
+
+// maxPaletteRecent bounds the recently run entries listed above the rest.
+const maxPaletteRecent = 5
+
+// paletteEntries lists every action, saved view, column preset and custom
+// command, in that order.
+func (m *Model) paletteEntries() []paletteEntry {
+	var entries []paletteEntry
+	for _, b := range m.keys.bindings {
+		if b.Action == keymap.Palette {
+			continue
+		}
+		group := b.Group
+		if group == "" {
+			group = "General"
+		}
+		action := b.Action
+		entries = append(entries, paletteEntry{
+			id:    "action:" + action,
+			title: b.Title,
+			group: group,
+			key:   m.keys.short(action),
+			run:   func(m *Model) (tea.Model, tea.Cmd) { return m.dispatch(action) },
+		})
+	}
+
+	for i, v := range m.views {
+		e := paletteEntry{
+			id:    "view:" + v.name,
+			title: "View: " + v.name,
+			group: "Saved views",
+			run: func(m *Model) (tea.Model, tea.Cmd) {
+				m.selectView(i)
+				return m, nil
+			},
+		}
+		if v.key.Enabled() {
+			e.key = v.key.Help().Key
+		}
+		entries = append(entries, e)
+	}
+
+	for i, p := range m.presets {
+		entries = append(entries, paletteEntry{
+			id:    "columns:" + p.Name,
+			title: "Columns: " + p.Name,
+			group: "Columns",
+			run:   func(m *Model) (tea.Model, tea.Cmd) { return m, m.setPreset(i) },
+		})
+	}
+
+	for _, c := range m.cfg.Commands {
+		title := "Run: " + c.Name
+		if c.All {
+			title += " (all listed)"
+		}
+		entries = append(entries, paletteEntry{
+			id:    "command:" + c.Name,
+			title: title,
+			group: "Commands",
+			run:   func(m *Model) (tea.Model, tea.Cmd) { return m, m.runCommand(c) },
+		})
+	}
+	return entries
+}
+
+// rankPalette matches query against the entries' titles, best first, with
+// a bonus for recently run entries. An empty query lists the recent
+// entries first, then the rest in order.
+func rankPalette(entries []paletteEntry, query string, recent []string) []paletteMatch {
+	rank := func(id string) int { return slices.Index(recent, id) } // higher is more recent
+
+	if query == "" {
+		var recents, rest []paletteMatch
+		for _, e := range entries {
+			if rank(e.id) >= 0 {
+				recents = append(recents, paletteMatch{entry: e, recent: true})
+			} else {
+				rest = append(rest, paletteMatch{entry: e})
+			}
+		}
+		sort.SliceStable(recents, func(i, j int) bool {
+			return rank(recents[i].entry.id) > rank(recents[j].entry.id)
+		})
+		if len(recents) > maxPaletteRecent {
+			for i := range recents[maxPaletteRecent:] {
+				recents[maxPaletteRecent+i].recent = false
+			}
+			rest = append(recents[maxPaletteRecent:], rest...)
+			recents = recents[:maxPaletteRecent]
+		}
+		return append(recents, rest...)
+	}
+
+	type scored struct {
+		paletteMatch
+		score int
+	}
+	var matches []scored
+	for _, e := range entries {
+		res, ok := fuzzy.Match(query, e.title)
+		if !ok {
+			continue
+		}
+		score := res.Score
+		if r := rank(e.id); r >= 0 {
+			score += 8 + 8*(r+1)/len(recent)
+		}
+		matches = append(matches, scored{paletteMatch{entry: e, positions: res.Positions}, score})
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	out := make([]paletteMatch, len(matches))
+	for i, s := range matches {
+		out[i] = s.paletteMatch
+	}
+	return out
+}
+
+// startPalette opens the command palette.
+func (m *Model) startPalette() tea.Cmd {
+	m.paletteMode = true
+	m.paletteInput.Reset()
+	m.paletteCursor = 0
+	m.paletteRows = rankPalette(m.paletteEntries(), "", m.state.Recent)
+	return m.paletteInput.Focus()
+}
+
+func (m *Model) closePalette() {
+	m.paletteMode = false
+	m.paletteInput.Blur()
+	m.paletteRows = nil
+}
+
+// handlePaletteKey handles a key while the command palette is open.
+func (m *Model) handlePaletteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Escape):
+		m.closePalette()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		if m.paletteCursor >= len(m.paletteRows) {
+			return m, nil
+		}
+		e := m.paletteRows[m.paletteCursor].entry
+		m.closePalette()
+		m.state.AddRecent(e.id)
+		save := m.saveState()
+		model, cmd := e.run(m)
+		return model, tea.Batch(cmd, save)
+
+	case msg.Type == tea.KeyUp, msg.Type == tea.KeyCtrlP:
+		if m.paletteCursor > 0 {
+			m.paletteCursor--
+		}
+		return m, nil
+
+	case msg.Type == tea.KeyDown, msg.Type == tea.KeyCtrlN:
+		if m.paletteCursor < len(m.paletteRows)-1 {
+			m.paletteCursor++
+		}
+		return m, nil
+
+	default:
+		var cmd tea.Cmd
+		m.paletteInput, cmd = m.paletteInput.Update(msg)
+		m.paletteCursor = 0
+		m.paletteRows = rankPalette(m.paletteEntries(), m.paletteInput.Value(), m.state.Recent)
+		return m, cmd
+	}
+}
+
+// --- Custom commands ---
+
+// commandDoneMsg reports a custom command that finished in paths.
+type commandDoneMsg struct {
+	name   string
+	paths  []string
+	failed []string // display names of the repos it failed in
+	err    error    // the first failure
+}
+
+// maxCommandJobs bounds the repos an all command runs in at once.
+const maxCommandJobs = 8
+
+// commandTimeout is how long a command that isn't interactive may run in
+// a repo before it is killed and counted as failed there.
+const commandTimeout = 5 * time.Minute
+
+// runCommand runs c in the selected repo, or in every listed repo when c
+// is an all command.
+func (m *Model) runCommand(c config.CommandConfig) tea.Cmd {
+	var repos []model.Repository
+	if c.All {
+		for _, row := range m.rows {
+			if row.Repo != nil {
+				repos = append(repos, *row.Repo)
+			}
+		}
+	} else if repo := m.selectedRepo(); repo != nil {
+		repos = append(repos, *repo)
+	}
+	if len(repos) == 0 {
+		return m.addToast(c.Name+": no repo selected", ToastInfo)
+	}
+
+	if c.Interactive {
+		r := repos[0]
+		return tea.Batch(
+			tea.ExecProcess(commandFor(context.Background(), c, r), func(err error) tea.Msg {
+				done := commandDoneMsg{name: c.Name, paths: []string{r.Path}, err: err}
+				if err != nil {
+					done.failed = []string{r.DisplayName()}
+				}
+				return done
+			}),
+			m.touch(r.Path),
+		)
+	}
+
+	return func() tea.Msg {
+		done := commandDoneMsg{name: c.Name}
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, maxCommandJobs)
+		for _, r := range repos {
+			done.paths = append(done.paths, r.Path)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+				defer cancel()
+				out, err := commandFor(ctx, c, r).CombinedOutput()
+				if err == nil {
+					return
+				}
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					err = fmt.Errorf("%s: timed out after %s", r.DisplayName(), commandTimeout)
+				} else if line := lastLine(out); line != "" {
+					err = fmt.Errorf("%s: %s", r.DisplayName(), line)
+				} else {
+					err = fmt.Errorf("%s: %w", r.DisplayName(), err)
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				done.failed = append(done.failed, r.DisplayName())
+				if done.err == nil {
+					done.err = err
+				}
+			}()
+		}
+		wg.Wait()
+		return done
+	}
+}
+
+// commandFor builds the process for c in repo r, killed when ctx ends.
+func commandFor(ctx context.Context, c config.CommandConfig, r model.Repository) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "sh", "-c", c.Run)
+	cmd.WaitDelay = time.Second // don't wait on children still holding the output open
+	cmd.Dir = r.Path
+	cmd.Env = append(os.Environ(), "GV_REPO="+r.Path, "GV_REPO_NAME="+r.DisplayName())
+	return cmd
+}
+
+// lastLine returns the last non-blank line of out, where commands usually
+// say what went wrong.
+func lastLine(out []byte) string {
+	lines := bytes.Split(bytes.TrimSpace(out), []byte("\n"))
+	return strings.TrimSpace(string(lines[len(lines)-1]))
+}
+
+// commandDone reports a finished command and refreshes the repos it ran in.
+func (m *Model) commandDone(msg commandDoneMsg) tea.Cmd {
+	cmds := make([]tea.Cmd, 0, len(msg.paths)+1)
+	switch {
+	case msg.err != nil && len(msg.paths) > 1:
+		cmds = append(cmds, m.addToast(fmt.Sprintf("%s failed in %d of %d repos: %v",
+			msg.name, len(msg.failed), len(msg.paths), msg.err), ToastError))
+	case msg.err != nil:
+		cmds = append(cmds, m.addToast(msg.name+" failed: "+msg.err.Error(), ToastError))
+	case len(msg.paths) > 1:
+		cmds = append(cmds, m.addToast(fmt.Sprintf("%s: done in %d repos", msg.name, len(msg.paths)), ToastSuccess))
+	default:
+		cmds = append(cmds, m.addToast(msg.name+": done", ToastSuccess))
+	}
+	for _, p := range msg.paths {
+		cmds = append(cmds, m.refreshRepo(p))
+	}
+	return tea.Batch(cmds...)
+}
@@ -0,0 +1,304 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jackchuka/gv/internal/config"
+	"github.com/jackchuka/gv/internal/forge"
+	"github.com/jackchuka/gv/internal/model"
+)
+
+// pullTTL is how long a branch's pull request and checks are reused
+// before asking the forge again.
+const pullTTL = 2 * time.Minute
+
+// pullClient makes the forge API requests.
+var pullClient = &http.Client{Timeout: 10 * time.Second}
+
+// Lookups gv didn't make, which show nothing rather than a failure.
+var (
+	errNoForge = errors.New("no forge API for this remote")
+	errNoToken = errors.New("no token for the forge")
+)
+
+// pullResult is what the forge said about a repo's branch, and when.
+// status is kept from the last answer while the forge rate limits.
+type pullResult struct {
+	status *forge.Status
+	err    error
+	at     time.Time
+}
+
+// stale reports whether the forge is due to be asked again; a repo it
+// wasn't asked about yet is stale too.
+func (p pullResult) stale() bool {
+	return time.Since(p.at) >= pullTTL
+}
+
+// known reports whether there is anything to show: a status, or a failure
+// worth explaining.
+func (p pullResult) known() bool {
+	if p.status != nil {
+		return true
+	}
+	return p.err != nil && !errors.Is(p.err, errNoForge) && !errors.Is(p.err, errNoToken) && !errors.Is(p.err, forge.ErrNoBranch)
+}
+
+type pullsLoadedMsg struct {
+	results map[string]pullResult
+}
+
+// pullsStaleMsg comes pullTTL after answers arrive, to ask again about the
+// branches on screen.
+type pullsStaleMsg struct{}
+
+// loadPulls looks up the pull request and checks of the branch of each
+// repo at paths when the active preset shows the pr column, and of the
+// selected repo when the detail panel shows and its answer is stale. The
+// panel alone only looks up repos whose forge has a token: browsing would
+// soon use up the small rate limit of anonymous requests. It returns nil
+// when there's nothing to look up.
+func (m *Model) loadPulls(paths []string) tea.Cmd {
+	if !m.cfg.PullRequests {
+		return nil
+	}
+	want := make(map[string]bool) // path -> whether the lookup needs a token
+	if !m.showsColumn("pr") {
+		paths = nil
+	}
+	for _, p := range paths {
+		want[p] = false
+	}
+	if repo := m.selectedRepo(); repo != nil && m.showDetail {
+		if _, ok := want[repo.Path]; !ok && m.pulls[repo.Path].stale() {
+			want[repo.Path] = !m.showsColumn("pr")
+		}
+	}
+
+	type lookup struct {
+		repo      model.Repository
+		needToken bool
+	}
+	var lookups []lookup
+	for _, r := range m.repos {
+		needToken, ok := want[r.Path]
+		if ok && r.Status != nil && !m.pullsLoading[r.Path] {
+			m.pullsLoading[r.Path] = true
+			lookups = append(lookups, lookup{r, needToken})
+		}
+	}
+	if len(lookups) == 0 {
+		return nil
+	}
+
+	reader, defs, cache := m.reader, m.cfg.Forges, m.pullCache
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		results := make(map[string]pullResult, len(lookups))
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, 4)
+		for _, l := range lookups {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				cmdCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+				remotes := reader.GetRemotes(cmdCtx, l.repo.Path)
+				cancel()
+				status, err := branchStatus(ctx, cache, l.repo, remotes, defs, l.needToken)
+				mu.Lock()
+				results[l.repo.Path] = pullResult{status, err, time.Now()}
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		return pullsLoadedMsg{results}
+	}
+}
+
+// pullsStale schedules a pullsStaleMsg unless one is on its way.
+func (m *Model) pullsStale() tea.Cmd {
+	if m.pullsTicking {
+		return nil
+	}
+	m.pullsTicking = true
+	return tea.Tick(pullTTL, func(time.Time) tea.Msg { return pullsStaleMsg{} })
+}
+
+// stalePulls lists the repos on screen whose answers are stale.
+func (m *Model) stalePulls() []string {
+	end := min(m.scrollOffset+m.visibleRows(), len(m.rows))
+	var paths []string
+	for _, row := range m.rows[min(m.scrollOffset, end):end] {
+		if m.pulls[row.Repo.Path].stale() {
+			paths = append(paths, row.Repo.Path)
+		}
+	}
+	return paths
+}
+
+// branchStatus asks the forge of the remote repo's branch tracks about
+// it; a branch tracking nothing hasn't been pushed and isn't asked about.
+// Pull requests of a fork are looked for in the upstream remote's repo
+// when it is on the same forge.
+func branchStatus(ctx context.Context, cache *forge.Cache, repo model.Repository, remotes []model.Remote, defs map[string]forge.Def, needToken bool) (*forge.Status, error) {
+	if repo.Status.Remote == "" || repo.Status.DetachedHead {
+		return nil, forge.ErrNoBranch
+	}
+	head, branch := browseRemote(repo, remotes)
+	if head == nil || head.Host == "" || head.Owner == "" {
+		return nil, errNoForge
+	}
+	f, ok := forge.For(head.Host, defs)
+	if !ok {
+		return nil, errNoForge
+	}
+	if needToken && f.Token == "" {
+		return nil, errNoToken
+	}
+	p, err := f.Provider(pullClient)
+	if err != nil {
+		return nil, errNoForge
+	}
+
+	ref := forge.Ref{Owner: head.Owner, Repo: head.Repo, HeadOwner: head.Owner, HeadRepo: head.Repo, Branch: branch}
+	for _, r := range remotes {
+		if r.Name == "upstream" && r.Host == head.Host && r.Owner != "" {
+			ref.Owner, ref.Repo = r.Owner, r.Repo
+		}
+	}
+	return cache.BranchStatus(ctx, head.Host, p, ref)
+}
+
+// showsColumn reports whether the active preset shows column name.
+func (m *Model) showsColumn(name string) bool {
+	return slices.ContainsFunc(m.presets[m.preset].Columns, func(c config.ColumnConfig) bool { return c.Name == name })
+}
+
+// pullNumber is the number of the repo's pull request, or 0.
+func (m *Model) pullNumber(path string) int {
+	if s := m.pulls[path].status; s != nil && s.PR != nil {
+		return s.PR.Number
+	}
+	return 0
+}
+
+// pullStateStyle colors a pull request by its state.
+func pullStateStyle(state forge.PRState) lipgloss.Style {
+	switch state {
+	case forge.PROpen:
+		return styleAhead
+	case forge.PRMerged:
+		return styleChurn
+	}
+	return styleDim // draft, closed
+}
+
+// checkIcon is the mark and color of a checks summary; empty when there
+// are no checks.
+func checkIcon(c forge.CheckState) (string, lipgloss.Style) {
+	switch c {
+	case forge.ChecksSuccess:
+		return iconPass, styleCleanTxt
+	case forge.ChecksFailure:
+		return iconFail, styleConflict
+	case forge.ChecksPending:
+		return iconPending, styleAmber
+	}
+	return "", styleDim
+}
+
+// reviewLabel describes a review summary; empty when there are no reviews.
+func reviewLabel(r forge.ReviewState) (string, lipgloss.Style) {
+	switch r {
+	case forge.ReviewApproved:
+		return "approved", styleCleanTxt
+	case forge.ReviewChangesRequested:
+		return "changes requested", styleBehind
+	case forge.ReviewRequired:
+		return "review required", styleAmber
+	}
+	return "", styleDim
+}
+
+func (r rowRenderer) prCell(p pullResult, loading bool, width int) string {
+	var content string
+	switch s := p.status; {
+	case s != nil && s.PR != nil:
+		content = r.bg(pullStateStyle(s.PR.State)).Render(fmt.Sprintf("#%d", s.PR.Number))
+		if s.PR.State == forge.PRDraft || s.PR.State == forge.PRMerged {
+			content += r.rowBg.Render(" ") + r.bg(pullStateStyle(s.PR.State)).Render(string(s.PR.State))
+		} else if icon, style := checkIcon(s.Checks); icon != "" {
+			content += r.rowBg.Render(" ") + r.bg(style).Render(icon)
+		}
+	case s != nil:
+		if icon, style := checkIcon(s.Checks); icon != "" {
+			content = r.bg(style).Render(icon)
+		}
+	case p.known():
+		content = r.bg(styleAmber).Render("?")
+	case loading:
+		content = r.bg(styleDim).Render("...")
+	}
+	if content == "" {
+		content = r.bg(styleDim).Render("─")
+	}
+	return r.rowBg.Width(width).Render(content)
+}
+
+// renderDetailPull describes the pull request and checks of the repo's
+// branch.
+func (m *Model) renderDetailPull(path string, innerW int) []string {
+	p, ok := m.pulls[path]
+	if !ok {
+		if m.pullsLoading[path] {
+			return []string{styleDim.Render(" Loading pull request..."), ""}
+		}
+		return nil
+	}
+	if !p.known() {
+		return nil
+	}
+
+	lines := []string{styleTableHdr.Render(" PULL REQUEST")}
+	if s := p.status; s != nil {
+		var parts []string
+		if s.PR != nil {
+			num := fmt.Sprintf("#%d ", s.PR.Number)
+			lines = append(lines, " "+pullStateStyle(s.PR.State).Render(num)+
+				truncateWithEllipsis(s.PR.Title, innerW-len(num)-1))
+			parts = append(parts, pullStateStyle(s.PR.State).Render(string(s.PR.State)))
+			if label, style := reviewLabel(s.PR.Review); label != "" {
+				parts = append(parts, style.Render(label))
+			}
+		} else {
+			parts = append(parts, styleDim.Render("none for this branch"))
+		}
+		if icon, style := checkIcon(s.Checks); icon != "" {
+			parts = append(parts, style.Render(icon+" checks "+string(s.Checks)))
+		}
+		lines = append(lines, " "+strings.Join(parts, styleDim.Render(" · ")))
+		if s.PR != nil {
+			lines = append(lines, styleDim.Render(" "+truncateWithEllipsis(s.PR.URL, innerW-1)))
+		}
+	}
+	if p.err != nil {
+		lines = append(lines, styleDim.Render(" "+truncateWithEllipsis(p.err.Error(), innerW-1)))
+	}
+	return append(lines, "")
+}
@@ -3,11 +3,15 @@ package tui
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
+	"github.com/jackchuka/gv/internal/keymap"
 	"github.com/jackchuka/gv/internal/model"
+	"github.com/jackchuka/gv/internal/scanner"
 )
 
 func (m *Model) View() string {
@@ -24,6 +28,24 @@ func (m *Model) View() string {
 			strings.Join(sections, "\n"))
 	}
 
+	if m.showScan {
+		sections = append(sections, m.renderScanReport())
+		return lipgloss.Place(m.width, m.height, lipgloss.Left, lipgloss.Top,
+			strings.Join(sections, "\n"))
+	}
+
+	if m.showTags {
+		sections = append(sections, m.renderTagPicker())
+		return lipgloss.Place(m.width, m.height, lipgloss.Left, lipgloss.Top,
+			strings.Join(sections, "\n"))
+	}
+
+	if m.paletteMode {
+		sections = append(sections, m.renderPalette())
+		return lipgloss.Place(m.width, m.height, lipgloss.Left, lipgloss.Top,
+			strings.Join(sections, "\n"))
+	}
+
 	sections = append(sections, m.renderSummaryPanel())
 	sections = append(sections, m.renderTable())
 	sections = append(sections, m.renderFooter())
@@ -45,12 +67,17 @@ func (m *Model) View() string {
 }
 
 func (m *Model) renderHeader() string {
-	title := lipgloss.NewStyle().Foreground(lipgloss.Color("44")).Bold(true).Render("GitVision")
+	title := lipgloss.NewStyle().Foreground(colorTitle).Bold(true).Render("GitVision")
 
 	// Spinner
 	var spinner string
 	switch m.phase {
-	case PhaseScanning, PhaseLoading:
+	case PhaseScanning:
+		spinner = "  " + renderSpinner(m.anim.frame) + " Scanning..."
+		if m.scanFound > 0 {
+			spinner += styleDim.Render(fmt.Sprintf(" %d found", m.scanFound))
+		}
+	case PhaseLoading:
 		spinner = "  " + renderSpinner(m.anim.frame) + " Scanning..."
 	case PhaseFetching:
 		target := "all"
@@ -72,24 +99,32 @@ func (m *Model) renderHeader() string {
 	// Spaced stats: dim label + bold colored number
 	s := m.summary
 	bold := lipgloss.NewStyle().Bold(true)
-	stats := styleDim.Render("repos ") + bold.Foreground(lipgloss.Color("255")).Render(fmt.Sprintf("%d", s.TotalRepos))
+	stats := styleDim.Render("repos ") + bold.Foreground(colorFg).Render(fmt.Sprintf("%d", s.TotalRepos))
 	if s.DirtyRepos > 0 {
-		stats += "  " + styleDim.Render("dirty ") + bold.Foreground(colorDirtyAmber).Render(fmt.Sprintf("%d", s.DirtyRepos))
+		stats += "  " + styleDim.Render("dirty ") + bold.Foreground(colorDirty).Render(fmt.Sprintf("%d", s.DirtyRepos))
 	}
 	if s.AheadRepos > 0 {
-		stats += "  " + styleDim.Render("ahead ") + bold.Foreground(lipgloss.Color("73")).Render(fmt.Sprintf("%d", s.AheadRepos))
+		stats += "  " + styleDim.Render("ahead ") + bold.Foreground(colorAhead).Render(fmt.Sprintf("%d", s.AheadRepos))
 	}
 	if s.ConflictRepos > 0 {
-		stats += "  " + styleDim.Render("conflict ") + bold.Foreground(colorDangerRed).Render(fmt.Sprintf("%d", s.ConflictRepos))
+		stats += "  " + styleDim.Render("conflict ") + bold.Foreground(colorConflict).Render(fmt.Sprintf("%d", s.ConflictRepos))
 	}
 
 	// Filter display
 	left := title + spinner
-	if m.filterMode {
+	if m.tagMode {
+		left += "  " + m.tagInput.View()
+	} else if m.jumpMode {
+		left += "  " + m.jumpInput.View()
+	} else if m.filterMode {
 		left += "  " + m.filterInput.View()
 	} else if m.filterText != "" {
 		left += "  " + styleDim.Render("filter: "+m.filterText)
 	}
+	if m.filterErr != nil {
+		room := m.width - lipgloss.Width(left) - lipgloss.Width(stats) - 3
+		left += "  " + styleConflict.Render(truncateWithEllipsis(m.filterErr.Error(), room))
+	}
 
 	gap := m.width - lipgloss.Width(left) - lipgloss.Width(stats)
 	if gap < 1 {
@@ -133,7 +168,7 @@ func (m *Model) renderSummaryPanel() string {
 	}
 	changesCol := renderSummaryColumn("CHANGES", []summaryRow{
 		{styleDiffAdd, fmt.Sprintf(" staged  %3d ", s.TotalStaged), s.TotalStaged, colorDiffAdd},
-		{styleAmber, fmt.Sprintf(" mod     %3d ", s.TotalModified), s.TotalModified, colorDirtyAmber},
+		{styleAmber, fmt.Sprintf(" mod     %3d ", s.TotalModified), s.TotalModified, colorDirty},
 		{styleDim, fmt.Sprintf(" untrack %3d ", s.TotalUntracked), s.TotalUntracked, colorDim},
 	}, changesMax, barW)
 
@@ -142,9 +177,9 @@ func (m *Model) renderSummaryPanel() string {
 		syncMax = 1
 	}
 	syncCol := renderSummaryColumn("SYNC", []summaryRow{
-		{styleAhead, fmt.Sprintf(" ahead  %3d ", s.AheadRepos), s.AheadRepos, colorDirtyAmber},
-		{styleBehind, fmt.Sprintf(" behind %3d ", s.BehindRepos), s.BehindRepos, colorDangerRed},
-		{styleCleanTxt, fmt.Sprintf(" sync   %3d ", s.InSyncRepos), s.InSyncRepos, colorCleanGreen},
+		{styleAhead, fmt.Sprintf(" ahead  %3d ", s.AheadRepos), s.AheadRepos, colorAhead},
+		{styleBehind, fmt.Sprintf(" behind %3d ", s.BehindRepos), s.BehindRepos, colorBehind},
+		{styleCleanTxt, fmt.Sprintf(" sync   %3d ", s.InSyncRepos), s.InSyncRepos, colorClean},
 	}, syncMax, barW)
 
 	// DIFF column — unique last row uses renderDiffBar instead of renderHBar
@@ -167,7 +202,7 @@ func (m *Model) renderSummaryPanel() string {
 	// ACTIVITY column
 	commits := s.DailyCommits[:]
 	actCol := styleTableHdr.Render("ACTIVITY (7d)") + "\n"
-	actCol += " " + renderSparkline(commits, colorCyan) + "\n"
+	actCol += " " + renderSparkline(commits, colorAccent) + "\n"
 	totalCommits := 0
 	for _, c := range commits {
 		totalCommits += c
@@ -191,7 +226,7 @@ func (m *Model) renderTable() string {
 
 	if len(m.rows) == 0 {
 		msg := "No repos found"
-		if m.filterText != "" {
+		if m.filterText != "" || m.activeView >= 0 || m.jumpMode {
 			msg = "No repos match filter"
 		}
 		content := "\n " + styleDim.Render(msg)
@@ -203,33 +238,21 @@ func (m *Model) renderTable() string {
 		return strings.Join(lines, "\n")
 	}
 
-	contentWidth := m.width
-	detailWidth := 0
-	if m.showDetail && m.width >= 100 {
-		detailWidth = m.width * 35 / 100
-		contentWidth = m.width - detailWidth - 1
-	}
-
-	// Column widths
-	cols := computeColumns(contentWidth)
+	contentWidth, detailWidth := m.paneWidths()
+	cols := m.columns(contentWidth)
 
-	// Header
-	hdr := " " +
-		styleTableHdr.Render(padRight("REPO", cols.repo)) +
-		styleTableHdr.Render(padRight("BRANCH", cols.branch)) +
-		styleTableHdr.Render(padRight("SYNC", cols.sync)) +
-		styleTableHdr.Render(padRight("CHANGES", cols.changes)) +
-		styleTableHdr.Render(padRight("DIFF", cols.diff))
-
-	// Keep cursor in view
-	if m.cursor < m.scrollOffset {
-		m.scrollOffset = m.cursor
-	}
-	if m.cursor >= m.scrollOffset+visRows {
-		m.scrollOffset = m.cursor - visRows + 1
-	}
-	if m.scrollOffset < 0 {
-		m.scrollOffset = 0
+	// Header, marking the column sorted by
+	hdr := " "
+	for _, c := range cols {
+		title := c.title()
+		if m.sortMode == SortColumn && c.name == m.sortColumn {
+			if m.sortReverse {
+				title += " ▴"
+			} else {
+				title += " ▾"
+			}
+		}
+		hdr += styleTableHdr.Render(padRight(truncateWithEllipsis(title, c.width-1), c.width))
 	}
 
 	end := m.scrollOffset + visRows
@@ -260,9 +283,18 @@ func (m *Model) renderTable() string {
 		row := m.rows[i]
 		selected := i == m.cursor
 		parentAbove := false
-		if row.Repo != nil && row.Repo.IsWorktree && i > 0 {
-			prev := m.rows[i-1].Repo
-			parentAbove = prev != nil && prev.Path == row.Repo.MainWorktree
+		if row.Repo != nil && row.Repo.ParentPath() != "" {
+			// Siblings nested under the same parent may sit in between
+			for j := i - 1; j >= 0; j-- {
+				prev := m.rows[j].Repo
+				if prev == nil || prev.Path == row.Repo.ParentPath() {
+					parentAbove = prev != nil
+					break
+				}
+				if prev.ParentPath() == "" {
+					break
+				}
+			}
 		}
 		line := m.renderTableRow(row, cols, selected, i%2 == 1, maxDiff, contentWidth, parentAbove)
 		tableLines = append(tableLines, line)
@@ -278,9 +310,13 @@ func (m *Model) renderTable() string {
 	// Detail panel
 	if detailWidth > 0 {
 		detail := m.renderDetailPanel(detailWidth, tableHeight)
+		sepStyle := styleDim
+		if m.detailFocus {
+			sepStyle = styleKey
+		}
 		sepLines := make([]string, tableHeight)
 		for i := range sepLines {
-			sepLines[i] = styleDim.Render("│")
+			sepLines[i] = sepStyle.Render("│")
 		}
 		sep := strings.Join(sepLines, "\n")
 		return lipgloss.JoinHorizontal(lipgloss.Top, tableContent, sep, detail)
@@ -289,43 +325,6 @@ func (m *Model) renderTable() string {
 	return tableContent
 }
 
-type columnWidths struct {
-	repo    int
-	branch  int
-	changes int
-	sync    int
-	diff    int
-}
-
-func computeColumns(width int) columnWidths {
-	// Allocate proportionally, minimum widths
-	usable := width - 2 // leading space + margin
-	if usable < 40 {
-		usable = 40
-	}
-
-	c := columnWidths{
-		repo:    usable * 28 / 100,
-		branch:  usable * 25 / 100,
-		sync:    usable * 8 / 100,
-		changes: usable * 18 / 100,
-		diff:    usable * 21 / 100,
-	}
-
-	// Minimum widths
-	if c.repo < 10 {
-		c.repo = 10
-	}
-	if c.branch < 8 {
-		c.branch = 8
-	}
-	if c.sync < 8 {
-		c.sync = 8
-	}
-
-	return c
-}
-
 // --- Row rendering ---
 
 // rowRenderer holds per-row styling state shared across cell renderers.
@@ -360,6 +359,8 @@ func (m *Model) newRowRenderer(repo *model.Repository, selected, alt bool) rowRe
 	var prefix string
 	if hasGlow {
 		prefix = lipgloss.NewStyle().Foreground(glowBorderColors[step]).Render("▎")
+	} else if selected && colorSelBg == "" {
+		prefix = "›" // no row background to show the selection, e.g. with NO_COLOR
 	}
 
 	return rowRenderer{
@@ -371,14 +372,16 @@ func (m *Model) newRowRenderer(repo *model.Repository, selected, alt bool) rowRe
 	}
 }
 
-func (r rowRenderer) repoCell(repo *model.Repository, width int, selected, parentAbove bool) string {
+func (r rowRenderer) repoCell(repo *model.Repository, match []int, width int, selected, parentAbove bool) string {
 	s := repo.Status
-	wt := repo.IsWorktree
+	wt := repo.ParentPath() != "" // nested rows: worktrees and submodules
 
 	var dot string
 	switch {
 	case s != nil && s.HasSpecialState():
 		dot = r.bg(styleConflict).Render(iconConflict)
+	case s != nil && repo.IsBare():
+		dot = r.bg(styleDim).Render(iconBare)
 	case s != nil && s.IsDirty() && wt:
 		dot = r.bg(styleAmber).Render(iconDirtyWt)
 	case s != nil && s.IsDirty():
@@ -398,8 +401,8 @@ func (r rowRenderer) repoCell(repo *model.Repository, width int, selected, paren
 
 	prefix := ""
 	nameWidth := width - 3
-	if repo.IsWorktree && repo.MainWorktree != "" {
-		parentName := filepath.Base(repo.MainWorktree) + "/"
+	if parent := repo.ParentPath(); parent != "" {
+		parentName := filepath.Base(parent) + "/"
 		if parentAbove {
 			prefix = r.bg(styleDim).Render("└ ")
 			nameWidth -= 2
@@ -408,8 +411,46 @@ func (r rowRenderer) repoCell(repo *model.Repository, width int, selected, paren
 			nameWidth -= len(parentName)
 		}
 	}
+	pin := ""
+	if repo.Pinned {
+		pin = r.bg(styleAmber).Render(" " + iconStar)
+		nameWidth -= 2
+	}
 	name := truncateWithEllipsis(repo.DisplayName(), nameWidth)
-	return r.rowBg.Width(width).Render(dot + r.rowBg.Render(" ") + prefix + nameStyle.Render(name))
+
+	// Tag chips fill whatever room the name leaves
+	chips := ""
+	room := nameWidth - lipgloss.Width(name)
+	for _, tag := range repo.Tags {
+		chip := " #" + tag
+		if lipgloss.Width(chip) > room {
+			break
+		}
+		chips += r.bg(styleTag).Render(chip)
+		room -= lipgloss.Width(chip)
+	}
+	return r.rowBg.Width(width).Render(dot + r.rowBg.Render(" ") + prefix + highlightMatch(name, match, nameStyle, r.bg(styleMatch)) + chips + pin)
+}
+
+// highlightMatch renders name with the runes at the matched positions in
+// hl. name may be truncated; positions past its end are dropped.
+func highlightMatch(name string, match []int, base, hl lipgloss.Style) string {
+	if len(match) == 0 {
+		return base.Render(name)
+	}
+	var b strings.Builder
+	runes := []rune(name)
+	start := 0
+	for _, p := range match {
+		if p >= len(runes) || (p == len(runes)-1 && runes[p] == '…') {
+			break
+		}
+		b.WriteString(base.Render(string(runes[start:p])))
+		b.WriteString(hl.Render(string(runes[p])))
+		start = p + 1
+	}
+	b.WriteString(base.Render(string(runes[start:])))
+	return b.String()
 }
 
 func (r rowRenderer) branchCell(s *model.RepoStatus, width int) string {
@@ -418,7 +459,7 @@ func (r rowRenderer) branchCell(s *model.RepoStatus, width int) string {
 	}
 	branch := s.Branch
 	if branch == "" && s.DetachedHead {
-		branch = s.CommitHash[:7]
+		branch = s.CommitHash[:min(7, len(s.CommitHash))]
 	}
 	if branch == "" {
 		branch = "???"
@@ -426,10 +467,16 @@ func (r rowRenderer) branchCell(s *model.RepoStatus, width int) string {
 	return r.bg(styleBranch).Width(width).Render(truncateWithEllipsis(branch, width-1))
 }
 
-func (r rowRenderer) syncCell(s *model.RepoStatus, width int) string {
+func (r rowRenderer) syncCell(s *model.RepoStatus, moved bool, width int) string {
 	var content string
 	if s != nil {
+		if moved {
+			content += r.bg(styleAmber).Render(iconMoved)
+		}
 		if s.Ahead > 0 {
+			if content != "" {
+				content += r.rowBg.Render(" ")
+			}
 			content += r.bg(styleAhead).Render(fmt.Sprintf("%s%d", iconAhead, s.Ahead))
 		}
 		if s.Behind > 0 {
@@ -439,7 +486,7 @@ func (r rowRenderer) syncCell(s *model.RepoStatus, width int) string {
 			content += r.bg(styleBehind).Render(fmt.Sprintf("%s%d", iconBehind, s.Behind))
 		}
 		if s.HasSpecialState() {
-			content = r.bg(styleConflict).Render(specialStateLabel(s))
+			content = r.bg(styleConflict).Render(s.SpecialState())
 		}
 		if content == "" {
 			content = r.bg(styleDim).Render("──")
@@ -489,7 +536,7 @@ func (r rowRenderer) diffCell(repo *model.Repository, width int, loading bool) s
 	return r.rowBg.Width(width).Render(content)
 }
 
-func (m *Model) renderTableRow(row TableRow, cols columnWidths, selected, alt bool, maxDiff, rowWidth int, parentAbove bool) string {
+func (m *Model) renderTableRow(row TableRow, cols []column, selected, alt bool, maxDiff, rowWidth int, parentAbove bool) string {
 	repo := row.Repo
 	if repo == nil {
 		return ""
@@ -502,12 +549,10 @@ func (m *Model) renderTableRow(row TableRow, cols columnWidths, selected, alt bo
 		leading = r.prefix
 	}
 
-	line := leading +
-		r.repoCell(repo, cols.repo, selected, parentAbove) +
-		r.branchCell(repo.Status, cols.branch) +
-		r.syncCell(repo.Status, cols.sync) +
-		r.changesCell(repo.Status, cols.changes) +
-		r.diffCell(repo, cols.diff, m.diffLoading)
+	line := leading
+	for _, c := range cols {
+		line += m.cell(r, c, row, selected, parentAbove)
+	}
 
 	return r.rowBg.Width(rowWidth).Render(line)
 }
@@ -538,6 +583,10 @@ func renderDetailStatus(s *model.RepoStatus, innerW int) []string {
 		lines = append(lines, sync)
 	}
 
+	if s.ForkBehind > 0 {
+		lines = append(lines, styleBehind.Render(fmt.Sprintf(" %s%d behind %s", iconBehind, s.ForkBehind, s.ForkBranch)))
+	}
+
 	if s.Stashes > 0 {
 		lines = append(lines, styleDim.Render(fmt.Sprintf(" stashes: %d", s.Stashes)))
 	}
@@ -565,7 +614,7 @@ func renderDetailStatus(s *model.RepoStatus, innerW int) []string {
 	}
 
 	if s.HasSpecialState() {
-		label := specialStateLabel(s)
+		label := s.SpecialState()
 		lines = append(lines, styleConflict.Render(" "+iconBolt+" "+label))
 		lines = append(lines, "")
 	}
@@ -573,24 +622,35 @@ func renderDetailStatus(s *model.RepoStatus, innerW int) []string {
 	return lines
 }
 
+func renderDetailSubmodules(subs map[string]model.SubmoduleState, innerW int) []string {
+	if len(subs) == 0 {
+		return nil
+	}
+	paths := make([]string, 0, len(subs))
+	for p := range subs {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	lines := []string{styleTableHdr.Render(" SUBMODULES")}
+	for _, p := range paths {
+		label := subs[p].Label()
+		name := truncateWithEllipsis(p, innerW-lipgloss.Width(label)-4)
+		lines = append(lines, "  "+styleDim.Render(name)+" "+styleAmber.Render(label))
+	}
+	return append(lines, "")
+}
+
 func renderDetailFileList(header string, files []model.FileDiffStat, innerW int, countStyle lipgloss.Style) []string {
 	if len(files) == 0 {
 		return nil
 	}
 	var lines []string
-	lines = append(lines, styleTableHdr.Render(" "+header))
-	maxLines := 5
-	for i, f := range files {
-		if i >= maxLines {
-			lines = append(lines, styleDim.Render(fmt.Sprintf("  ...and %d more", len(files)-maxLines)))
-			break
-		}
-		fname := truncateWithEllipsis(filepath.Base(f.Path), innerW-20)
-		miniBar := renderMiniDiffBar(f.Added, f.Deleted, 6)
-		lines = append(lines, fmt.Sprintf("  %s %s %s",
-			miniBar,
-			countStyle.Render(fmt.Sprintf("+%d", f.Added)),
-			styleDim.Render(fname)))
+	lines = append(lines, styleTableHdr.Render(fmt.Sprintf(" %s (%d)", header, len(files))))
+	for _, f := range files {
+		counts := countStyle.Render(fmt.Sprintf("+%d", f.Added)) + " " + styleDiffDel.Render(fmt.Sprintf("-%d", f.Deleted))
+		fname := truncateWithEllipsis(f.Path, innerW-lipgloss.Width(counts)-11)
+		lines = append(lines, fmt.Sprintf("  %s %s %s", renderMiniDiffBar(f.Added, f.Deleted, 6), counts, styleDim.Render(fname)))
 	}
 	lines = append(lines, "")
 	return lines
@@ -622,118 +682,95 @@ func renderDetailDiff(d *model.DiffStats, innerW int) []string {
 		lines = append(lines, "")
 	}
 
-	lines = append(lines, renderDetailFileList("STAGED FILES", d.StagedFiles, innerW, styleDiffAdd)...)
-	lines = append(lines, renderDetailFileList("MODIFIED FILES", d.UnstagedFiles, innerW, styleAmber)...)
-
-	// Activity sparkline
-	lines = append(lines, styleTableHdr.Render(" ACTIVITY (7d)"))
-	commits := d.DailyCommits[:]
-	lines = append(lines, " "+renderSparkline(commits, colorCyan))
-	totalCommits := 0
-	for _, c := range commits {
-		totalCommits += c
-	}
-	lines = append(lines, styleDim.Render(fmt.Sprintf("  %d commits this week", totalCommits)))
-	lines = append(lines, "")
-
-	// Top file churn
-	topChurn := d.TopChurnFiles(5)
-	if len(topChurn) > 0 {
-		lines = append(lines, styleTableHdr.Render(" HOT FILES"))
-		maxChurn := topChurn[0].Count
-		for _, entry := range topChurn {
-			fname := truncateWithEllipsis(filepath.Base(entry.Path), innerW-12)
-			bar := renderChurnBar(entry.Count, maxChurn, 6)
-			lines = append(lines, fmt.Sprintf("  %s %s %s",
-				bar,
-				lipgloss.NewStyle().Foreground(colorChurn).Render(fmt.Sprintf("%d", entry.Count)),
-				styleDim.Render(fname)))
-		}
-	}
-
 	return lines
 }
 
-func (m *Model) renderDetailPanel(width, height int) string {
-	repo := m.selectedRepo()
-	if repo == nil {
-		return padLines(styleDim.Render(" No selection"), width, height)
+// repoSettings summarizes the group, tags and fetch setting from the repos
+// section of the config.
+func repoSettings(repo *model.Repository) string {
+	var parts []string
+	if repo.Group != "" {
+		parts = append(parts, "group "+repo.Group)
 	}
-
-	var lines []string
-	innerW := width - 2
-
-	lines = append(lines, styleRepoName.Render(" "+repo.DisplayName()))
-	lines = append(lines, styleDim.Render(" "+repo.Path))
-	lines = append(lines, "")
-
-	if repo.Status != nil {
-		lines = append(lines, renderDetailStatus(repo.Status, innerW)...)
+	for _, tag := range repo.Tags {
+		parts = append(parts, "#"+tag)
 	}
-
-	if repo.Diff != nil {
-		lines = append(lines, renderDetailDiff(repo.Diff, innerW)...)
-	} else if m.diffLoading {
-		lines = append(lines, styleDim.Render(" Loading diff stats..."))
+	if repo.FetchDisabled {
+		parts = append(parts, "fetch disabled")
 	}
-
-	return padLines(strings.Join(lines, "\n"), width, height)
+	return strings.Join(parts, " · ")
 }
 
 // --- Footer, toasts, help ---
 
 func (m *Model) renderFooter() string {
 	sep := styleDim.Render(strings.Repeat("─", m.width))
+	parts, _ := m.footerParts()
+	return sep + "\n " + truncateWithEllipsis(strings.Join(parts, "  "), m.width-2)
+}
 
-	type viewTab struct {
-		key    string
-		label  string
-		filter ViewFilter
-	}
-	tabs := []viewTab{
-		{"1", "all", ViewAll},
-		{"2", "dirty", ViewDirty},
-		{"3", "ahead", ViewUnpushed},
-		{"4", "conflict", ViewConflicts},
-	}
-
+// footerParts returns the footer's hints, and where each is for clicks.
+func (m *Model) footerParts() ([]string, []footerZone) {
 	var parts []string
-	parts = append(parts, styleKey.Render("/")+" search")
-	parts = append(parts, styleKey.Render("f")+" fetch")
-	parts = append(parts, styleKey.Render("e")+" editor")
-
-	for _, t := range tabs {
-		if m.viewFilter == t.filter {
-			parts = append(parts, styleActiveTab.Render(t.key+" "+t.label))
-		} else {
-			parts = append(parts, styleKey.Render(t.key)+" "+t.label)
+	var zones []footerZone
+	x := 1 // column of the next part
+	add := func(part string, z footerZone) {
+		z.start, z.end = x, x+lipgloss.Width(part)
+		zones = append(zones, z)
+		parts = append(parts, part)
+		x = z.end + 2
+	}
+	// hint shows the key of action with label, skipping unbound actions
+	// unless they are active
+	hint := func(action, label string, active bool) {
+		k := m.keys.short(action)
+		z := footerZone{action: action, view: -1}
+		switch {
+		case active && k == "":
+			add(styleActiveTab.Render(label), z)
+		case active:
+			add(styleActiveTab.Render(k+" "+label), z)
+		case k != "":
+			add(styleKey.Render(k)+" "+label, z)
 		}
 	}
 
-	// Sort mode indicators
-	switch m.sortMode {
-	case SortDiff:
-		parts = append(parts, styleActiveTab.Render("5 diff"))
-	default:
-		parts = append(parts, styleKey.Render("5")+" diff")
-	}
-	switch m.sortMode {
-	case SortChurn:
-		parts = append(parts, styleActiveTab.Render("6 churn"))
-	default:
-		parts = append(parts, styleKey.Render("6")+" churn")
+	if m.keySeq != nil {
+		add(styleActiveTab.Render(strings.Join(m.keySeq, "")+"…"), footerZone{view: -1})
 	}
+	hint(keymap.Filter, "search", false)
+	hint(keymap.Jump, "jump", false)
+	hint(keymap.Palette, "commands", false)
+	hint(keymap.Fetch, "fetch", false)
+	hint(keymap.Editor, "editor", false)
 
-	if m.showDetail {
-		parts = append(parts, styleActiveTab.Render("d detail"))
-	} else {
-		parts = append(parts, styleKey.Render("d")+" detail")
-	}
+	hint(keymap.ViewAll, "all", m.viewFilter == ViewAll)
+	hint(keymap.ViewDirty, "dirty", m.viewFilter == ViewDirty)
+	hint(keymap.ViewAhead, "ahead", m.viewFilter == ViewUnpushed)
+	hint(keymap.ViewConflicts, "conflict", m.viewFilter == ViewConflicts)
+	hint(keymap.ViewPinned, "pinned", m.viewFilter == ViewPinned)
 
-	parts = append(parts, styleKey.Render("?")+" help")
-	parts = append(parts, styleKey.Render("q")+" quit")
+	for i, v := range m.views {
+		label := v.name
+		if v.key.Enabled() {
+			label = v.key.Help().Key + " " + v.name
+		}
+		if i == m.activeView {
+			add(styleActiveTab.Render(label), footerZone{view: i})
+		} else if v.key.Enabled() {
+			add(styleKey.Render(v.key.Help().Key)+" "+v.name, footerZone{view: i})
+		}
+	}
 
-	return sep + "\n " + truncateWithEllipsis(strings.Join(parts, "  "), m.width-2)
+	// Sort mode indicators
+	hint(keymap.SortDiff, "diff", m.sortMode == SortDiff)
+	hint(keymap.SortChurn, "churn", m.sortMode == SortChurn)
+	hint(keymap.Detail, "detail", m.showDetail)
+
+	hint(keymap.Columns, m.presets[m.preset].Name, false)
+	hint(keymap.Help, "help", false)
+	hint(keymap.Quit, "quit", false)
+	return parts, zones
 }
 
 func (m *Model) renderToasts() string {
@@ -743,13 +780,13 @@ func (m *Model) renderToasts() string {
 		var icon string
 		switch t.Level {
 		case ToastSuccess:
-			bc = colorGold
+			bc = colorHighlight
 			icon = iconStar + " "
 		case ToastError:
-			bc = colorDangerRed
+			bc = colorDanger
 			icon = iconConflict + " "
 		default:
-			bc = colorCyan
+			bc = colorAccent
 			icon = ""
 		}
 		box := styleToastBox.BorderForeground(bc).Render(icon + t.Message)
@@ -759,13 +796,25 @@ func (m *Model) renderToasts() string {
 }
 
 func (m *Model) renderHelp() string {
-	content := m.keys.helpText()
+	var views []string
+	if len(m.views) > 0 {
+		block := "Saved views"
+		for _, v := range m.views {
+			k := m.keys.short(keymap.NextView)
+			if v.key.Enabled() {
+				k = v.key.Help().Key
+			}
+			block += "\n  " + padRight(k, 12) + v.name
+		}
+		views = append(views, block)
+	}
+	content := m.keys.helpText(views...)
 
 	box := lipgloss.NewStyle().
 		Border(lipgloss.DoubleBorder()).
-		BorderForeground(colorCyan).
+		BorderForeground(colorAccent).
 		Padding(1, 2).
-		Width(50).
+		Width(2*helpColumnWidth + 6).
 		Render(styleTitle.Render("HELP") + "\n\n" + content + "\n\n" + styleDim.Render("press any key to close"))
 
 	availH := m.height - 4
@@ -775,6 +824,128 @@ func (m *Model) renderHelp() string {
 	return lipgloss.Place(m.width, availH, lipgloss.Center, lipgloss.Center, box)
 }
 
+// maxScanErrors caps the errors listed in the scan report overlay.
+const maxScanErrors = 10
+
+func (m *Model) renderScanReport() string {
+	var lines []string
+	res := m.scanResult
+	if res == nil {
+		lines = append(lines, styleDim.Render("No scan has completed yet."))
+	} else {
+		lines = append(lines, fmt.Sprintf("%d repos in %d directories, %s",
+			len(res.Repos), res.Dirs, res.Duration.Round(time.Millisecond)))
+
+		if counts := res.SkipCounts(); len(counts) > 0 {
+			lines = append(lines, "", styleTableHdr.Render("Skipped"))
+			for _, reason := range scanner.SkipReasons {
+				if n := counts[reason]; n > 0 {
+					lines = append(lines, "  "+padRight(string(reason), 12)+fmt.Sprintf("%d", n))
+				}
+			}
+		}
+
+		if len(res.Errors) > 0 {
+			lines = append(lines, "", styleTableHdr.Render("Errors"))
+			for i, e := range res.Errors {
+				if i == maxScanErrors {
+					lines = append(lines, styleDim.Render(fmt.Sprintf("  ...and %d more", len(res.Errors)-maxScanErrors)))
+					break
+				}
+				lines = append(lines, "  "+styleConflict.Render(padRight(string(e.Kind), 12))+truncateWithEllipsis(e.Path, 50))
+			}
+		}
+	}
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(colorAccent).
+		Padding(1, 2).
+		Width(70).
+		Render(styleTitle.Render("SCAN REPORT") + "\n\n" + strings.Join(lines, "\n") + "\n\n" + styleDim.Render("press any key to close"))
+
+	availH := m.height - 4
+	if availH < 10 {
+		availH = 10
+	}
+	return lipgloss.Place(m.width, availH, lipgloss.Center, lipgloss.Center, box)
+}
+
+func (m *Model) renderTagPicker() string {
+	availH := m.height - 4
+	if availH < 10 {
+		availH = 10
+	}
+
+	// Keep the cursor in view when there are more tags than fit
+	tags := allTags(m.repos)
+	maxRows := max(availH-8, 1)
+	first := max(0, min(m.tagCursor-maxRows/2, len(tags)-maxRows))
+	var lines []string
+	for i := first; i < len(tags) && i < first+maxRows; i++ {
+		cursor, style := "  ", styleTag
+		if i == m.tagCursor {
+			cursor, style = styleKey.Render("> "), styleTag.Bold(true)
+		}
+		lines = append(lines, cursor+style.Render(padRight("#"+tags[i].tag, 30))+styleDim.Render(fmt.Sprintf("%d", tags[i].count)))
+	}
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(colorAccent).
+		Padding(1, 2).
+		Width(50).
+		Render(styleTitle.Render("TAGS") + "\n\n" + strings.Join(lines, "\n") + "\n\n" + styleDim.Render("j/k move  enter filter  any other key closes"))
+
+	return lipgloss.Place(m.width, availH, lipgloss.Center, lipgloss.Center, box)
+}
+
+func (m *Model) renderPalette() string {
+	availH := m.height - 4
+	if availH < 10 {
+		availH = 10
+	}
+
+	title := styleTitle.Render("COMMANDS")
+	if repo := m.selectedRepo(); repo != nil {
+		title += styleDim.Render("  " + repo.DisplayName())
+	}
+
+	// Keep the cursor in view when there are more entries than fit
+	rows := m.paletteRows
+	maxRows := max(availH-10, 1)
+	first := max(0, min(m.paletteCursor-maxRows/2, len(rows)-maxRows))
+	var lines []string
+	for i := first; i < len(rows) && i < first+maxRows; i++ {
+		r := rows[i]
+		if r.recent && (i == first || !rows[i-1].recent) {
+			lines = append(lines, styleDim.Render("  Recent"))
+		} else if i > 0 && rows[i-1].recent && !r.recent {
+			lines = append(lines, "")
+		}
+		cursor, base := "  ", styleFg
+		if i == m.paletteCursor {
+			cursor, base = styleKey.Render("> "), styleFg.Bold(true)
+		}
+		name := highlightMatch(truncateWithEllipsis(r.entry.title, 32), r.positions, base, styleMatch)
+		lines = append(lines, cursor+padRight(name, 34)+
+			styleDim.Render(padRight(r.entry.group, 14))+styleKey.Render(r.entry.key))
+	}
+	if len(rows) == 0 {
+		lines = append(lines, styleDim.Render("  no matching commands"))
+	}
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(colorAccent).
+		Padding(1, 2).
+		Width(64).
+		Render(title + "\n\n" + m.paletteInput.View() + "\n\n" + strings.Join(lines, "\n") +
+			"\n\n" + styleDim.Render("↑/↓ move  enter run  esc close"))
+
+	return lipgloss.Place(m.width, availH, lipgloss.Center, lipgloss.Center, box)
+}
+
 // --- Layout utilities ---
 
 // placeOverlay writes fg on top of bg at the given column (x) and row (y).
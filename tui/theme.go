@@ -4,51 +4,47 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jackchuka/gv/internal/config"
+	"github.com/jackchuka/gv/internal/theme"
 )
 
-// ANSI 256 color palette
+// Palette, set from the theme by setTheme
 var (
-	// Status colors (carried from V2)
-	colorCleanGreen = lipgloss.Color("71")
-	colorDirtyAmber = lipgloss.Color("179")
-	colorDangerRed  = lipgloss.Color("167")
-	colorCriticalRd = lipgloss.Color("196")
+	// Status
+	colorClean    lipgloss.Color
+	colorDirty    lipgloss.Color
+	colorAhead    lipgloss.Color
+	colorBehind   lipgloss.Color
+	colorDanger   lipgloss.Color
+	colorConflict lipgloss.Color
 
 	// Accent
-	colorCyan = lipgloss.Color("73")
-	colorGold = lipgloss.Color("220")
+	colorAccent    lipgloss.Color
+	colorHighlight lipgloss.Color
+	colorTitle     lipgloss.Color
 
 	// Text
-	colorFg  = lipgloss.Color("253")
-	colorDim = lipgloss.Color("242")
+	colorFg  lipgloss.Color
+	colorDim lipgloss.Color
 
 	// Selection
-	colorSelBg = lipgloss.Color("238")
-	colorSelFg = lipgloss.Color("255")
+	colorSelBg lipgloss.Color
+	colorSelFg lipgloss.Color
 
 	// V3-specific
-	colorBlue     = lipgloss.Color("69")  // net delta, accent
-	colorDiffAdd  = lipgloss.Color("71")  // added lines (same as clean green)
-	colorDiffDel  = lipgloss.Color("167") // deleted lines (same as danger red)
-	colorBarEmpty = lipgloss.Color("238") // ░ empty bar segments
-	colorTableHdr = lipgloss.Color("245") // table header text
-	colorRowAlt   = lipgloss.Color("234") // alternating row bg
-	colorChurn    = lipgloss.Color("208") // churn/activity (orange)
+	colorTag      lipgloss.Color // tag chips, net delta
+	colorDiffAdd  lipgloss.Color // added lines
+	colorDiffDel  lipgloss.Color // deleted lines
+	colorBarEmpty lipgloss.Color // ░ empty bar segments
+	colorHeader   lipgloss.Color // table header text
+	colorRowAlt   lipgloss.Color // alternating row bg
+	colorChurn    lipgloss.Color // churn/activity
+
+	// Left-border accent: flash bright/off, then fade out
+	glowBorderColors []lipgloss.Color
 )
 
-// Left-border accent: flash bright/off, then fade out
-var glowBorderColors = []lipgloss.Color{
-	lipgloss.Color("46"),  // on
-	lipgloss.Color("236"), // off
-	lipgloss.Color("46"),  // on
-	lipgloss.Color("236"), // off
-	lipgloss.Color("46"),  // on
-	lipgloss.Color("34"),  // fade
-	lipgloss.Color("28"),  // fade
-	lipgloss.Color("23"),  // fade
-	lipgloss.Color("236"), // gone
-}
-
 // Braille spinner frames
 var spinnerFrames = []string{"⣾", "⣽", "⣻", "⢿", "⡿", "⣟", "⣯", "⣷"}
 
@@ -58,43 +54,121 @@ const (
 	iconDirty    = "●"
 	iconCleanWt  = "◇"
 	iconDirtyWt  = "◆"
+	iconBare     = "□"
 	iconConflict = "⚠"
 	iconBranch   = "⟫"
 	iconAhead    = "↑"
 	iconBehind   = "↓"
+	iconMoved    = "±"
 	iconBolt     = "⚡"
 	iconStar     = "★"
+	iconPass     = "✓"
+	iconFail     = "✗"
+	iconPending  = "◌"
 )
 
-// Lipgloss styles
+// Lipgloss styles, set from the palette by setTheme
 var (
-	styleTitle    = lipgloss.NewStyle().Bold(true).Foreground(colorCyan)
-	styleDim      = lipgloss.NewStyle().Foreground(colorDim)
-	styleRepoName = lipgloss.NewStyle().Foreground(colorFg).Bold(true)
-	styleBranch   = lipgloss.NewStyle().Foreground(colorCyan)
-	styleAhead    = lipgloss.NewStyle().Foreground(colorDirtyAmber)
-	styleBehind   = lipgloss.NewStyle().Foreground(colorDangerRed)
-	styleCleanTxt = lipgloss.NewStyle().Foreground(colorCleanGreen)
-	styleConflict = lipgloss.NewStyle().Foreground(colorCriticalRd).Bold(true)
-	styleAmber    = lipgloss.NewStyle().Foreground(colorDirtyAmber)
-
-	styleDiffAdd  = lipgloss.NewStyle().Foreground(colorDiffAdd)
-	styleDiffDel  = lipgloss.NewStyle().Foreground(colorDiffDel)
-	styleNetDelta = lipgloss.NewStyle().Foreground(colorBlue)
-	styleBarEmpty = lipgloss.NewStyle().Foreground(colorBarEmpty)
-	styleTableHdr = lipgloss.NewStyle().Foreground(colorTableHdr).Bold(true)
-
-	styleKey       = lipgloss.NewStyle().Foreground(colorCyan).Bold(true)
-	styleActiveTab = lipgloss.NewStyle().Foreground(colorCyan).Bold(true).Underline(true)
+	styleTitle    lipgloss.Style
+	styleDim      lipgloss.Style
+	styleRepoName lipgloss.Style
+	styleBranch   lipgloss.Style
+	styleAhead    lipgloss.Style
+	styleBehind   lipgloss.Style
+	styleCleanTxt lipgloss.Style
+	styleConflict lipgloss.Style
+	styleAmber    lipgloss.Style
+	styleTag      lipgloss.Style
+	styleMatch    lipgloss.Style
+	styleFg       lipgloss.Style
+	styleChurn    lipgloss.Style
+
+	styleDiffAdd  lipgloss.Style
+	styleDiffDel  lipgloss.Style
+	styleNetDelta lipgloss.Style
+	styleBarEmpty lipgloss.Style
+	styleTableHdr lipgloss.Style
+
+	styleKey       lipgloss.Style
+	styleActiveTab lipgloss.Style
 
 	styleToastBox = lipgloss.NewStyle().
 			Border(lipgloss.DoubleBorder()).
 			Padding(0, 1)
 )
 
+func init() {
+	dark, _ := theme.Builtin("dark")
+	setTheme(dark)
+}
+
+// ResolveTheme picks the theme the config asks for. For auto it reads the
+// terminal background from COLORFGBG, or else asks the terminal.
+func ResolveTheme(cfg *config.Config) (theme.Theme, error) {
+	dark := func() bool {
+		if d, ok := theme.DarkFromEnv(); ok {
+			return d
+		}
+		return lipgloss.HasDarkBackground()
+	}
+	return theme.Resolve(cfg.Theme, cfg.Themes, cfg.Colors, dark)
+}
+
+// setTheme sets the palette and the styles built from it.
+func setTheme(t theme.Theme) {
+	c := func(r theme.Role) lipgloss.Color { return lipgloss.Color(t[r]) }
+
+	colorClean = c(theme.Clean)
+	colorDirty = c(theme.Dirty)
+	colorAhead = c(theme.Ahead)
+	colorBehind = c(theme.Behind)
+	colorDanger = c(theme.Danger)
+	colorConflict = c(theme.Conflict)
+	colorAccent = c(theme.Accent)
+	colorHighlight = c(theme.Highlight)
+	colorTitle = c(theme.Title)
+	colorFg = c(theme.Fg)
+	colorDim = c(theme.Dim)
+	colorSelBg = c(theme.SelectionBg)
+	colorSelFg = c(theme.SelectionFg)
+	colorTag = c(theme.Tag)
+	colorDiffAdd = c(theme.Added)
+	colorDiffDel = c(theme.Deleted)
+	colorBarEmpty = c(theme.BarEmpty)
+	colorHeader = c(theme.Header)
+	colorRowAlt = c(theme.RowAlt)
+	colorChurn = c(theme.Churn)
+
+	on, off := c(theme.Glow), c(theme.GlowOff)
+	glowBorderColors = []lipgloss.Color{on, off, on, off, on, c(theme.GlowFade), c(theme.GlowFade2), c(theme.GlowFade3), off}
+
+	styleTitle = lipgloss.NewStyle().Bold(true).Foreground(colorAccent)
+	styleDim = lipgloss.NewStyle().Foreground(colorDim)
+	styleRepoName = lipgloss.NewStyle().Foreground(colorFg).Bold(true)
+	styleBranch = lipgloss.NewStyle().Foreground(colorAccent)
+	styleAhead = lipgloss.NewStyle().Foreground(colorAhead)
+	styleBehind = lipgloss.NewStyle().Foreground(colorBehind)
+	styleCleanTxt = lipgloss.NewStyle().Foreground(colorClean)
+	styleConflict = lipgloss.NewStyle().Foreground(colorConflict).Bold(true)
+	styleAmber = lipgloss.NewStyle().Foreground(colorDirty)
+	styleTag = lipgloss.NewStyle().Foreground(colorTag)
+	styleMatch = lipgloss.NewStyle().Foreground(colorHighlight).Bold(true)
+	styleFg = lipgloss.NewStyle().Foreground(colorFg)
+	styleChurn = lipgloss.NewStyle().Foreground(colorChurn)
+
+	styleDiffAdd = lipgloss.NewStyle().Foreground(colorDiffAdd)
+	styleDiffDel = lipgloss.NewStyle().Foreground(colorDiffDel)
+	styleNetDelta = lipgloss.NewStyle().Foreground(colorTag)
+	styleBarEmpty = lipgloss.NewStyle().Foreground(colorBarEmpty)
+	styleTableHdr = lipgloss.NewStyle().Foreground(colorHeader).Bold(true)
+
+	styleKey = lipgloss.NewStyle().Foreground(colorAccent).Bold(true)
+	styleActiveTab = lipgloss.NewStyle().Foreground(colorAccent).Bold(true).Underline(true)
+}
+
 func renderSpinner(frame int) string {
 	f := spinnerFrames[frame%len(spinnerFrames)]
-	return lipgloss.NewStyle().Foreground(colorCyan).Render(f)
+	return lipgloss.NewStyle().Foreground(colorAccent).Render(f)
 }
 
 func truncateWithEllipsis(s string, maxWidth int) string {
@@ -2,14 +2,30 @@ package tui
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jackchuka/gv/internal/forge"
+	"github.com/jackchuka/gv/internal/keymap"
+	"github.com/jackchuka/gv/internal/notify"
+	"github.com/jackchuka/gv/internal/status"
 )
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	if _, ok := msg.(animTickMsg); !ok { // animation doesn't move anything
+		m.syncView()
+	}
+	return model, cmd
+}
+
+func (m *Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -25,46 +41,111 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
-		return m.handleKey(msg)
+		model, cmd := m.handleKey(msg)
+		return model, tea.Batch(cmd, m.loadDetail(), m.loadPulls(nil))
+
+	case repoFoundMsg:
+		if msg.scanID != m.scanID {
+			return m, msg.next // drain a superseded scan
+		}
+		m.scanFound++
+		known := false
+		for _, r := range m.repos {
+			if r.Path == msg.repo.Path {
+				known = true
+				break
+			}
+		}
+		if !known {
+			m.applyState(&msg.repo)
+			m.repos = append(m.repos, msg.repo)
+			m.refresh()
+		}
+		return m, msg.next
 
-	case reposLoadedMsg:
-		m.repos = msg.repos
+	case scanDoneMsg:
+		if msg.scanID != m.scanID {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.phase = PhaseIdle
+			return m, m.addToast("Error: "+msg.err.Error(), ToastError)
+		}
+		m.scanResult = msg.result
+		m.repos = msg.result.Repos
+		clear(m.pinnedByConfig)
+		clear(m.configTags)
+		for i := range m.repos {
+			m.applyState(&m.repos[i])
+		}
 		m.phase = PhaseLoading
 		m.buildRows()
 
+		status.ConfigureRemotes(m.reader, m.repos)
 		if m.watcher != nil {
+			iv, perRepo := m.watcher.(interface{ SetInterval(string, time.Duration) })
 			for _, r := range m.repos {
 				_ = m.watcher.Watch(r.Path)
+				if perRepo {
+					iv.SetInterval(r.Path, r.PollInterval)
+				}
 			}
 		}
 
+		var toast tea.Cmd
+		if n := len(msg.result.Errors); n > 0 {
+			toast = m.addToast(fmt.Sprintf("Scan: %d unreadable", n)+m.keys.hint(keymap.ScanReport, "for details"), ToastError)
+		}
+
 		if len(m.repos) > 0 {
-			return m, tea.Batch(m.loadStatuses(), m.ensureAnimTick())
+			return m, tea.Batch(m.loadStatuses(), m.ensureAnimTick(), toast)
 		}
 		m.phase = PhaseIdle
-		return m, nil
+		return m, toast
 
 	case statusUpdatedMsg:
 		m.phase = PhaseIdle
 		firstLoad := true
+		var events []notify.Event
 		for i := range m.repos {
 			if m.repos[i].Status != nil {
 				firstLoad = false
 			}
 			if s, ok := msg.statuses[m.repos[i].Path]; ok {
-				m.repos[i].Status = s
-				m.repos[i].LastScanned = time.Now()
+				events = append(events, m.setStatus(i, s)...)
+				m.invalidateDetail(m.repos[i].Path) // branches and stashes may have changed too
 			}
 		}
 		m.refresh()
+		paths := slices.Collect(maps.Keys(msg.statuses))
+		aliases := m.loadAliases(paths)
+		pulls := m.loadPulls(paths)
 
 		// Trigger diff stats load on first status load
 		if firstLoad {
 			m.diffLoading = true
-			return m, tea.Batch(m.loadDiffStats(), m.ensureAnimTick())
+			return m, tea.Batch(m.loadDiffStats(), m.ensureAnimTick(), aliases, pulls, m.loadDetail())
 		}
+		return m, tea.Batch(m.dispatchNotifications(events), aliases, pulls, m.loadDetail())
+
+	case aliasesLoadedMsg:
+		m.setAliases(msg.outputs)
 		return m, nil
 
+	case pullsLoadedMsg:
+		for path, p := range msg.results {
+			delete(m.pullsLoading, path)
+			m.pulls[path] = p
+		}
+		return m, m.pullsStale()
+
+	case pullsStaleMsg:
+		m.pullsTicking = false
+		if cmd := m.loadPulls(m.stalePulls()); cmd != nil {
+			return m, cmd
+		}
+		return m, m.pullsStale() // all fresh from other lookups: check again later
+
 	case diffStatsLoadedMsg:
 		m.diffLoading = false
 		for i := range m.repos {
@@ -79,6 +160,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.phase = PhaseIdle
 		m.fetchTarget = ""
 		delete(m.anim.fetchShimmer, msg.path)
+		m.invalidateDetail(msg.path) // remote branches moved, even if the fetch failed part way
 
 		var cmds []tea.Cmd
 		if msg.err != nil {
@@ -86,8 +168,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else if msg.status != nil {
 			for i := range m.repos {
 				if m.repos[i].Path == msg.path {
-					m.repos[i].Status = msg.status
-					m.repos[i].LastScanned = time.Now()
+					cmds = append(cmds, m.dispatchNotifications(m.setStatus(i, msg.status)))
 					break
 				}
 			}
@@ -101,9 +182,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			cmds = append(cmds, m.addToast("Fetched "+name, ToastSuccess))
-			cmds = append(cmds, m.refreshDiffStats(msg.path))
+			cmds = append(cmds, m.refreshDiffStats(msg.path), m.loadAliases([]string{msg.path}), m.loadPulls([]string{msg.path}))
 		}
-		return m, tea.Batch(cmds...)
+		return m, tea.Batch(append(cmds, m.loadDetail())...)
 
 	case fetchAllCompletedMsg:
 		m.phase = PhaseIdle
@@ -113,11 +194,12 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		errCount := len(msg.errors)
+		var events []notify.Event
 		for i := range m.repos {
 			if s, ok := msg.statuses[m.repos[i].Path]; ok {
-				m.repos[i].Status = s
-				m.repos[i].LastScanned = time.Now()
+				events = append(events, m.setStatus(i, s)...)
 			}
+			m.invalidateDetail(m.repos[i].Path)
 		}
 		m.refresh()
 
@@ -132,6 +214,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.addToast(toastMsg, ToastSuccess),
 			m.loadDiffStats(),
 			m.ensureAnimTick(),
+			m.dispatchNotifications(events),
+			m.loadDetail(),
+			m.loadPulls(slices.Collect(maps.Keys(msg.statuses))),
 		)
 
 	case repoChangedMsg:
@@ -149,6 +234,27 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.ensureAnimTick(),
 		)
 
+	case tea.MouseMsg:
+		model, cmd := m.handleMouse(msg)
+		return model, tea.Batch(cmd, m.loadDetail(), m.loadPulls(nil))
+
+	case detailLoadedMsg:
+		if msg.gen != m.detailGen[msg.path] {
+			return m, nil // read before the repo changed
+		}
+		delete(m.detailLoading, msg.path)
+		m.details[msg.path] = msg.detail
+		return m, nil
+
+	case commandDoneMsg:
+		return m, m.commandDone(msg)
+
+	case browsedMsg:
+		if msg.err != nil {
+			return m, m.addToast("Browse: "+msg.err.Error(), ToastError)
+		}
+		return m, m.addToast("Opened "+msg.url, ToastInfo)
+
 	case errMsg:
 		m.phase = PhaseIdle
 		return m, m.addToast("Error: "+msg.err.Error(), ToastError)
@@ -172,54 +278,130 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	// Filter mode
-	if m.filterMode {
+	// Scan report overlay — any key closes
+	if m.showScan {
+		m.showScan = false
+		return m, nil
+	}
+
+	// Tag picker — enter filters by the selected tag, any other key closes
+	if m.showTags {
+		tags := allTags(m.repos)
+		switch {
+		case key.Matches(msg, m.keys.Down):
+			if m.tagCursor < len(tags)-1 {
+				m.tagCursor++
+			}
+		case key.Matches(msg, m.keys.Up):
+			if m.tagCursor > 0 {
+				m.tagCursor--
+			}
+		case key.Matches(msg, m.keys.Enter):
+			m.showTags = false
+			if m.tagCursor < len(tags) {
+				m.filterInput.SetValue("#" + tags[m.tagCursor].tag)
+				m.setFilter(m.filterInput.Value())
+			}
+		default:
+			m.showTags = false
+		}
+		return m, nil
+	}
+
+	// Tag editing
+	if m.tagMode {
 		switch {
 		case key.Matches(msg, m.keys.Escape):
-			m.filterMode = false
-			m.filterInput.Reset()
-			m.filterText = ""
-			m.buildRows()
+			m.tagMode = false
+			m.tagInput.Blur()
 			return m, nil
 		case key.Matches(msg, m.keys.Enter):
-			m.filterMode = false
-			m.filterText = m.filterInput.Value()
-			m.buildRows()
-			return m, nil
+			m.tagMode = false
+			m.tagInput.Blur()
+			return m, m.saveTags()
 		default:
 			var cmd tea.Cmd
-			m.filterInput, cmd = m.filterInput.Update(msg)
-			m.filterText = m.filterInput.Value()
-			m.buildRows()
+			m.tagInput, cmd = m.tagInput.Update(msg)
 			return m, cmd
 		}
 	}
 
-	// Normal mode
-	switch {
-	case key.Matches(msg, m.keys.Quit):
+	// Filter mode
+	if m.filterMode {
+		return m.handleFilterKey(msg)
+	}
+
+	// Quick jump
+	if m.jumpMode {
+		return m.handleJumpKey(msg)
+	}
+
+	// Command palette
+	if m.paletteMode {
+		return m.handlePaletteKey(msg)
+	}
+
+	// Clear the filter, or an unfinished key sequence
+	if key.Matches(msg, m.keys.Escape) {
+		if m.keySeq != nil {
+			m.keySeq = nil
+			return m, nil
+		}
+		if m.detailFocus {
+			m.detailFocus = false
+			return m, nil
+		}
+		m.filterInput.Reset()
+		m.setFilter("")
+		return m, nil
+	}
+
+	// Saved views
+	if m.keySeq == nil {
+		if i := m.viewIndex(msg); i >= 0 {
+			m.selectView(i)
+			return m, nil
+		}
+	}
+
+	action, pending := m.keys.lookup(m.keySeq, msg)
+	m.keySeq = pending
+	if action == "" {
+		return m, nil
+	}
+	return m.dispatch(action)
+}
+
+// dispatch runs a normal-mode action.
+func (m *Model) dispatch(action string) (tea.Model, tea.Cmd) {
+	if m.detailFocus && m.scrollDetail(action) {
+		return m, nil
+	}
+
+	switch action {
+	case keymap.Quit:
 		return m, tea.Quit
 
 	// Navigation
-	case key.Matches(msg, m.keys.Down):
+	case keymap.Down:
 		if m.cursor < len(m.rows)-1 {
 			m.cursor++
 		}
 
-	case key.Matches(msg, m.keys.Up):
+	case keymap.Up:
 		if m.cursor > 0 {
 			m.cursor--
 		}
 
-	case key.Matches(msg, m.keys.Top):
+	case keymap.Top:
 		m.cursor = 0
 
-	case key.Matches(msg, m.keys.Bottom):
+	case keymap.Bottom:
 		if len(m.rows) > 0 {
 			m.cursor = len(m.rows) - 1
 		}
 
-	case key.Matches(msg, m.keys.HalfDown):
+	case keymap.HalfDown:
 		m.cursor += m.visibleRows() / 2
 		if m.cursor >= len(m.rows) {
 			m.cursor = len(m.rows) - 1
@@ -228,30 +410,38 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.cursor = 0
 		}
 
-	case key.Matches(msg, m.keys.HalfUp):
+	case keymap.HalfUp:
 		m.cursor -= m.visibleRows() / 2
 		if m.cursor < 0 {
 			m.cursor = 0
 		}
 
 	// Filter
-	case key.Matches(msg, m.keys.Filter):
+	case keymap.Filter:
 		m.filterMode = true
+		m.historyPos = len(m.state.History)
 		m.filterInput.Focus()
 		return m, textinput.Blink
 
-	case key.Matches(msg, m.keys.Escape):
-		m.filterText = ""
-		m.filterInput.Reset()
-		m.buildRows()
+	case keymap.Jump:
+		return m, m.startJump()
+
+	case keymap.Palette:
+		return m, m.startPalette()
 
 	// Actions
-	case key.Matches(msg, m.keys.Reload):
+	case keymap.Reload:
 		m.phase = PhaseScanning
 		return m, tea.Batch(m.loadRepos(), m.ensureAnimTick())
 
-	case key.Matches(msg, m.keys.Fetch):
+	case keymap.ScanReport:
+		m.showScan = true
+
+	case keymap.Fetch:
 		repo := m.selectedRepo()
+		if repo != nil && repo.FetchDisabled {
+			return m, m.addToast("Fetching is disabled for "+repo.DisplayName(), ToastInfo)
+		}
 		if repo != nil {
 			m.phase = PhaseFetching
 			m.fetchTarget = repo.Path
@@ -259,62 +449,113 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, tea.Batch(m.fetchRepo(repo.Path), m.ensureAnimTick())
 		}
 
-	case key.Matches(msg, m.keys.FetchAll):
+	case keymap.FetchAll:
 		m.phase = PhaseFetching
 		m.fetchTarget = ""
 		for _, row := range m.rows {
-			if row.Repo != nil {
+			if row.Repo != nil && !row.Repo.FetchDisabled {
 				m.anim.fetchShimmer[row.Repo.Path] = true
 			}
 		}
 		return m, tea.Batch(m.fetchAllRepos(), m.ensureAnimTick())
 
-	case key.Matches(msg, m.keys.Editor):
+	case keymap.Editor:
 		repo := m.selectedRepo()
 		if repo != nil {
-			return m, m.openEditor(repo.Path)
+			return m, tea.Batch(m.openEditor(repo.Path), m.touch(repo.Path))
 		}
 
-	case key.Matches(msg, m.keys.Open):
+	case keymap.Open:
 		repo := m.selectedRepo()
 		if repo != nil {
-			return m, m.openFinder(repo.Path)
+			return m, tea.Batch(m.openFinder(repo.Path), m.touch(repo.Path))
 		}
 
-	case key.Matches(msg, m.keys.Shell):
+	case keymap.Browse:
+		return m, m.browse(forge.RepoPage)
+
+	case keymap.BrowseBranch:
+		return m, m.browse(forge.BranchPage)
+
+	case keymap.BrowseCommit:
+		return m, m.browse(forge.CommitPage)
+
+	case keymap.BrowseCompare:
+		return m, m.browse(forge.ComparePage)
+
+	case keymap.Shell:
 		repo := m.selectedRepo()
 		if repo != nil {
-			return m, m.openShell(repo.Path)
+			return m, tea.Batch(m.openShell(repo.Path), m.touch(repo.Path))
 		}
 
-	case key.Matches(msg, m.keys.CopyPath):
+	case keymap.CopyPath:
 		repo := m.selectedRepo()
 		if repo != nil {
 			return m, tea.Batch(
 				m.copyToClipboard(repo.Path),
 				m.addToast("Copied path", ToastInfo),
+				m.touch(repo.Path),
 			)
 		}
 
+	case keymap.EditTags:
+		if repo := m.selectedRepo(); repo != nil {
+			return m, m.editTags(repo.Path)
+		}
+
+	case keymap.TagPicker:
+		if len(allTags(m.repos)) == 0 {
+			return m, m.addToast("No tagged repos"+m.keys.hint(keymap.EditTags, "to tag"), ToastInfo)
+		}
+		m.showTags = true
+		m.tagCursor = 0
+
+	case keymap.Pin:
+		if repo := m.selectedRepo(); repo != nil {
+			return m, m.togglePin(repo.Path)
+		}
+
 	// Views
-	case key.Matches(msg, m.keys.ViewAll):
+	case keymap.ViewAll:
 		m.viewFilter = ViewAll
+		m.activeView = -1
 		m.buildRows()
 
-	case key.Matches(msg, m.keys.ViewDirty):
+	case keymap.ViewDirty:
 		m.viewFilter = ViewDirty
+		m.activeView = -1
 		m.buildRows()
 
-	case key.Matches(msg, m.keys.ViewUnpushed):
+	case keymap.ViewAhead:
 		m.viewFilter = ViewUnpushed
+		m.activeView = -1
 		m.buildRows()
 
-	case key.Matches(msg, m.keys.ViewConflicts):
+	case keymap.ViewConflicts:
 		m.viewFilter = ViewConflicts
+		m.activeView = -1
+		m.buildRows()
+
+	case keymap.NextView:
+		if len(m.views) == 0 {
+			return m, m.addToast("No saved views (add views to the config)", ToastInfo)
+		}
+		// Cycle through the saved views, then back to none
+		if m.activeView+1 < len(m.views) {
+			m.selectView(m.activeView + 1)
+		} else {
+			m.activeView = -1
+			m.buildRows()
+		}
+
+	case keymap.ViewPinned:
+		m.viewFilter = ViewPinned
+		m.activeView = -1
 		m.buildRows()
 
 	// V3 sort modes
-	case key.Matches(msg, m.keys.SortDiff):
+	case keymap.SortDiff:
 		if m.sortMode == SortDiff {
 			m.sortMode = SortAlpha
 		} else {
@@ -322,7 +563,7 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		m.buildRows()
 
-	case key.Matches(msg, m.keys.SortChurn):
+	case keymap.SortChurn:
 		if m.sortMode == SortChurn {
 			m.sortMode = SortAlpha
 		} else {
@@ -331,16 +572,80 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.buildRows()
 
 	// V3 detail panel toggle
-	case key.Matches(msg, m.keys.Detail):
+	case keymap.Detail:
 		m.showDetail = !m.showDetail
+		m.detailFocus = false
+
+	case keymap.FocusDetail:
+		m.showDetail = true
+		m.detailFocus = !m.detailFocus
+
+	case keymap.NextTab:
+		m.setDetailTab((m.detailTab + 1) % detailTab(len(detailTabNames)))
+
+	case keymap.PrevTab:
+		m.setDetailTab((m.detailTab + detailTab(len(detailTabNames)) - 1) % detailTab(len(detailTabNames)))
 
-	case key.Matches(msg, m.keys.Help):
+	case keymap.DetailWider:
+		m.detailPct = min(m.detailPct+detailPctStep, maxDetailPct)
+
+	case keymap.DetailNarrower:
+		m.detailPct = max(m.detailPct-detailPctStep, minDetailPct)
+
+	case keymap.Columns:
+		return m, m.cyclePreset()
+
+	case keymap.Help:
 		m.showHelp = !m.showHelp
 	}
 
 	return m, nil
 }
 
+// syncView settles what View reads after an update: the table scrolled to
+// the cursor, the detail panel's scroll in range, at the top for a new
+// selection, and where things are drawn, for the mouse.
+func (m *Model) syncView() {
+	vis := m.visibleRows()
+	if m.cursor >= m.scrollOffset+vis {
+		m.scrollOffset = m.cursor - vis + 1
+	}
+	m.scrollOffset = max(0, min(m.scrollOffset, m.cursor))
+
+	tableWidth, detailWidth := m.paneWidths()
+	repo := m.selectedRepo()
+	switch {
+	case repo == nil:
+		m.detailPath, m.detailScroll = "", 0
+	case repo.Path != m.detailPath:
+		m.detailPath, m.detailScroll = repo.Path, 0
+	case detailWidth > 0:
+		lines := m.detailLines(repo, detailWidth-2)
+		m.detailScroll = max(0, min(m.detailScroll, len(lines)-vis)) // vis rows under the tabs
+	}
+
+	if m.width == 0 {
+		return
+	}
+	m.layout = layout{tableTop: lipgloss.Height(m.renderHeader()) + lipgloss.Height(m.renderSummaryPanel())}
+	m.layout.footerRow = m.layout.tableTop + vis + 2 // under the table and the separator
+	_, m.layout.footer = m.footerParts()
+	if len(m.rows) > 0 {
+		m.layout.tableWidth, m.layout.detailWidth = tableWidth, detailWidth
+		m.layout.cols = m.columns(tableWidth)
+	}
+}
+
+// paneWidths splits the width between the table and the detail panel,
+// which is 0 when hidden.
+func (m *Model) paneWidths() (table, detail int) {
+	if m.showDetail && m.width >= 100 {
+		detail = m.width * m.detailPct / 100
+		return m.width - detail - 1, detail
+	}
+	return m.width, 0
+}
+
 func (m *Model) visibleRows() int {
 	// header(2) + summary(5) + table header(1) + footer(2) = 10
 	avail := m.height - 10